@@ -0,0 +1,91 @@
+package sqlz
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// acquireConn is a [querier] which acquires a connection of pool within timeout
+// before each call, the call itself runs on it with the caller context,
+// see [Options.AcquireTimeout].
+type acquireConn struct {
+	pool    *sql.DB
+	timeout time.Duration
+}
+
+// acquiring returns pool as is, or wrapped by [acquireConn] if
+// [Options.AcquireTimeout] is set.
+func (db *DB) acquiring(pool *sql.DB) querier {
+	if db.base.acquireTimeout <= 0 {
+		return pool
+	}
+	return &acquireConn{pool, db.base.acquireTimeout}
+}
+
+// conn waits for a free connection, bounded by both ctx and the timeout.
+func (a *acquireConn) conn(ctx context.Context) (*sql.Conn, error) {
+	acquireCtx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	conn, err := a.pool.Conn(acquireCtx)
+	if err != nil {
+		return nil, fmt.Errorf("sqlz: acquiring connection: %w", err)
+	}
+	return conn, nil
+}
+
+func (a *acquireConn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	conn, err := a.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// Close blocks until rows are closed, only then the connection is released
+	go conn.Close()
+
+	return rows, nil
+}
+
+func (a *acquireConn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	conn, err := a.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return conn.ExecContext(ctx, query, args...)
+}
+
+// PrepareContext prepares on the pool, as statements prepared on a connection
+// are closed with it; they are not used by [base], see [base.useStmtCache].
+func (a *acquireConn) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return a.pool.PrepareContext(ctx, query)
+}
+
+// BeginTx begins a transaction on an acquired connection, which is released
+// once the transaction ends.
+func (a *acquireConn) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	conn, err := a.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.BeginTx(ctx, opts)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// Close blocks until tx ends, only then the connection is released
+	go conn.Close()
+
+	return tx, nil
+}
@@ -0,0 +1,60 @@
+package sqlz
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDB_AcquireTimeout(t *testing.T) {
+	pool := sql.OpenDB(&countingConnector{})
+	t.Cleanup(func() { pool.Close() })
+	pool.SetMaxOpenConns(1)
+
+	db := New("mock", pool, &Options{
+		Bind:                   BindQuestion,
+		StatementCacheCapacity: 16,
+		AcquireTimeout:         50 * time.Millisecond,
+	})
+
+	t.Run("free pool", func(t *testing.T) {
+		_, err := db.Exec(ctx, "UPDATE user SET name = ? WHERE id = ?", "Alice", 1)
+		require.NoError(t, err)
+
+		var ids []int
+		err = db.Query(ctx, "SELECT id FROM user WHERE id = ?", 1).Scan(&ids)
+		require.NoError(t, err)
+
+		// the connection is released once rows are closed
+		assert.Eventually(t, func() bool { return pool.Stats().InUse == 0 }, time.Second, time.Millisecond)
+	})
+
+	t.Run("saturated pool", func(t *testing.T) {
+		conn, err := pool.Conn(ctx)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		// the caller context outlives the acquire timeout
+		callerCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		start := time.Now()
+		_, err = db.Exec(callerCtx, "UPDATE user SET name = ? WHERE id = ?", "Alice", 1)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, "sqlz: acquiring connection")
+		assert.Less(t, time.Since(start), 10*time.Second)
+
+		err = db.Query(callerCtx, "SELECT id FROM user").Err()
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.NoError(t, callerCtx.Err())
+	})
+
+	t.Run("skips stmt cache", func(t *testing.T) {
+		assert.False(t, db.base.useStmtCache(db.writePool(), []any{1}))
+		assert.True(t, db.base.useStmtCache(pool, []any{1}))
+	})
+}
@@ -3,7 +3,10 @@ package sqlz
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/rfberaldo/sqlz/internal/parser"
@@ -41,84 +44,327 @@ func (c *base) resolveQuery(query string, args []any) (string, []any, error) {
 		return "", nil, fmt.Errorf("sqlz: query cannot be blank")
 	}
 
+	if c.normalizeQueries {
+		query = parser.Normalize(query)
+	}
+
+	if c.stripTrailingSemicolon {
+		query = parser.StripTrailingSemicolon(query)
+	}
+
+	args, _ = stripColumnMap(args) // only meaningful for scanning
+	args, allowed := stripAllowNoWhere(args)
+	if c.requireWhereOnMutations && !allowed && parser.IsMutationWithoutWhere(query) {
+		return "", nil, fmt.Errorf("sqlz: UPDATE or DELETE without WHERE clause, use AllowNoWhere to override")
+	}
+
 	if len(args) == 0 {
 		return query, nil, nil
 	}
 
+	// bound as text before resolving, otherwise they would be taken as named args
+	args = ipsToText(args)
+
 	argType := reflectutil.TypeOfAny(args[0])
 
+	// otherwise unsupported kinds implementing [driver.Valuer], e.g. [UUID] arrays
+	if argType == reflectutil.Invalid && isValuerArg(args[0]) {
+		argType = reflectutil.Primitive
+	}
+
 	if argType == reflectutil.Invalid {
 		panic(fmt.Sprintf("sqlz: unsupported argument type: %T", args[0]))
 	}
 
+	var err error
 	if argType.IsNamed() {
 		if len(args) > 1 {
 			return "", nil, fmt.Errorf("sqlz: too many arguments for named query, want 1 got %d", len(args))
 		}
-		return processNamed(query, args[0], c.config)
+		query, args, err = processNamed(query, args[0], c.config)
+	} else {
+		if c.normalizeTimesToUTC {
+			args = timesToUTC(args)
+		}
+
+		// must be a native query, just parse for possible "IN" clauses
+		query, args, err = parser.ParseInClause(c.bind, query, args)
+	}
+	if err != nil {
+		return "", nil, err
 	}
 
-	// must be a native query, just parse for possible "IN" clauses
-	return parser.ParseInClause(c.bind, query, args)
+	if c.bind == parser.BindNamedAt {
+		args, err = namedAtArgs(query, args)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	return query, args, nil
 }
 
 func (c *base) query(ctx context.Context, db querier, query string, args ...any) *Scanner {
-	query, args, err := c.resolveQuery(query, args)
+	c, args = c.withStructTag(args)
+	rows, columnMap, err := c.queryRows(ctx, db, "sqlz.Query", query, args)
+	if err != nil {
+		return &Scanner{err: err}
+	}
+	return newScanner(rows, c.config).withColumnMap(columnMap)
+}
+
+func (c *base) queryRow(ctx context.Context, db querier, query string, args ...any) *Scanner {
+	c, args = c.withStructTag(args)
+	rows, columnMap, err := c.queryRows(ctx, db, "sqlz.QueryRow", query, args)
 	if err != nil {
 		return &Scanner{err: err}
 	}
+	return newRowScanner(rows, c.config).withColumnMap(columnMap)
+}
+
+// queryRows resolves and executes query within a span named spanName,
+// returning the rows and the [WithColumnMap] marker map, if any.
+func (c *base) queryRows(
+	ctx context.Context, db querier, spanName, query string, args []any,
+) (_ *sql.Rows, _ map[string]string, err error) {
+	args, columnMap := stripColumnMap(args)
+	query, args, err = c.resolveQuery(query, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, endSpan := c.startSpan(ctx, spanName, query)
+	defer func() { endSpan(err) }()
 
-	if c.stmtCache == nil || len(args) == 0 {
+	if !c.useStmtCache(db, args) {
 		rows, err := db.QueryContext(ctx, query, args...)
-		if err != nil {
-			return &Scanner{err: err}
-		}
-		return newScanner(rows, c.config)
+		return rows, columnMap, err
 	}
 
 	stmt, err := c.loadOrPrepare(ctx, db, query)
 	if err != nil {
-		return &Scanner{err: err}
+		return nil, nil, err
 	}
 	rows, err := stmt.QueryContext(ctx, args...)
+	if errors.Is(err, driver.ErrBadConn) {
+		if stmt, err = c.reprepare(ctx, db, query); err != nil {
+			return nil, nil, err
+		}
+		rows, err = stmt.QueryContext(ctx, args...)
+	}
+	return rows, columnMap, err
+}
+
+func (c *base) selectAppend(ctx context.Context, db querier, dest any, query string, args ...any) error {
+	if err := assertSlicePtr(dest); err != nil {
+		return err
+	}
+	return c.query(ctx, db, query, args...).Scan(dest)
+}
+
+func (c *base) selectGroup(
+	ctx context.Context, db querier, query, keyCol string, args ...any,
+) (map[any][]map[string]any, error) {
+	var rows []map[string]any
+	if err := c.query(ctx, db, query, args...).Scan(&rows); err != nil {
+		return nil, err
+	}
+
+	groups := make(map[any][]map[string]any)
+	for _, row := range rows {
+		key, ok := row[keyCol]
+		if !ok {
+			return nil, fmt.Errorf("sqlz: key column not found: '%s'", keyCol)
+		}
+		groups[key] = append(groups[key], row)
+	}
+
+	return groups, nil
+}
+
+// exists scans the single column of the single row of query as a bool,
+// no rows are reported as false.
+func (c *base) exists(ctx context.Context, db querier, query string, args ...any) (bool, error) {
+	var exists bool
+	err := c.queryRow(ctx, db, query, args...).Scan(&exists)
+	if IsNotFound(err) {
+		return false, nil
+	}
+	return exists, err
+}
+
+// getRow scans the columns of the single row of query into dests, positionally.
+func (c *base) getRow(ctx context.Context, db querier, query string, args []any, dests []any) (err error) {
+	c, args = c.withStructTag(args)
+	rows, _, err := c.queryRows(ctx, db, "sqlz.QueryRow", query, args)
 	if err != nil {
-		return &Scanner{err: err}
+		return err
+	}
+	defer func() {
+		if errClose := rows.Close(); errClose != nil && err == nil {
+			err = fmt.Errorf("sqlz/scan: closing rows: %w", errClose)
+		}
+	}()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nextRowError(err)
+		}
+		return sql.ErrNoRows
+	}
+
+	if err := rows.Scan(dests...); err != nil {
+		return fmt.Errorf("sqlz/scan: scanning row: %w", err)
 	}
-	return newScanner(rows, c.config)
+
+	if rows.Next() {
+		return fmt.Errorf("sqlz/scan: expected one row, got more")
+	}
+
+	if err := rows.Err(); err != nil {
+		return nextRowError(err)
+	}
+
+	return nil
 }
 
-func (c *base) queryRow(ctx context.Context, db querier, query string, args ...any) *Scanner {
+func (c *base) exec(ctx context.Context, db querier, query string, args ...any) (sql.Result, error) {
+	c, args = c.withStructTag(args)
+	if chunks := c.batchChunks(query, args); chunks != nil {
+		return c.execChunks(ctx, db, query, chunks)
+	}
+
 	query, args, err := c.resolveQuery(query, args)
 	if err != nil {
-		return &Scanner{err: err}
+		return nil, err
 	}
 
-	if c.stmtCache == nil || len(args) == 0 {
-		rows, err := db.QueryContext(ctx, query, args...)
-		if err != nil {
-			return &Scanner{err: err}
+	return c.execResolved(ctx, db, query, args)
+}
+
+// execInsert executes the insert query and appends the generated ids to dest,
+// see [DB.ExecInsert].
+func (c *base) execInsert(ctx context.Context, db querier, dest any, query string, args ...any) error {
+	insertQuery, hasReturning := parser.StripReturning(query)
+
+	if c.bind == parser.BindDollar && !hasReturning {
+		return fmt.Errorf("sqlz: insert query must have a RETURNING clause to return the generated ids")
+	}
+
+	// e.g. SQLite >= 3.35, whose last insert id is the one of the last row
+	if c.bind == parser.BindDollar || hasReturning && !c.firstInsertId {
+		return c.query(ctx, db, query, args...).Scan(dest)
+	}
+
+	ids, ok := dest.(*[]int64)
+	if !ok {
+		return fmt.Errorf("sqlz: destination must be *[]int64 to scan the last insert id, got %T", dest)
+	}
+
+	result, err := c.exec(ctx, db, insertQuery, args...)
+	if err != nil {
+		return err
+	}
+
+	// ids are only consecutive within a statement, other sessions may insert between chunks
+	if batch, ok := result.(batchResult); ok {
+		for _, result := range batch.results {
+			if err := c.appendInsertIds(ids, result); err != nil {
+				return err
+			}
 		}
-		return newRowScanner(rows, c.config)
+		return nil
 	}
 
-	stmt, err := c.loadOrPrepare(ctx, db, query)
+	return c.appendInsertIds(ids, result)
+}
+
+// appendInsertIds appends the ids of the rows inserted by a single statement,
+// computed from the id of the first one, see [DB.ExecInsert].
+func (c *base) appendInsertIds(ids *[]int64, result sql.Result) error {
+	firstId, err := result.LastInsertId()
 	if err != nil {
-		return &Scanner{err: err}
+		return fmt.Errorf("sqlz: driver cannot return the last insert id: %w", err)
 	}
-	rows, err := stmt.QueryContext(ctx, args...)
+
+	affected, err := result.RowsAffected()
 	if err != nil {
-		return &Scanner{err: err}
+		return fmt.Errorf("sqlz: driver cannot return the rows affected: %w", err)
+	}
+
+	if firstId == 0 && affected > 0 {
+		return fmt.Errorf("sqlz: no id was generated, the table may not have an auto increment column")
+	}
+
+	if affected > 1 && !c.firstInsertId {
+		return fmt.Errorf("sqlz: driver may not report the id of the first of %d inserted rows, add a RETURNING clause", affected)
 	}
-	return newRowScanner(rows, c.config)
+
+	for i := range affected {
+		*ids = append(*ids, firstId+i)
+	}
+
+	return nil
 }
 
-func (c *base) exec(ctx context.Context, db querier, query string, args ...any) (sql.Result, error) {
-	query, args, err := c.resolveQuery(query, args)
+// execMany executes query once per arg set of argsList, see [DB.ExecMany].
+func (c *base) execMany(ctx context.Context, db querier, query string, argsList []any) ([]sql.Result, error) {
+	results := make([]sql.Result, 0, len(argsList))
+	for i, arg := range argsList {
+		args := []any{arg}
+		if list, ok := arg.([]any); ok {
+			args = list
+		}
+
+		result, err := c.exec(ctx, db, query, args...)
+		if err != nil {
+			return results, fmt.Errorf("sqlz: executing arg set %d: %w", i, err)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// insertValues executes the native INSERT query with its single placeholder
+// VALUES row repeated once per element of values, see [DB.InsertValues].
+func (c *base) insertValues(ctx context.Context, db querier, query string, values any) (sql.Result, error) {
+	v := reflect.ValueOf(values)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array || v.Len() == 0 {
+		return nil, fmt.Errorf("sqlz: values must be a non-empty slice, got %T", values)
+	}
+
+	if c.bind == parser.BindNamedAt || c.bind == parser.BindColon {
+		return nil, fmt.Errorf("sqlz: inserting values is not supported with named placeholders")
+	}
+
+	args := make([]any, v.Len())
+	for i := range args {
+		args[i] = v.Index(i).Interface()
+	}
+
+	if reflectutil.TypeOfAny(args[0]).IsNamed() {
+		return nil, fmt.Errorf("sqlz: values must be single column values, got %T, use a named query instead", args[0])
+	}
+
+	if count := parser.CountPlaceholders(c.bind, query); count != 1 {
+		return nil, fmt.Errorf("sqlz: inserting values requires a single placeholder, got %d", count)
+	}
+
+	query, err := expandInsertSyntax(query, len(args))
 	if err != nil {
 		return nil, err
 	}
 
-	if c.stmtCache == nil || len(args) == 0 {
+	return c.exec(ctx, db, parser.Renumber(c.bind, query), args...)
+}
+
+// execResolved executes query as is, which must be already resolved to the native bind.
+func (c *base) execResolved(ctx context.Context, db querier, query string, args []any) (_ sql.Result, err error) {
+	ctx, endSpan := c.startSpan(ctx, "sqlz.Exec", query)
+	defer func() { endSpan(err) }()
+
+	if !c.useStmtCache(db, args) {
 		return db.ExecContext(ctx, query, args...)
 	}
 
@@ -126,7 +372,71 @@ func (c *base) exec(ctx context.Context, db querier, query string, args ...any)
 	if err != nil {
 		return nil, err
 	}
-	return stmt.ExecContext(ctx, args...)
+	result, err := stmt.ExecContext(ctx, args...)
+	if errors.Is(err, driver.ErrBadConn) {
+		if stmt, err = c.reprepare(ctx, db, query); err != nil {
+			return nil, err
+		}
+		result, err = stmt.ExecContext(ctx, args...)
+	}
+	return result, err
+}
+
+// prepareNamed prepares the named query on db, returning the statement and
+// the idents of its placeholders, in order.
+func (c *base) prepareNamed(ctx context.Context, db querier, query string) (*sql.Stmt, []string, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil, fmt.Errorf("sqlz: query cannot be blank")
+	}
+
+	if c.normalizeQueries {
+		query = parser.Normalize(query)
+	}
+
+	if c.stripTrailingSemicolon {
+		query = parser.StripTrailingSemicolon(query)
+	}
+
+	query, idents := parser.Parse(c.bind, query)
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sqlz: preparing stmt: %w", err)
+	}
+
+	return stmt, idents, nil
+}
+
+// withStructTag returns c with the struct tag of the [WithStructTag] marker of
+// args, if any, sharing the statement cache, and args without the marker.
+func (c *base) withStructTag(args []any) (*base, []any) {
+	args, tag := stripStructTag(args)
+	if tag == "" || tag == c.structTag {
+		return c, args
+	}
+
+	cfg := *c.config
+	cfg.structTag = tag
+	return &base{&cfg, c.stmtCache}, args
+}
+
+// startSpan starts a span with [Options.Tracer], if set,
+// the returned func ending it is never nil.
+func (c *base) startSpan(ctx context.Context, name, query string) (context.Context, func(error)) {
+	if c.tracer == nil {
+		return ctx, func(error) {}
+	}
+	if c.traceCaller {
+		ctx = context.WithValue(ctx, callerKey{}, caller())
+	}
+	return c.tracer.StartSpan(ctx, name, query)
+}
+
+// useStmtCache reports whether a query with args runs on a cached statement,
+// queries without args don't need one, and they can't run on [acquireConn].
+func (c *base) useStmtCache(db querier, args []any) bool {
+	_, acquiring := db.(*acquireConn)
+	return c.stmtCache != nil && len(args) > 0 && !acquiring
 }
 
 func (c *base) loadOrPrepare(ctx context.Context, db querier, query string) (*sql.Stmt, error) {
@@ -134,19 +444,37 @@ func (c *base) loadOrPrepare(ctx context.Context, db querier, query string) (*sq
 		panic("sqlz: stmt cache is not enabled")
 	}
 
-	stmt, ok := c.stmtCache.Get(query)
+	key := c.stmtKey(db, query)
+	stmt, ok := c.stmtCache.Get(key)
 	if !ok {
 		var err error
 		stmt, err = db.PrepareContext(ctx, query)
 		if err != nil {
 			return nil, fmt.Errorf("sqlz: preparing stmt: %w", err)
 		}
-		c.stmtCache.Put(query, stmt)
+		c.stmtCache.Put(key, stmt)
 	}
 
 	return stmt.(*sql.Stmt), nil
 }
 
+// reprepare evicts the cached statement of query, which failed with
+// [driver.ErrBadConn] even after [sql.Stmt] own retries, e.g. after a
+// database restart, and prepares it again.
+func (c *base) reprepare(ctx context.Context, db querier, query string) (*sql.Stmt, error) {
+	c.stmtCache.Remove(c.stmtKey(db, query))
+	return c.loadOrPrepare(ctx, db, query)
+}
+
+// stmtKey returns the cache key of query on db,
+// statements are bound to the pool they were prepared on.
+func (c *base) stmtKey(db querier, query string) string {
+	if pool, ok := db.(*sql.DB); ok && c.readWriteRouter != nil {
+		return fmt.Sprintf("%p %s", pool, query)
+	}
+	return query
+}
+
 func (c *base) clearStmtCache() {
 	if c.stmtCache == nil {
 		return
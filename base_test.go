@@ -5,12 +5,15 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
+	"net"
+	"net/netip"
 	"strings"
 	"testing"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/rfberaldo/sqlz/internal/parser"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -479,6 +482,30 @@ func TestBase_exec(t *testing.T) {
 			require.NoError(t, err)
 			assert.Equal(t, len(args), int(rows))
 		})
+
+		t.Run("named batch insert should keep value expressions per row", func(t *testing.T) {
+			args := []map[string]any{
+				{"id": 1001, "name": "ALICE", "age": 18},
+				{"id": 1002, "name": "Rob", "age": nil},
+			}
+			q := th.fmt(`INSERT INTO %s (id, name, age) VALUES (:id, LOWER(:name), COALESCE(:age, 0))`)
+			re, err := base.exec(ctx, conn.db, q, args)
+			require.NoError(t, err)
+
+			rows, err := re.RowsAffected()
+			require.NoError(t, err)
+			assert.Equal(t, len(args), int(rows))
+
+			type Person struct {
+				Name string
+				Age  int
+			}
+			var got []Person
+			q = th.fmt(`SELECT name, age FROM %s WHERE id IN (?) ORDER BY id`)
+			err = base.query(ctx, conn.db, q, []int{1001, 1002}).Scan(&got)
+			require.NoError(t, err)
+			assert.Equal(t, []Person{{"alice", 18}, {"rob", 0}}, got)
+		})
 	})
 }
 
@@ -572,6 +599,368 @@ func TestBase_nonEnglishCharacters(t *testing.T) {
 	})
 }
 
+func TestBase_normalizeQueries(t *testing.T) {
+	base := newBase(&config{bind: parser.BindQuestion, normalizeQueries: true})
+
+	query1 := "SELECT * FROM user WHERE id = ? AND name = 'Alice  Doe'"
+	query2 := `
+		SELECT *
+		FROM user
+		WHERE id = ?
+			AND name = 'Alice  Doe'`
+
+	got1, args1, err := base.resolveQuery(query1, []any{1})
+	require.NoError(t, err)
+	got2, args2, err := base.resolveQuery(query2, []any{1})
+	require.NoError(t, err)
+
+	assert.Equal(t, query1, got1)
+	assert.Equal(t, got1, got2)
+	assert.Equal(t, args1, args2)
+
+	// same output means same statement cache key
+	base.stmtCache.Put(got1, &sql.Stmt{})
+	_, ok := base.stmtCache.Get(got2)
+	assert.True(t, ok)
+}
+
+func TestBase_stripTrailingSemicolon(t *testing.T) {
+	base := newBase(&config{bind: parser.BindDollar, stripTrailingSemicolon: true})
+
+	query, args, err := base.resolveQuery("SELECT * FROM user WHERE id = :id;", []any{map[string]any{"id": 1}})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM user WHERE id = $1", query)
+	assert.Equal(t, []any{1}, args)
+
+	query, _, err = base.resolveQuery("SELECT * FROM user WHERE name = ';'", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM user WHERE name = ';'", query)
+
+	base = newBase(&config{bind: parser.BindDollar})
+	query, _, err = base.resolveQuery("SELECT 1;", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT 1;", query)
+}
+
+func TestBase_namedAt(t *testing.T) {
+	base := newBase(&config{bind: parser.BindNamedAt})
+
+	t.Run("named", func(t *testing.T) {
+		arg := map[string]any{"id": 1, "ids": []int{2, 3}}
+		query, args, err := base.resolveQuery("SELECT * FROM user WHERE id = :id OR parent_id = :id OR id IN (:ids)", []any{arg})
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM user WHERE id = @id OR parent_id = @id OR id IN (@ids_1,@ids_2)", query)
+		assert.Equal(t, []any{sql.Named("id", 1), sql.Named("ids_1", 2), sql.Named("ids_2", 3)}, args)
+	})
+
+	t.Run("native", func(t *testing.T) {
+		query, args, err := base.resolveQuery("SELECT * FROM user WHERE name = @name AND id IN (@ids)", []any{"Alice", []int{2, 3}})
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM user WHERE name = @name AND id IN (@ids_1,@ids_2)", query)
+		assert.Equal(t, []any{sql.Named("name", "Alice"), sql.Named("ids_1", 2), sql.Named("ids_2", 3)}, args)
+
+		_, _, err = base.resolveQuery("SELECT * FROM user WHERE name = @name", []any{"Alice", "Bob"})
+		assert.ErrorContains(t, err, "arguments mismatch binding named placeholders: placeholders 1 arguments 2")
+	})
+
+	t.Run("batch insert", func(t *testing.T) {
+		arg := []map[string]any{{"name": "Alice"}, {"name": "Bob"}}
+		query, args, err := base.resolveQuery("INSERT INTO user (name) VALUES (:name)", []any{arg})
+		require.NoError(t, err)
+		assert.Equal(t, "INSERT INTO user (name) VALUES (@p1),(@p2)", query)
+		assert.Equal(t, []any{sql.Named("p1", "Alice"), sql.Named("p2", "Bob")}, args)
+	})
+}
+
+func TestBase_resolveQuery_requireWhereOnMutations(t *testing.T) {
+	base := newBase(&config{bind: parser.BindQuestion, requireWhereOnMutations: true})
+
+	_, _, err := base.resolveQuery("DELETE FROM user", nil)
+	assert.ErrorContains(t, err, "without WHERE clause")
+
+	_, _, err = base.resolveQuery("UPDATE user SET active = ?", []any{false})
+	assert.ErrorContains(t, err, "without WHERE clause")
+
+	query, args, err := base.resolveQuery("UPDATE user SET active = ?", []any{false, AllowNoWhere()})
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE user SET active = ?", query)
+	assert.Equal(t, []any{false}, args)
+
+	query, args, err = base.resolveQuery("DELETE FROM user", []any{AllowNoWhere()})
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM user", query)
+	assert.Empty(t, args)
+
+	_, _, err = base.resolveQuery("DELETE FROM user WHERE id = ?", []any{1})
+	assert.NoError(t, err)
+
+	// marker is stripped even if the option is disabled
+	base = newBase(&config{bind: parser.BindQuestion})
+	_, args, err = base.resolveQuery("DELETE FROM user WHERE id = ?", []any{AllowNoWhere(), 1})
+	require.NoError(t, err)
+	assert.Equal(t, []any{1}, args)
+}
+
+func TestBase_jsonOption(t *testing.T) {
+	type Meta struct {
+		Tags  []string `json:"tags"`
+		Score int      `json:"score"`
+	}
+
+	type Doc struct {
+		Id   int
+		Meta Meta `db:"meta,json"`
+	}
+
+	runConn(t, func(t *testing.T, conn *Conn) {
+		base := newBase(&config{bind: conn.bind})
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		jsonType := "JSON"
+		if conn.bind == parser.BindDollar {
+			jsonType = "JSONB"
+		}
+		_, err := conn.db.Exec(th.fmt(`CREATE TABLE IF NOT EXISTS %s (id INT PRIMARY KEY, meta ` + jsonType + `)`))
+		require.NoError(t, err)
+
+		docs := []Doc{
+			{1, Meta{[]string{"a", "b"}, 5}},
+			{2, Meta{Score: 1}},
+		}
+		_, err = base.exec(ctx, conn.db, th.fmt(`INSERT INTO %s (id, meta) VALUES (:id, :meta)`), docs)
+		require.NoError(t, err)
+
+		var got []Doc
+		err = base.query(ctx, conn.db, th.fmt(`SELECT id, meta FROM %s ORDER BY id`)).Scan(&got)
+		require.NoError(t, err)
+		assert.Equal(t, docs, got)
+	})
+}
+
+func TestBase_resolveQuery_columnMap(t *testing.T) {
+	base := newBase(&config{bind: parser.BindQuestion})
+
+	columnMap := WithColumnMap(map[string]string{"n": "name"})
+	query, args, err := base.resolveQuery("SELECT name AS n FROM user WHERE id = ?", []any{1, columnMap})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT name AS n FROM user WHERE id = ?", query)
+	assert.Equal(t, []any{1}, args)
+
+	// not mistaken for a named arg
+	_, args, err = base.resolveQuery("SELECT name AS n FROM user", []any{columnMap})
+	require.NoError(t, err)
+	assert.Empty(t, args)
+}
+
+func TestBase_resolveQuery_ip(t *testing.T) {
+	base := newBase(&config{bind: parser.BindDollar})
+
+	addr := netip.MustParseAddr("192.168.0.1")
+	query, args, err := base.resolveQuery("SELECT * FROM host WHERE addr = $1 AND id = $2", []any{addr, 1})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM host WHERE addr = $1 AND id = $2", query)
+	assert.Equal(t, []any{"192.168.0.1", 1}, args)
+}
+
+func TestBase_inet(t *testing.T) {
+	type Host struct {
+		Id      int
+		Addr    netip.Addr
+		Network netip.Prefix
+		Legacy  net.IP
+	}
+
+	runConn(t, func(t *testing.T, conn *Conn) {
+		if conn.bind != parser.BindDollar {
+			t.Skip("INET is a Postgres type")
+		}
+
+		base := newBase(&config{bind: conn.bind})
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := conn.db.Exec(th.fmt(`CREATE TABLE IF NOT EXISTS %s (id INT PRIMARY KEY, addr INET, network CIDR, legacy INET)`))
+		require.NoError(t, err)
+
+		hosts := []Host{
+			{1, netip.MustParseAddr("192.168.0.1"), netip.MustParsePrefix("10.0.0.0/8"), net.ParseIP("2001:db8::1")},
+			{2, netip.Addr{}, netip.Prefix{}, nil},
+		}
+		_, err = base.exec(ctx, conn.db, th.fmt(`INSERT INTO %s (id, addr, network, legacy) VALUES (:id, :addr, :network, :legacy)`), hosts)
+		require.NoError(t, err)
+
+		var got []Host
+		err = base.query(ctx, conn.db, th.fmt(`SELECT id, addr, network, legacy FROM %s ORDER BY id`)).Scan(&got)
+		require.NoError(t, err)
+		assert.Equal(t, hosts, got)
+
+		var addr netip.Addr
+		err = base.queryRow(ctx, conn.db, th.fmt(`SELECT addr FROM %s WHERE addr = ?`), hosts[0].Addr).Scan(&addr)
+		require.NoError(t, err)
+		assert.Equal(t, hosts[0].Addr, addr)
+	})
+}
+
+func TestBase_query_columnMap(t *testing.T) {
+	type User struct {
+		Id   int
+		Name string
+	}
+
+	runConn(t, func(t *testing.T, conn *Conn) {
+		base := newBase(&config{bind: conn.bind})
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := conn.db.Exec(th.fmt(`CREATE TABLE IF NOT EXISTS %s (id INT PRIMARY KEY, full_name VARCHAR(255))`))
+		require.NoError(t, err)
+
+		_, err = conn.db.Exec(th.fmt(`INSERT INTO %s (id, full_name) VALUES (1, 'Alice')`))
+		require.NoError(t, err)
+
+		columnMap := WithColumnMap(map[string]string{"user_id": "id", "full_name": "Name"})
+		query := th.fmt(rebind(conn.bind, `SELECT id AS user_id, full_name FROM %s WHERE id = ?`))
+
+		var users []User
+		err = base.query(ctx, conn.db, query, columnMap, 1).Scan(&users)
+		require.NoError(t, err)
+		assert.Equal(t, []User{{1, "Alice"}}, users)
+
+		var user User
+		err = base.queryRow(ctx, conn.db, query, 1, columnMap).Scan(&user)
+		require.NoError(t, err)
+		assert.Equal(t, User{1, "Alice"}, user)
+
+		err = base.query(ctx, conn.db, query, 1).Scan(&users)
+		assert.ErrorContains(t, err, "struct field not found")
+	})
+}
+
+func TestBase_withStructTag(t *testing.T) {
+	type User struct {
+		Id   int    `json:"user_id"`
+		Name string `json:"full_name" db:"name"`
+	}
+
+	base := newBase(&config{bind: parser.BindQuestion, stmtCacheCapacity: 4})
+	query := "UPDATE user SET full_name = :full_name WHERE id = :user_id"
+
+	c, args := base.withStructTag([]any{User{1, "Alice"}, WithStructTag("json")})
+	assert.Equal(t, "json", c.structTag)
+	assert.Same(t, base.stmtCache, c.stmtCache)
+	assert.Equal(t, "db", base.structTag)
+
+	compiled, args, err := c.resolveQuery(query, args)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE user SET full_name = ? WHERE id = ?", compiled)
+	assert.Equal(t, []any{"Alice", 1}, args)
+
+	c, args = base.withStructTag([]any{User{1, "Alice"}})
+	assert.Same(t, base, c)
+	_, _, err = c.resolveQuery(query, args)
+	assert.ErrorContains(t, err, "no value for ':full_name'")
+
+	c, _ = base.withStructTag([]any{WithStructTag("db")})
+	assert.Same(t, base, c)
+}
+
+func TestBase_query_withStructTag(t *testing.T) {
+	type User struct {
+		Id   int    `json:"user_id"`
+		Name string `json:"full_name"`
+	}
+
+	runConn(t, func(t *testing.T, conn *Conn) {
+		base := newBase(&config{bind: conn.bind})
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := conn.db.Exec(th.fmt(`CREATE TABLE IF NOT EXISTS %s (user_id INT PRIMARY KEY, full_name VARCHAR(255))`))
+		require.NoError(t, err)
+
+		tag := WithStructTag("json")
+		_, err = base.exec(ctx, conn.db, th.fmt(`INSERT INTO %s (user_id, full_name) VALUES (:user_id, :full_name)`), User{1, "Alice"}, tag)
+		require.NoError(t, err)
+
+		var users []User
+		err = base.query(ctx, conn.db, th.fmt(`SELECT user_id, full_name FROM %s WHERE user_id = :user_id`), tag, User{Id: 1}).Scan(&users)
+		require.NoError(t, err)
+		assert.Equal(t, []User{{1, "Alice"}}, users)
+
+		var name string
+		err = base.getRow(ctx, conn.db, th.fmt(`SELECT full_name FROM %s WHERE user_id = :user_id`), []any{User{Id: 1}, tag}, []any{&name})
+		require.NoError(t, err)
+		assert.Equal(t, "Alice", name)
+
+		err = base.query(ctx, conn.db, th.fmt(`SELECT user_id, full_name FROM %s`)).Scan(&users)
+		assert.ErrorContains(t, err, "struct field not found")
+	})
+}
+
+func TestBase_requireWhereOnMutations(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		base := newBase(&config{bind: conn.bind, requireWhereOnMutations: true})
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := conn.db.Exec(th.fmt(`CREATE TABLE IF NOT EXISTS %s (id INT PRIMARY KEY)`))
+		require.NoError(t, err)
+
+		_, err = conn.db.Exec(th.fmt(`INSERT INTO %s (id) VALUES (?),(?),(?)`), 1, 2, 3)
+		require.NoError(t, err)
+
+		_, err = base.exec(ctx, conn.db, th.fmt("DELETE FROM %s"))
+		assert.ErrorContains(t, err, "without WHERE clause")
+
+		re, err := base.exec(ctx, conn.db, th.fmt("DELETE FROM %s WHERE id = ?"), 1)
+		require.NoError(t, err)
+		rows, err := re.RowsAffected()
+		require.NoError(t, err)
+		assert.Equal(t, 1, int(rows))
+
+		re, err = base.exec(ctx, conn.db, th.fmt("DELETE FROM %s"), AllowNoWhere())
+		require.NoError(t, err)
+		rows, err = re.RowsAffected()
+		require.NoError(t, err)
+		assert.Equal(t, 2, int(rows))
+	})
+}
+
+func TestBase_normalizeTimesToUTC(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		base := newBase(&config{bind: conn.bind, normalizeTimesToUTC: true})
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := conn.db.Exec(th.fmt(`
+			CREATE TABLE IF NOT EXISTS %s (
+				id INT PRIMARY KEY,
+				created_at TIMESTAMP NULL
+			)`,
+		))
+		require.NoError(t, err)
+
+		type Event struct {
+			Id        int
+			CreatedAt time.Time
+		}
+
+		loc := time.FixedZone("UTC-3", -3*60*60)
+		ts := time.Date(2025, 9, 29, 12, 0, 0, 0, loc)
+
+		_, err = base.exec(ctx, conn.db, th.fmt("INSERT INTO %s (id, created_at) VALUES (:id, :created_at)"), Event{1, ts})
+		require.NoError(t, err)
+
+		_, err = base.exec(ctx, conn.db, th.fmt("INSERT INTO %s (id, created_at) VALUES (?, ?)"), 2, ts)
+		require.NoError(t, err)
+
+		var events []Event
+		err = base.query(ctx, conn.db, th.fmt("SELECT * FROM %s ORDER BY id")).Scan(&events)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+
+		for _, event := range events {
+			assert.Equal(t, time.UTC, event.CreatedAt.Location())
+			assert.Equal(t, ts.UTC(), event.CreatedAt)
+		}
+	})
+}
+
 type Email string
 
 // Value implements [driver.Valuer].
@@ -582,6 +971,66 @@ func (p Email) Value() (driver.Value, error) {
 	return driver.Value(string(p)), nil
 }
 
+func TestBase_zeroTimeAsNull(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		base := newBase(&config{bind: conn.bind, zeroTimeAsNull: true})
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := conn.db.Exec(th.fmt(`
+			CREATE TABLE IF NOT EXISTS %s (
+				id INT PRIMARY KEY,
+				deleted_at TIMESTAMP NULL
+			)`,
+		))
+		require.NoError(t, err)
+
+		type Event struct {
+			Id        int
+			DeletedAt time.Time
+		}
+
+		ts := time.Date(2025, 9, 29, 12, 0, 0, 0, time.UTC)
+		events := []Event{{1, time.Time{}}, {2, ts}}
+		_, err = base.exec(ctx, conn.db, th.fmt("INSERT INTO %s (id, deleted_at) VALUES (:id, :deleted_at)"), events)
+		require.NoError(t, err)
+
+		var deletedAt []sql.NullTime
+		err = base.query(ctx, conn.db, th.fmt("SELECT deleted_at FROM %s ORDER BY id")).Scan(&deletedAt)
+		require.NoError(t, err)
+		require.Len(t, deletedAt, 2)
+		assert.False(t, deletedAt[0].Valid)
+		assert.True(t, deletedAt[1].Valid)
+		assert.True(t, ts.Equal(deletedAt[1].Time))
+	})
+}
+
+func TestBase_valuerEnum(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		base := newBase(&config{bind: conn.bind})
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := conn.db.Exec(th.fmt(`CREATE TABLE IF NOT EXISTS %s (id INT PRIMARY KEY, status INT)`))
+		require.NoError(t, err)
+
+		type Order struct {
+			Id     int
+			Status orderStatus
+		}
+
+		query := th.fmt("INSERT INTO %s (id, status) VALUES (:id, :status)")
+		_, err = base.exec(ctx, conn.db, query, []Order{{1, orderPending}, {2, orderShipped}})
+		require.NoError(t, err)
+
+		_, err = base.exec(ctx, conn.db, query, Order{3, 42})
+		assert.ErrorContains(t, err, "unknown order status: 42")
+
+		var statuses []orderStatus
+		err = base.query(ctx, conn.db, th.fmt("SELECT status FROM %s WHERE status IN (?) ORDER BY id"), []orderStatus{orderPending, orderShipped}).Scan(&statuses)
+		require.NoError(t, err)
+		assert.Equal(t, []orderStatus{orderPending, orderShipped}, statuses)
+	})
+}
+
 func TestBase_valuerInterface(t *testing.T) {
 	runConn(t, func(t *testing.T, conn *Conn) {
 		base := newBase(&config{bind: conn.bind})
@@ -644,3 +1093,77 @@ func BenchmarkBatchInsertStruct(b *testing.B) {
 		require.NoError(b, err)
 	}
 }
+
+func TestBase_insertValues(t *testing.T) {
+	pool := sql.OpenDB(&countingConnector{})
+	t.Cleanup(func() { pool.Close() })
+
+	newDB := func(bind parser.Bind) (*DB, *fakeTracer) {
+		tracer := &fakeTracer{}
+		return New("mock", pool, &Options{Bind: bind, Tracer: tracer}), tracer
+	}
+
+	t.Run("spreads rows", func(t *testing.T) {
+		db, tracer := newDB(parser.BindDollar)
+		_, err := db.InsertValues(ctx, "INSERT INTO tag (id, name) VALUES (DEFAULT, $1) ON CONFLICT DO NOTHING", []string{"a", "b", "c"})
+		require.NoError(t, err)
+		require.Len(t, tracer.spans, 1)
+		assert.Equal(t,
+			"INSERT INTO tag (id, name) VALUES (DEFAULT, $1),(DEFAULT, $2),(DEFAULT, $3) ON CONFLICT DO NOTHING",
+			tracer.spans[0].query,
+		)
+
+		db, tracer = newDB(parser.BindQuestion)
+		_, err = db.InsertValues(ctx, "INSERT INTO num (n) VALUES (?)", [2]int{1, 2})
+		require.NoError(t, err)
+		assert.Equal(t, "INSERT INTO num (n) VALUES (?),(?)", tracer.spans[0].query)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		db, _ := newDB(parser.BindQuestion)
+		_, err := db.InsertValues(ctx, "INSERT INTO num (n) VALUES (?)", []int{})
+		assert.ErrorContains(t, err, "values must be a non-empty slice, got []int")
+
+		_, err = db.InsertValues(ctx, "INSERT INTO num (n) VALUES (?)", 1)
+		assert.ErrorContains(t, err, "values must be a non-empty slice, got int")
+
+		_, err = db.InsertValues(ctx, "INSERT INTO num (n, m) VALUES (?, ?)", []int{1})
+		assert.ErrorContains(t, err, "requires a single placeholder, got 2")
+
+		_, err = db.InsertValues(ctx, "INSERT INTO num (n) SELECT ?", []int{1})
+		assert.ErrorContains(t, err, "only supported in INSERT query with 'VALUES' clause")
+
+		_, err = db.InsertValues(ctx, "INSERT INTO num (n) VALUES (:n)", []map[string]any{{"n": 1}})
+		assert.ErrorContains(t, err, "values must be single column values, got map[string]interface {}")
+
+		db, _ = newDB(parser.BindNamedAt)
+		_, err = db.InsertValues(ctx, "INSERT INTO num (n) VALUES (@n)", []int{1})
+		assert.ErrorContains(t, err, "not supported with named placeholders")
+	})
+}
+
+func TestDB_InsertValues(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, nil)
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := db.Exec(ctx, th.fmt(`CREATE TABLE %s (n INT PRIMARY KEY)`))
+		require.NoError(t, err)
+
+		result, err := db.InsertValues(ctx, th.fmt(`INSERT INTO %s (n) VALUES (?)`), []int{1, 2, 3})
+		require.NoError(t, err)
+		affected, err := result.RowsAffected()
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), affected)
+
+		tx, err := db.Begin(ctx)
+		require.NoError(t, err)
+		_, err = tx.InsertValues(ctx, th.fmt(`INSERT INTO %s (n) VALUES (?)`), []int{4})
+		require.NoError(t, err)
+		require.NoError(t, tx.Commit())
+
+		var got []int
+		require.NoError(t, db.Query(ctx, th.fmt(`SELECT n FROM %s ORDER BY n`)).Scan(&got))
+		assert.Equal(t, []int{1, 2, 3, 4}, got)
+	})
+}
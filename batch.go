@@ -0,0 +1,158 @@
+package sqlz
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/rfberaldo/sqlz/internal/parser"
+	"github.com/rfberaldo/sqlz/internal/reflectutil"
+)
+
+// executor is implemented by [DB] and [Tx].
+type executor interface {
+	baseConn() (*base, querier)
+}
+
+func (db *DB) baseConn() (*base, querier) { return db.base, db.writePool() }
+func (tx *Tx) baseConn() (*base, querier) { return tx.base, tx.conn }
+
+// BatchInserter inserts slices of T, a struct or a pointer to one, into a table
+// with a single INSERT statement. The columns and the field of each column are
+// resolved once, on the first insert, sparing the per-row reflection of a named
+// batch insert, which helps repeated bulk loads. Columns are mapped the same way
+// as [DB.ReplaceAll]. It's safe for concurrent use by multiple goroutines.
+//
+//	inserter := sqlz.NewBatchInserter[User]("user")
+//	_, err := inserter.Insert(ctx, db, users)
+//
+// The plan is bound to the [Options] of the first [DB] or [Tx] it's used with.
+type BatchInserter[T any] struct {
+	table string
+	once  sync.Once
+	plan  *batchPlan
+	err   error
+}
+
+// batchPlan holds the precomputed columns of a [BatchInserter].
+type batchPlan struct {
+	columns []string
+	indexes [][]int // struct field index of each column
+	isJSON  []bool  // whether each column is tagged with [reflectutil.JSONOption]
+	named   *namedQuery
+}
+
+// NewBatchInserter returns a [BatchInserter] of T into table.
+// The table name is used as is, it must not come from user input.
+func NewBatchInserter[T any](table string) *BatchInserter[T] {
+	return &BatchInserter[T]{table: table}
+}
+
+// Insert inserts rows using db, which must be a [*DB] or a [*Tx].
+// Every row is bound in the same statement, mind the placeholder limit of
+// the database for large slices, splitting them in chunks.
+func (b *BatchInserter[T]) Insert(ctx context.Context, db executor, rows []T) (sql.Result, error) {
+	base, conn := db.baseConn()
+
+	b.once.Do(func() {
+		b.plan, b.err = newBatchPlan(base, reflect.TypeFor[T]())
+	})
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("sqlz: no rows to insert into %s", b.table)
+	}
+
+	args, err := b.plan.args(reflect.ValueOf(rows))
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := b.plan.query(b.table, len(rows))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := base.execResolved(ctx, conn, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("sqlz: inserting rows into %s: %w", b.table, err)
+	}
+	return result, nil
+}
+
+// newBatchPlan resolves the columns of structType with the config of base.
+func newBatchPlan(base *base, structType reflect.Type) (*batchPlan, error) {
+	if reflectutil.TypeOf(structType) != reflectutil.Struct {
+		return nil, fmt.Errorf("sqlz: batch inserter type must be a struct, got %s", structType)
+	}
+
+	structType = reflectutil.Deref(structType)
+	columns, _, err := base.insertColumns(structType)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("sqlz: no columns found in %s", structType)
+	}
+
+	indexByColumn := reflectutil.StructFieldMap(structType, base.structTag, "_", base.fieldNameTransformer)
+	plan := &batchPlan{
+		columns: columns,
+		indexes: make([][]int, len(columns)),
+		isJSON:  make([]bool, len(columns)),
+		named:   &namedQuery{config: base.config},
+	}
+	for i, column := range columns {
+		index := indexByColumn[column]
+		plan.indexes[i] = index
+		field := structType.FieldByIndex(index)
+		plan.isJSON[i] = reflectutil.HasTagOption(field, base.structTag, reflectutil.JSONOption)
+	}
+
+	return plan, nil
+}
+
+// args returns the args of every row of rows, a slice of the struct of the plan.
+func (p *batchPlan) args(rows reflect.Value) ([]any, error) {
+	args := make([]any, 0, rows.Len()*len(p.columns))
+	for i := range rows.Len() {
+		row := reflect.Indirect(rows.Index(i))
+		if !row.IsValid() {
+			return nil, fmt.Errorf("sqlz: row %d is nil pointer", i)
+		}
+
+		for j, index := range p.indexes {
+			v, err := row.FieldByIndexErr(index)
+			if err != nil {
+				return nil, fmt.Errorf("sqlz: row %d: field is nil pointer: '%s'", i, p.columns[j])
+			}
+			arg, err := p.named.fieldArg(v, p.isJSON[j])
+			if err != nil {
+				return nil, fmt.Errorf("sqlz: row %d: field '%s': %w", i, p.columns[j], err)
+			}
+			args = append(args, arg)
+		}
+	}
+
+	return args, nil
+}
+
+// query returns the INSERT query of count rows, in the bind of the plan.
+func (p *batchPlan) query(table string, count int) (string, error) {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (:%s)",
+		table, strings.Join(p.columns, ", "), strings.Join(p.columns, ", :"),
+	)
+
+	query, err := expandInsertSyntax(query, count)
+	if err != nil {
+		return "", err
+	}
+
+	return parser.ParseQuery(batchBind(p.named.bind), query), nil
+}
@@ -0,0 +1,160 @@
+package sqlz
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rfberaldo/sqlz/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchInserter(t *testing.T) {
+	type Country struct {
+		Code      string
+		Name      string
+		CreatedAt time.Time `db:"created_at,readonly"`
+	}
+
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, nil)
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := db.Exec(ctx, th.fmt(`CREATE TABLE %s (code VARCHAR(2) PRIMARY KEY, name VARCHAR(100) NOT NULL)`))
+		require.NoError(t, err)
+
+		inserter := NewBatchInserter[Country](th.tableName)
+
+		result, err := inserter.Insert(ctx, db, []Country{{Code: "br", Name: "Brazil"}, {Code: "pt", Name: "Portugal"}})
+		require.NoError(t, err)
+		affected, err := result.RowsAffected()
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), affected)
+
+		tx, err := db.Begin(ctx)
+		require.NoError(t, err)
+		_, err = inserter.Insert(ctx, tx, []Country{{Code: "de", Name: "Germany"}})
+		require.NoError(t, err)
+		require.NoError(t, tx.Commit())
+
+		var got []string
+		err = db.Query(ctx, th.fmt(`SELECT name FROM %s ORDER BY code`)).Scan(&got)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Brazil", "Germany", "Portugal"}, got)
+	})
+}
+
+func TestBatchInserter_mock(t *testing.T) {
+	pool := sql.OpenDB(&countingConnector{})
+	t.Cleanup(func() { pool.Close() })
+	db := New("mock", pool, &Options{Bind: BindQuestion})
+
+	t.Run("inserts", func(t *testing.T) {
+		type Country struct{ Code, Name string }
+		inserter := NewBatchInserter[*Country]("country")
+		_, err := inserter.Insert(ctx, db, []*Country{{"br", "Brazil"}, {"pt", "Portugal"}})
+		require.NoError(t, err)
+
+		_, err = inserter.Insert(ctx, db, []*Country{{"br", "Brazil"}, nil})
+		assert.ErrorContains(t, err, "row 1 is nil pointer")
+
+		_, err = inserter.Insert(ctx, db, nil)
+		assert.ErrorContains(t, err, "no rows to insert into country")
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		_, err := NewBatchInserter[string]("country").Insert(ctx, db, []string{"br"})
+		assert.ErrorContains(t, err, "batch inserter type must be a struct, got string")
+
+		_, err = NewBatchInserter[struct{ code string }]("country").Insert(ctx, db, []struct{ code string }{{"br"}})
+		assert.ErrorContains(t, err, "no columns found")
+	})
+}
+
+func TestBatchPlan(t *testing.T) {
+	type Meta struct {
+		Tags []string
+	}
+
+	type Audit struct {
+		CreatedBy string
+	}
+
+	type Doc struct {
+		Id    int `db:"id,readonly"`
+		Title string
+		Meta  Meta `db:"meta,json"`
+		Audit *Audit
+	}
+
+	newPlan := func(bind parser.Bind) *batchPlan {
+		plan, err := newBatchPlan(newBase(&config{bind: bind}), reflect.TypeFor[Doc]())
+		require.NoError(t, err)
+		return plan
+	}
+
+	t.Run("query", func(t *testing.T) {
+		query, err := newPlan(parser.BindQuestion).query("doc", 2)
+		require.NoError(t, err)
+		assert.Equal(t, "INSERT INTO doc (title, meta, audit_created_by) VALUES (?, ?, ?),(?, ?, ?)", query)
+
+		query, err = newPlan(parser.BindDollar).query("doc", 2)
+		require.NoError(t, err)
+		assert.Equal(t, "INSERT INTO doc (title, meta, audit_created_by) VALUES ($1, $2, $3),($4, $5, $6)", query)
+
+		query, err = newPlan(parser.BindNamedAt).query("doc", 2)
+		require.NoError(t, err)
+		assert.Equal(t, "INSERT INTO doc (title, meta, audit_created_by) VALUES (@p1, @p2, @p3),(@p4, @p5, @p6)", query)
+	})
+
+	t.Run("args", func(t *testing.T) {
+		rows := []Doc{
+			{1, "a", Meta{[]string{"x"}}, &Audit{"alice"}},
+			{2, "b", Meta{}, &Audit{"bob"}},
+		}
+		args, err := newPlan(parser.BindQuestion).args(reflect.ValueOf(rows))
+		require.NoError(t, err)
+		assert.Equal(t, []any{"a", `{"Tags":["x"]}`, "alice", "b", `{"Tags":null}`, "bob"}, args)
+
+		_, err = newPlan(parser.BindQuestion).args(reflect.ValueOf([]Doc{{Title: "c"}}))
+		assert.ErrorContains(t, err, "row 0: field is nil pointer: 'audit_created_by'")
+	})
+}
+
+// BenchmarkBatchInserter compares [BatchInserter] against a named batch insert.
+func BenchmarkBatchInserter(b *testing.B) {
+	type user struct {
+		Id       int
+		Username string
+		Email    string
+		Password string
+		Age      int
+	}
+
+	var rows []user
+	for i := range 1000 {
+		rows = append(rows, user{i + 1, "user123", "user@example.com", "abc123", 18})
+	}
+
+	pool := sql.OpenDB(&countingConnector{})
+	b.Cleanup(func() { pool.Close() })
+	db := New("mock", pool, &Options{Bind: BindDollar})
+
+	b.Run("BatchInserter", func(b *testing.B) {
+		inserter := NewBatchInserter[user]("user")
+		for b.Loop() {
+			_, err := inserter.Insert(ctx, db, rows)
+			require.NoError(b, err)
+		}
+	})
+
+	b.Run("Exec", func(b *testing.B) {
+		query := `INSERT INTO user (id, username, email, password, age) VALUES (:id, :username, :email, :password, :age)`
+		for b.Loop() {
+			_, err := db.Exec(ctx, query, rows)
+			require.NoError(b, err)
+		}
+	})
+}
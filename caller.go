@@ -0,0 +1,53 @@
+package sqlz
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// callerKey is the context key of the caller set by [Options.TraceCaller].
+type callerKey struct{}
+
+// CallerFromContext returns the "file:line" of the code that called sqlz for
+// the operation of ctx, as passed to [Tracer.StartSpan] when [Options.TraceCaller]
+// is set, e.g. to add it as the OpenTelemetry "code.filepath" attribute.
+func CallerFromContext(ctx context.Context) (string, bool) {
+	caller, ok := ctx.Value(callerKey{}).(string)
+	return caller, ok
+}
+
+// pkgDir is the directory of sqlz source files, frames within it are skipped
+// when looking for the caller, except tests.
+var pkgDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// caller returns the "file:line" of the first frame outside sqlz, which depth
+// varies by method, so it walks the stack rather than using a fixed skip.
+func caller() string {
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:]) // skip runtime.Callers and caller
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+		if !isPkgFile(frame.File) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// isPkgFile reports whether file is a non-test source file of sqlz or one of
+// its internal packages.
+func isPkgFile(file string) bool {
+	dir := filepath.Dir(file)
+	inPkg := dir == pkgDir || strings.HasPrefix(dir, pkgDir+string(filepath.Separator))
+	return inPkg && !strings.HasSuffix(file, "_test.go")
+}
@@ -0,0 +1,63 @@
+package sqlz
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallerFromContext(t *testing.T) {
+	_, ok := CallerFromContext(ctx)
+	assert.False(t, ok)
+}
+
+func TestDB_TraceCaller(t *testing.T) {
+	pool := sql.OpenDB(&countingConnector{})
+	t.Cleanup(func() { pool.Close() })
+
+	tracer := &fakeTracer{}
+	db := New("mock", pool, &Options{Bind: BindQuestion, Tracer: tracer, TraceCaller: true})
+
+	// line returns the "file:line" of its caller plus offset.
+	line := func(offset int) string {
+		_, file, line, _ := runtime.Caller(1)
+		return fmt.Sprintf("%s:%d", file, line+offset)
+	}
+
+	var ids []int
+	require.NoError(t, db.Query(ctx, "SELECT id FROM user WHERE id = ?", 1).Scan(&ids))
+	queryLine := line(-1)
+	_, err := db.Exec(ctx, "DELETE FROM user WHERE id = :id", map[string]any{"id": 1})
+	execLine := line(-1)
+	require.NoError(t, err)
+	err = db.GetRow(ctx, "SELECT id FROM user", nil, &ids)
+	getRowLine := line(-1)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.Len(t, tracer.spans, 3)
+	assert.Equal(t, queryLine, tracer.spans[0].caller)
+	assert.Equal(t, execLine, tracer.spans[1].caller)
+	assert.Equal(t, getRowLine, tracer.spans[2].caller)
+
+	t.Run("disabled", func(t *testing.T) {
+		tracer := &fakeTracer{}
+		db := New("mock", pool, &Options{Bind: BindQuestion, Tracer: tracer})
+		_, err := db.Exec(ctx, "DELETE FROM user WHERE id = 1")
+		require.NoError(t, err)
+		require.Len(t, tracer.spans, 1)
+		assert.Empty(t, tracer.spans[0].caller)
+	})
+}
+
+func TestIsPkgFile(t *testing.T) {
+	assert.True(t, isPkgFile(filepath.Join(pkgDir, "base.go")))
+	assert.True(t, isPkgFile(filepath.Join(pkgDir, "internal", "parser", "parser.go")))
+	assert.False(t, isPkgFile(filepath.Join(pkgDir, "base_test.go")))
+	assert.False(t, isPkgFile(filepath.Join(filepath.Dir(pkgDir), "app", "main.go")))
+	assert.False(t, isPkgFile(filepath.Join(pkgDir+"extra", "main.go")))
+}
@@ -0,0 +1,139 @@
+package sqlz
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/rfberaldo/sqlz/internal/parser"
+	"github.com/rfberaldo/sqlz/internal/reflectutil"
+)
+
+// defaultMaxBatchParams returns the parameter limit of a single statement
+// of driverName, see [Options.MaxBatchParams].
+func defaultMaxBatchParams(driverName string, bind parser.Bind) int {
+	switch {
+	case driverName == "sqlite3" || driverName == "sqlite":
+		return 32766
+	case bind == parser.BindAt || bind == parser.BindNamedAt:
+		return 2100
+	}
+	return 65535
+}
+
+// batchChunks splits the arg of a named batch insert into chunks of rows
+// within [Options.MaxBatchParams], returns nil if it doesn't need splitting.
+func (c *base) batchChunks(query string, args []any) []any {
+	if c.maxBatchParams <= 0 || len(args) != 1 {
+		return nil
+	}
+
+	rows := reflect.Indirect(reflect.ValueOf(args[0]))
+	if rows.Kind() != reflect.Slice {
+		return nil
+	}
+
+	switch reflectutil.Deref(rows.Type().Elem()).Kind() {
+	case reflect.Struct, reflect.Map:
+	default:
+		return nil
+	}
+
+	perRow := len(parser.ParseIdents(c.bind, query))
+	if perRow == 0 {
+		return nil
+	}
+
+	size := max(c.maxBatchParams/perRow, 1)
+	if rows.Len() <= size {
+		return nil
+	}
+
+	chunks := make([]any, 0, (rows.Len()+size-1)/size)
+	for i := 0; i < rows.Len(); i += size {
+		chunks = append(chunks, rows.Slice(i, min(i+size, rows.Len())).Interface())
+	}
+
+	return chunks
+}
+
+// beginner is satisfied by [sql.DB] and [acquireConn], whose chunked
+// batch inserts run in a transaction.
+type beginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// execChunks executes query once per chunk of rows, see [base.batchChunks],
+// within a transaction if db is not one already.
+func (c *base) execChunks(ctx context.Context, db querier, query string, chunks []any) (_ sql.Result, err error) {
+	pool, ok := db.(beginner)
+	if !ok {
+		return c.execEachChunk(ctx, db, query, chunks)
+	}
+
+	tx, err := pool.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("sqlz: beginning batch transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback())
+		}
+	}()
+
+	// statements prepared on tx are closed with it, don't cache them
+	result, err := (&base{config: c.config}).execEachChunk(ctx, tx, query, chunks)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("sqlz: committing batch transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// execEachChunk executes query once per chunk, sequentially.
+func (c *base) execEachChunk(ctx context.Context, db querier, query string, chunks []any) (sql.Result, error) {
+	results := make([]sql.Result, 0, len(chunks))
+	for i, chunk := range chunks {
+		result, err := c.exec(ctx, db, query, chunk)
+		if err != nil {
+			return nil, fmt.Errorf("sqlz: executing batch chunk %d of %d: %w", i+1, len(chunks), err)
+		}
+		results = append(results, result)
+	}
+	return batchResult{results, c.firstInsertId}, nil
+}
+
+// batchResult aggregates the results of the chunks of a batch insert.
+type batchResult struct {
+	results       []sql.Result
+	firstInsertId bool // whether LastInsertId of a multi-row insert is the first id
+}
+
+// LastInsertId returns the id the driver would report for a single insert:
+// the one of the first chunk, which in MySQL is the id of the first row,
+// otherwise the one of the last chunk, e.g. the id of the last row in SQLite.
+func (r batchResult) LastInsertId() (int64, error) {
+	if r.firstInsertId {
+		return r.results[0].LastInsertId()
+	}
+	return r.results[len(r.results)-1].LastInsertId()
+}
+
+// RowsAffected returns the sum of the rows affected by every chunk.
+func (r batchResult) RowsAffected() (int64, error) {
+	var total int64
+	for _, result := range r.results {
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
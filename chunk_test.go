@@ -0,0 +1,150 @@
+package sqlz
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/rfberaldo/sqlz/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultMaxBatchParams(t *testing.T) {
+	assert.Equal(t, 65535, defaultMaxBatchParams("pgx", parser.BindDollar))
+	assert.Equal(t, 65535, defaultMaxBatchParams("mysql", parser.BindQuestion))
+	assert.Equal(t, 32766, defaultMaxBatchParams("sqlite3", parser.BindQuestion))
+	assert.Equal(t, 2100, defaultMaxBatchParams("sqlserver", parser.BindAt))
+	assert.Equal(t, 2100, defaultMaxBatchParams("sqlserver", parser.BindNamedAt))
+
+	db := New("pgx", sql.OpenDB(&countingConnector{}), nil)
+	assert.Equal(t, 65535, db.base.maxBatchParams)
+
+	db = New("pgx", sql.OpenDB(&countingConnector{}), &Options{MaxBatchParams: -1})
+	assert.Equal(t, -1, db.base.maxBatchParams)
+}
+
+func TestBase_batchChunks(t *testing.T) {
+	type User struct{ Id, Age int }
+
+	base := newBase(&config{bind: parser.BindQuestion, maxBatchParams: 4})
+	query := "INSERT INTO user (id, age) VALUES (:id, :age)"
+	users := []User{{1, 10}, {2, 20}, {3, 30}, {4, 40}, {5, 50}}
+
+	chunks := base.batchChunks(query, []any{users})
+	assert.Equal(t, []any{users[0:2], users[2:4], users[4:5]}, chunks)
+
+	chunks = base.batchChunks(query, []any{&users})
+	assert.Len(t, chunks, 3)
+
+	maps := []map[string]any{{"id": 1, "age": 10}, {"id": 2, "age": 20}, {"id": 3, "age": 30}}
+	chunks = base.batchChunks(query, []any{maps})
+	assert.Equal(t, []any{maps[0:2], maps[2:3]}, chunks)
+
+	// within the limit
+	assert.Nil(t, base.batchChunks(query, []any{users[:2]}))
+
+	// not a batch insert
+	assert.Nil(t, base.batchChunks("SELECT * FROM user WHERE id IN (?)", []any{[]int{1, 2, 3, 4, 5}}))
+	assert.Nil(t, base.batchChunks("UPDATE user SET age = ? WHERE id = ?", []any{1, 2}))
+	assert.Nil(t, base.batchChunks("DELETE FROM user", []any{users}))
+
+	// disabled
+	base = newBase(&config{bind: parser.BindQuestion})
+	assert.Nil(t, base.batchChunks(query, []any{users}))
+}
+
+func TestDB_Exec_chunks(t *testing.T) {
+	type User struct{ Id, Age int }
+
+	users := make([]User, 10)
+	for i := range users {
+		users[i] = User{i + 1, 20}
+	}
+	query := "INSERT INTO user (id, age) VALUES (:id, :age)"
+
+	t.Run("in a transaction", func(t *testing.T) {
+		connector := &countingConnector{transactional: true}
+		pool := sql.OpenDB(connector)
+		t.Cleanup(func() { pool.Close() })
+		db := New("mock", pool, &Options{Bind: BindQuestion, MaxBatchParams: 8})
+
+		result, err := db.Exec(ctx, query, users)
+		require.NoError(t, err)
+		affected, err := result.RowsAffected()
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), affected) // the mock affects 1 row per exec
+
+		assert.Equal(t, int32(3), connector.execs.Load())
+		assert.Equal(t, int32(1), connector.commits.Load())
+		assert.Zero(t, connector.rollbacks.Load())
+	})
+
+	t.Run("failed chunk rolls back", func(t *testing.T) {
+		connector := &countingConnector{transactional: true, failExec: 2}
+		pool := sql.OpenDB(connector)
+		t.Cleanup(func() { pool.Close() })
+		db := New("mock", pool, &Options{Bind: BindQuestion, MaxBatchParams: 8})
+
+		_, err := db.Exec(ctx, query, users)
+		assert.ErrorContains(t, err, "executing batch chunk 2 of 3: exec failed")
+		assert.Equal(t, int32(2), connector.execs.Load())
+		assert.Zero(t, connector.commits.Load())
+		assert.Equal(t, int32(1), connector.rollbacks.Load())
+	})
+
+	t.Run("within a transaction", func(t *testing.T) {
+		connector := &countingConnector{transactional: true}
+		pool := sql.OpenDB(connector)
+		t.Cleanup(func() { pool.Close() })
+		db := New("mock", pool, &Options{Bind: BindQuestion, MaxBatchParams: 8})
+
+		tx, err := db.Begin(ctx)
+		require.NoError(t, err)
+		_, err = tx.Exec(ctx, query, users)
+		require.NoError(t, err)
+		require.NoError(t, tx.Commit())
+
+		assert.Equal(t, int32(3), connector.execs.Load())
+		assert.Equal(t, int32(1), connector.commits.Load())
+	})
+
+	t.Run("acquire timeout", func(t *testing.T) {
+		connector := &countingConnector{transactional: true}
+		pool := sql.OpenDB(connector)
+		t.Cleanup(func() { pool.Close() })
+		pool.SetMaxOpenConns(1)
+		db := New("mock", pool, &Options{Bind: BindQuestion, MaxBatchParams: 8, AcquireTimeout: time.Second})
+
+		_, err := db.Exec(ctx, query, users)
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), connector.commits.Load())
+
+		// the connection is released once the transaction ends
+		assert.Eventually(t, func() bool { return pool.Stats().InUse == 0 }, time.Second, time.Millisecond)
+	})
+
+	t.Run("last insert id", func(t *testing.T) {
+		results := []sql.Result{driverResult{10, 2}, driverResult{20, 1}}
+
+		// MySQL reports the first id, and SQLite the last one
+		result := batchResult{results, true}
+		id, err := result.LastInsertId()
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), id)
+		affected, err := result.RowsAffected()
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), affected)
+
+		result = batchResult{results, false}
+		id, err = result.LastInsertId()
+		require.NoError(t, err)
+		assert.Equal(t, int64(20), id)
+	})
+}
+
+// driverResult is a [sql.Result] with fixed values.
+type driverResult struct{ id, affected int64 }
+
+func (r driverResult) LastInsertId() (int64, error) { return r.id, nil }
+func (r driverResult) RowsAffected() (int64, error) { return r.affected, nil }
@@ -2,6 +2,10 @@ package sqlz
 
 import (
 	"cmp"
+	"context"
+	"database/sql"
+	"reflect"
+	"time"
 
 	"github.com/rfberaldo/sqlz/internal/parser"
 )
@@ -10,6 +14,7 @@ const (
 	defaultStructTag         = "db"
 	defaultBind              = parser.BindQuestion
 	defaultStmtCacheCapacity = 16
+	defaultCSVDelimiter      = ","
 )
 
 var (
@@ -18,12 +23,35 @@ var (
 
 // config contains flags that are used across internal objects.
 type config struct {
-	defaultsApplied      bool
-	bind                 parser.Bind
-	structTag            string
-	fieldNameTransformer func(string) string
-	ignoreMissingFields  bool
-	stmtCacheCapacity    int
+	defaultsApplied         bool
+	bind                    parser.Bind
+	structTag               string
+	fieldNameTransformer    func(string) string
+	ignoreMissingFields     bool
+	stmtCacheCapacity       int
+	normalizeQueries        bool
+	normalizeTimesToUTC     bool
+	requireWhereOnMutations bool
+	fieldConverters         map[reflect.Type]func(any) (any, error)
+	nilAllNullStructs       bool
+	stripColumnTablePrefix  bool
+	csvDelimiter            string
+	readWriteRouter         func(ctx context.Context, query string) *sql.DB
+	maxColumns              int
+	collectColumnTimings    bool
+	nullCollectionMode      NullCollectionMode
+	tracer                  Tracer
+	traceCaller             bool
+	verifyArgCount          bool
+	zeroTimeAsNull          bool
+	fastParse               bool
+	bindMissingAsNull       bool
+	stripTrailingSemicolon  bool
+	acquireTimeout          time.Duration
+	strictNumericRange      bool
+	maxBatchParams          int
+	duplicateColumnMode     DuplicateColumnMode
+	firstInsertId           bool // whether LastInsertId of a multi-row insert is the first id, see [DB.ExecInsert]
 }
 
 // applyDefaults returns a cfg with defaults applied, if not set.
@@ -42,6 +70,7 @@ func applyDefaults(cfg *config) *config {
 	cfg.bind = cmp.Or(cfg.bind, defaultBind)
 	cfg.structTag = cmp.Or(cfg.structTag, defaultStructTag)
 	cfg.stmtCacheCapacity = cmp.Or(cfg.stmtCacheCapacity, defaultStmtCacheCapacity)
+	cfg.csvDelimiter = cmp.Or(cfg.csvDelimiter, defaultCSVDelimiter)
 
 	if cfg.fieldNameTransformer == nil {
 		cfg.fieldNameTransformer = defaultFieldNameTransformer
@@ -0,0 +1,55 @@
+package sqlz
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/rfberaldo/sqlz/internal/reflectutil"
+)
+
+// GetByPK scans the row of table whose primary key is pk into dest, a pointer
+// to a struct with a field tagged with the "pk" option, as in `db:"id,pk"`:
+//
+//	// SELECT * FROM user WHERE id = ?
+//	err := db.GetByPK(ctx, &user, "user", 42)
+//
+// If there's no such row, it returns [sql.ErrNoRows].
+// The table name is used as is, it must not come from user input.
+func (db *DB) GetByPK(ctx context.Context, dest any, table string, pk any) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Pointer || reflectutil.TypeOfAny(dest) != reflectutil.Struct || destValue.IsNil() {
+		return fmt.Errorf("sqlz: destination must be a pointer to a struct, got %T", dest)
+	}
+
+	pkCol, err := db.base.pkColumn(destValue.Type())
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s = :pk", table, pkCol)
+	return db.QueryRow(ctx, query, map[string]any{"pk": pk}).Scan(dest)
+}
+
+// pkColumn returns the column of the structType field tagged with [reflectutil.PKOption].
+func (c *base) pkColumn(structType reflect.Type) (string, error) {
+	structType = reflectutil.Deref(structType)
+
+	var pkCol string
+	for column, index := range reflectutil.StructFieldMap(structType, c.structTag, "_", c.fieldNameTransformer) {
+		field := structType.FieldByIndex(index)
+		if !reflectutil.HasTagOption(field, c.structTag, reflectutil.PKOption) {
+			continue
+		}
+		if pkCol != "" {
+			return "", fmt.Errorf("sqlz: multiple pk fields in %s", structType)
+		}
+		pkCol = column
+	}
+
+	if pkCol == "" {
+		return "", fmt.Errorf("sqlz: no field tagged with the '%s' option in %s", reflectutil.PKOption, structType)
+	}
+
+	return pkCol, nil
+}
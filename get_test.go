@@ -0,0 +1,94 @@
+package sqlz
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDB_GetByPK(t *testing.T) {
+	type Product struct {
+		Code  string `db:"code,pk"`
+		Name  string
+		Price float64
+	}
+
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, nil)
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := db.Exec(ctx, th.fmt(`CREATE TABLE %s (code VARCHAR(10) PRIMARY KEY, name VARCHAR(100), price FLOAT)`))
+		require.NoError(t, err)
+
+		_, err = db.Exec(ctx, th.fmt(`INSERT INTO %s (code, name, price) VALUES ('pen', 'Pen', 1.5), ('ink', 'Ink', 3)`))
+		require.NoError(t, err)
+
+		var product Product
+		err = db.GetByPK(ctx, &product, th.tableName, "ink")
+		require.NoError(t, err)
+		assert.Equal(t, Product{"ink", "Ink", 3}, product)
+
+		err = db.GetByPK(ctx, &product, th.tableName, "cap")
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
+
+func TestDB_GetByPK_mock(t *testing.T) {
+	pool := sql.OpenDB(&countingConnector{})
+	t.Cleanup(func() { pool.Close() })
+	db := New("mock", pool, &Options{Bind: BindQuestion})
+
+	type Product struct {
+		Id   int `db:"id,pk"`
+		Name string
+	}
+
+	var product Product
+	err := db.GetByPK(ctx, &product, "product", 1)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = db.GetByPK(ctx, product, "product", 1)
+	assert.ErrorContains(t, err, "destination must be a pointer to a struct")
+
+	err = db.GetByPK(ctx, &[]Product{}, "product", 1)
+	assert.ErrorContains(t, err, "destination must be a pointer to a struct")
+}
+
+func TestBase_pkColumn(t *testing.T) {
+	base := newBase(nil)
+
+	t.Run("tagged", func(t *testing.T) {
+		type Audit struct {
+			CreatedBy string
+		}
+		type User struct {
+			Audit
+			UserId int `db:"user_id,pk,readonly"`
+			Name   string
+		}
+		column, err := base.pkColumn(reflect.TypeFor[*User]())
+		require.NoError(t, err)
+		assert.Equal(t, "user_id", column)
+	})
+
+	t.Run("untagged", func(t *testing.T) {
+		type User struct {
+			Id   int
+			Name string
+		}
+		_, err := base.pkColumn(reflect.TypeFor[User]())
+		assert.ErrorContains(t, err, "no field tagged with the 'pk' option")
+	})
+
+	t.Run("multiple", func(t *testing.T) {
+		type User struct {
+			Id   int    `db:"id,pk"`
+			Code string `db:"code,pk"`
+		}
+		_, err := base.pkColumn(reflect.TypeFor[User]())
+		assert.ErrorContains(t, err, "multiple pk fields")
+	})
+}
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParseFast is like [Parse], but assumes query has no literals nor escaped
+// placeholders, skipping their handling; whitespace is kept as is.
+// If query has a quote char or an escaped placeholder, it falls back to [Parse].
+func ParseFast(bind Bind, query string) (string, []string) {
+	if strings.ContainsAny(query, "'\"`") || strings.Contains(query, "::") {
+		return Parse(bind, query)
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(query))
+
+	var idents []string
+	last := 0 // start of the input not yet written
+
+	for i := 0; i < len(query); i++ {
+		if query[i] != ':' {
+			continue
+		}
+
+		ch, _ := utf8.DecodeRuneInString(query[i+1:])
+		if !unicode.IsLetter(ch) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(query) {
+			ch, size := utf8.DecodeRuneInString(query[end:])
+			if !isIdentChar(ch) {
+				break
+			}
+			end += size
+		}
+
+		ident := query[i+1 : end]
+		idents = append(idents, ident)
+		sb.WriteString(query[last:i])
+
+		switch bind {
+		case BindQuestion:
+			sb.WriteByte('?')
+		case BindColon:
+			sb.WriteByte(':')
+			sb.WriteString(ident)
+		case BindAt:
+			sb.WriteString("@p")
+			sb.WriteString(strconv.Itoa(len(idents)))
+		case BindDollar:
+			sb.WriteByte('$')
+			sb.WriteString(strconv.Itoa(len(idents)))
+		case BindNamedAt:
+			sb.WriteByte('@')
+			sb.WriteString(namedAtIdent(ident, 0, 1))
+		}
+
+		last = end
+		i = end - 1
+	}
+
+	sb.WriteString(query[last:])
+
+	return sb.String(), idents
+}
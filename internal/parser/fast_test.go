@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFast(t *testing.T) {
+	tests := []struct {
+		name             string
+		input            string
+		expectedDollar   string
+		expectedQuestion string
+		expectedIdents   []string
+	}{
+		{
+			name:             "no named parameters",
+			input:            "SELECT * FROM user WHERE id = 1",
+			expectedDollar:   "SELECT * FROM user WHERE id = 1",
+			expectedQuestion: "SELECT * FROM user WHERE id = 1",
+			expectedIdents:   nil,
+		},
+		{
+			name:             "multiple named parameters",
+			input:            "SELECT * FROM user WHERE id = :id AND name = :name",
+			expectedDollar:   "SELECT * FROM user WHERE id = $1 AND name = $2",
+			expectedQuestion: "SELECT * FROM user WHERE id = ? AND name = ?",
+			expectedIdents:   []string{"id", "name"},
+		},
+		{
+			name:             "nested and unicode idents",
+			input:            "SELECT * FROM user WHERE id=:user.id AND name=:名前",
+			expectedDollar:   "SELECT * FROM user WHERE id=$1 AND name=$2",
+			expectedQuestion: "SELECT * FROM user WHERE id=? AND name=?",
+			expectedIdents:   []string{"user.id", "名前"},
+		},
+		{
+			name:             "colon not followed by letter",
+			input:            "SELECT * FROM t WHERE a = :1 AND b = :b:",
+			expectedDollar:   "SELECT * FROM t WHERE a = :1 AND b = $1:",
+			expectedQuestion: "SELECT * FROM t WHERE a = :1 AND b = ?:",
+			expectedIdents:   []string{"b"},
+		},
+		{
+			name:             "whitespace is kept",
+			input:            "SELECT *\n\tFROM user\n\tWHERE id = :id",
+			expectedDollar:   "SELECT *\n\tFROM user\n\tWHERE id = $1",
+			expectedQuestion: "SELECT *\n\tFROM user\n\tWHERE id = ?",
+			expectedIdents:   []string{"id"},
+		},
+		{
+			name:             "fallback on escaped colon",
+			input:            "SELECT id::text FROM user WHERE id = :id",
+			expectedDollar:   "SELECT id:text FROM user WHERE id = $1",
+			expectedQuestion: "SELECT id:text FROM user WHERE id = ?",
+			expectedIdents:   []string{"id"},
+		},
+		{
+			name:             "fallback on literal",
+			input:            "SELECT *\n\tFROM user WHERE name = ':name' AND id = :id",
+			expectedDollar:   "SELECT * FROM user WHERE name = '$1' AND id = $2",
+			expectedQuestion: "SELECT * FROM user WHERE name = '?' AND id = ?",
+			expectedIdents:   []string{"name", "id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, idents := ParseFast(BindDollar, tt.input)
+			assert.Equal(t, tt.expectedDollar, output)
+			assert.Equal(t, tt.expectedIdents, idents)
+
+			output, idents = ParseFast(BindQuestion, tt.input)
+			assert.Equal(t, tt.expectedQuestion, output)
+			assert.Equal(t, tt.expectedIdents, idents)
+
+			output, _ = ParseFast(BindColon, tt.input)
+			expected, _ := Parse(BindColon, tt.input)
+			assert.Equal(t, strings.Fields(expected), strings.Fields(output))
+
+			output, _ = ParseFast(BindAt, tt.input)
+			expected, _ = Parse(BindAt, tt.input)
+			assert.Equal(t, strings.Fields(expected), strings.Fields(output))
+
+			output, _ = ParseFast(BindNamedAt, tt.input)
+			expected, _ = Parse(BindNamedAt, tt.input)
+			assert.Equal(t, strings.Fields(expected), strings.Fields(output))
+		})
+	}
+}
+
+// BenchmarkParseFast/Parse-12        	 1000000	      1023 ns/op	     128 B/op	       3 allocs/op
+// BenchmarkParseFast/ParseFast-12    	 4634511	       265.4 ns/op	     112 B/op	       2 allocs/op
+func BenchmarkParseFast(b *testing.B) {
+	input := `SELECT id, username, email FROM user WHERE id = :id AND status = :status`
+
+	b.Run("Parse", func(b *testing.B) {
+		for b.Loop() {
+			_, _ = Parse(BindDollar, input)
+		}
+	})
+
+	b.Run("ParseFast", func(b *testing.B) {
+		for b.Loop() {
+			_, _ = ParseFast(BindDollar, input)
+		}
+	})
+}
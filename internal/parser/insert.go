@@ -0,0 +1,23 @@
+package parser
+
+// InsertValuesIndex returns the index of the VALUES keyword of an INSERT query,
+// a leading WITH clause is skipped, VALUES of subqueries and literals are ignored.
+// It returns -1 if the query has no VALUES clause, or inserts from a SELECT.
+//
+//	InsertValuesIndex("INSERT INTO user (name) VALUES (?)")                 // Output: 24
+//	InsertValuesIndex("INSERT INTO user (id) SELECT id FROM tmp")        // Output: -1
+func InsertValuesIndex(query string) int {
+	offset := max(topLevelKeyword(query, "INSERT"), 0)
+	query = query[offset:]
+
+	values := topLevelKeyword(query, "VALUES")
+	if values == -1 {
+		return -1
+	}
+
+	if sel := topLevelKeyword(query, "SELECT"); sel != -1 && sel < values {
+		return -1
+	}
+
+	return offset + values
+}
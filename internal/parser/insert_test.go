@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertValuesIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+	}{
+		{
+			name:     "values",
+			input:    "INSERT INTO user (name) VALUES (?)",
+			expected: 24,
+		},
+		{
+			name:     "lowercase without spaces",
+			input:    "insert into user(name)values(?)",
+			expected: 22,
+		},
+		{
+			name:     "with cte",
+			input:    "WITH t AS (SELECT * FROM (VALUES (1)) v) INSERT INTO user (id) VALUES (?)",
+			expected: 63,
+		},
+		{
+			name:     "insert select",
+			input:    "INSERT INTO user (id, name) SELECT id, name FROM tmp",
+			expected: -1,
+		},
+		{
+			name:     "insert select from cte",
+			input:    "WITH t AS (SELECT ? AS id) INSERT INTO user (id) SELECT id FROM t",
+			expected: -1,
+		},
+		{
+			name:     "insert select with values subquery",
+			input:    "INSERT INTO user (id) SELECT id FROM (VALUES (1), (2)) v",
+			expected: -1,
+		},
+		{
+			name:     "values in literal",
+			input:    "INSERT INTO note (text) SELECT 'values (1)'",
+			expected: -1,
+		},
+		{
+			name:     "no values",
+			input:    "UPDATE user SET name = ?",
+			expected: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, InsertValuesIndex(tt.input))
+		})
+	}
+}
@@ -1,8 +1,12 @@
 package parser
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/rfberaldo/sqlz/internal/reflectutil"
 )
@@ -28,9 +32,52 @@ func ParseIdents(bind Bind, query string) []string {
 	return idents
 }
 
+// CountPlaceholders returns the number of placeholders of the native query,
+// counted the same way as [ParseInClause] numbers them, escaped ones are not counted.
+func CountPlaceholders(bind Bind, query string) int {
+	p := &Parser{bind: bind, input: query, sliceOutsideIn: -1}
+	p.parseInNative()
+	return p.bindCount
+}
+
+// Renumber returns the native query with its numbered placeholders, like "$1"
+// or "@p1", numbered again in order of appearance, e.g. after repeating a part
+// of the query. Like [ParseInClause], whitespace is collapsed.
+//
+//	Renumber(BindDollar, "INSERT INTO t (a) VALUES ($1),($1)") // Output: "INSERT INTO t (a) VALUES ($1),($2)"
+func Renumber(bind Bind, query string) string {
+	p := &Parser{bind: bind, input: query, sliceOutsideIn: -1}
+	return p.parseInNative()
+}
+
+// PlaceholderNames returns the names of the placeholders of a native query with
+// [BindNamedAt], in order, including repeated ones; escaped ones are not included.
+//
+//	PlaceholderNames("SELECT * FROM user WHERE id = @id OR parent = @id") // Output: []string{"id", "id"}
+func PlaceholderNames(query string) []string {
+	p := &Parser{bind: BindNamedAt, input: query, sliceOutsideIn: -1}
+	p.parseInNative()
+	return p.idents
+}
+
+// SliceOutsideInError is returned by [ParseInClause] when a slice is passed
+// to a placeholder which is not inside an "IN (...)" clause.
+type SliceOutsideInError struct {
+	Index int // 0-based index of the placeholder
+}
+
+func (e *SliceOutsideInError) Error() string {
+	return fmt.Sprintf(
+		"sqlz/parser: slice arg for non-IN parameter #%d, slices are only spread inside 'IN (...)'",
+		e.Index+1,
+	)
+}
+
 // ParseInClause expands any binds in the query, respecting the bind param,
 // that correspond to a slice in args to the length of that slice,
 // and then appends those slice elements to a new arglist.
+// Slices are only accepted inside an "IN (...)" clause, otherwise it returns
+// a [SliceOutsideInError].
 func ParseInClause(bind Bind, query string, args []any) (string, []any, error) {
 	countByIndex, spreadArgs, err := spreadSlices(args)
 	if err != nil {
@@ -45,9 +92,14 @@ func ParseInClause(bind Bind, query string, args []any) (string, []any, error) {
 		bind:                 bind,
 		input:                query,
 		inClauseCountByIndex: countByIndex,
+		sliceOutsideIn:       -1,
 	}
 	output := p.parseInNative()
 
+	if p.sliceOutsideIn > -1 {
+		return "", nil, &SliceOutsideInError{p.sliceOutsideIn}
+	}
+
 	if len(spreadArgs) != p.bindCount {
 		return "", nil, fmt.Errorf(
 			"sqlz/parser: arguments mismatch parsing 'IN' clause: bindvars %d arguments %d",
@@ -58,6 +110,30 @@ func ParseInClause(bind Bind, query string, args []any) (string, []any, error) {
 	return output, spreadArgs, nil
 }
 
+// IdentIndexes returns the 0-based index of the first placeholder of each ident
+// after expanding "IN" clauses, idents are as returned by [Parse] and args are
+// their values in the same order; if args is nil, no ident is expanded.
+func IdentIndexes(idents []string, args []any) map[string]int {
+	indexByIdent := make(map[string]int, len(idents))
+	position := 0
+
+	for i, ident := range idents {
+		if _, ok := indexByIdent[ident]; !ok {
+			indexByIdent[ident] = position
+		}
+
+		if i < len(args) {
+			if argValue := reflect.Indirect(reflect.ValueOf(args[i])); shouldSpread(argValue) {
+				position += argValue.Len()
+				continue
+			}
+		}
+		position++
+	}
+
+	return indexByIdent
+}
+
 func spreadSlices(args []any) (map[int]int, []any, error) {
 	inClauseCountByIndex := make(map[int]int)
 	outArgs := make([]any, 0, len(args))
@@ -72,7 +148,7 @@ func spreadSlices(args []any) (map[int]int, []any, error) {
 			}
 			inClauseCountByIndex[i] = length
 			for j := range length {
-				outArgs = append(outArgs, reflectutil.TypedValue(argValue.Index(j)))
+				outArgs = append(outArgs, spreadValue(argValue.Index(j)))
 			}
 			continue
 		}
@@ -83,6 +159,34 @@ func spreadSlices(args []any) (map[int]int, []any, error) {
 	return inClauseCountByIndex, outArgs, nil
 }
 
+var valuerType = reflect.TypeFor[driver.Valuer]()
+
+// spreadValue returns the value of a slice element, pointers are dereferenced
+// and nil becomes NULL, unless they implement [driver.Valuer], which are kept as is.
+func spreadValue(v reflect.Value) any {
+	// e.g. []any{&id}
+	if v.Kind() == reflect.Interface && !v.IsNil() && v.Elem().Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Implements(valuerType) {
+			return v.Interface()
+		}
+		v = v.Elem()
+	}
+
+	// e.g. an enum "type Status int", which would lose its Value method
+	if v.Type().Implements(valuerType) {
+		return v.Interface()
+	}
+
+	return reflectutil.TypedValue(v)
+}
+
 func shouldSpread(v reflect.Value) bool {
 	if !v.IsValid() {
 		return false
@@ -99,3 +203,134 @@ func shouldSpread(v reflect.Value) bool {
 
 	return true
 }
+
+// StripTrailingSemicolon removes a single trailing semicolon of query, and the
+// whitespace before it, semicolons inside literals are kept.
+//
+//	StripTrailingSemicolon("SELECT * FROM user; ")   // Output: "SELECT * FROM user"
+//	StripTrailingSemicolon("SELECT * FROM user ';'") // Output: "SELECT * FROM user ';'"
+func StripTrailingSemicolon(query string) string {
+	trimmed := strings.TrimRightFunc(query, unicode.IsSpace)
+	if !strings.HasSuffix(trimmed, ";") {
+		return query
+	}
+
+	// the semicolon is inside a literal if it's not closed
+	var quote rune
+	for _, ch := range trimmed[:len(trimmed)-1] {
+		switch {
+		case quote == 0 && (ch == '\'' || ch == '"' || ch == '`'):
+			quote = ch
+		case ch == quote:
+			quote = 0
+		}
+	}
+	if quote != 0 {
+		return query
+	}
+
+	return strings.TrimRightFunc(trimmed[:len(trimmed)-1], unicode.IsSpace)
+}
+
+// Normalize collapses runs of whitespace outside string literals and comments
+// into a single space, so queries that differ only in formatting are identical.
+// Literals, see [literalLen], and comments are kept as-is, including the newline
+// ending a "--" comment.
+func Normalize(query string) string {
+	var sb strings.Builder
+	sb.Grow(len(query))
+
+	pendingSpace := false
+	for i := 0; i < len(query); {
+		ch, size := utf8.DecodeRuneInString(query[i:])
+		if unicode.IsSpace(ch) {
+			pendingSpace = true
+			i += size
+			continue
+		}
+
+		if pendingSpace {
+			// the newline ending a comment already separates tokens
+			if s := sb.String(); s != "" && s[len(s)-1] != '\n' {
+				sb.WriteByte(' ')
+			}
+			pendingSpace = false
+		}
+
+		end := i + size
+		switch {
+		case strings.HasPrefix(query[i:], "--"):
+			end = len(query)
+			if n := strings.IndexByte(query[i:], '\n'); n > -1 {
+				end = i + n + 1
+			}
+		case strings.HasPrefix(query[i:], "/*"):
+			end = len(query)
+			if n := strings.Index(query[i+2:], "*/"); n > -1 {
+				end = i + 2 + n + 2
+			}
+		default:
+			if n := literalLen(query[i:]); n > 0 {
+				end = i + n
+			}
+		}
+
+		sb.WriteString(query[i:end])
+		i = end
+	}
+
+	return sb.String()
+}
+
+// literalLen returns the length of the literal at the start of s, or 0 if there's
+// none: a string or identifier delimited by single quotes, double quotes or
+// backticks, or a PostgreSQL dollar-quoted string, like $$text$$ or $tag$text$tag$.
+// A backslash escapes the next char inside quotes, as in MySQL; in PostgreSQL it
+// may end the literal later than the database would, but never earlier.
+// Unterminated quotes extend to the end of s.
+func literalLen(s string) int {
+	if s == "" {
+		return 0
+	}
+
+	switch quote := s[0]; quote {
+	case '\'', '"', '`':
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				if quote != '`' {
+					i++
+				}
+			case quote:
+				return i + 1 // a doubled quote starts the next literal right away
+			}
+		}
+		return len(s)
+
+	case '$':
+		n := strings.IndexByte(s[1:], '$')
+		if n < 0 || !isDollarTag(s[1:n+1]) {
+			return 0
+		}
+		tag := s[:n+2]
+		end := strings.Index(s[len(tag):], tag)
+		if end < 0 {
+			return 0
+		}
+		return len(tag) + end + len(tag)
+	}
+
+	return 0
+}
+
+// isDollarTag reports whether tag is valid between the dollar signs of a
+// dollar-quoted string, it's empty or an identifier not starting with a digit,
+// so placeholders like $1 are not taken as one.
+func isDollarTag(tag string) bool {
+	for i, ch := range tag {
+		if !unicode.IsLetter(ch) && ch != '_' && (i == 0 || !unicode.IsDigit(ch)) {
+			return false
+		}
+	}
+	return true
+}
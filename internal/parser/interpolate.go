@@ -0,0 +1,171 @@
+package parser
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Interpolate returns query with its placeholders replaced by args rendered as
+// SQL literals, placeholders inside quoted literals are left untouched.
+// The output is meant for display only, e.g. logging, it must not be executed:
+//
+//	Interpolate(BindDollar, "SELECT * FROM user WHERE id = $1", []any{42})
+//	// Output: "SELECT * FROM user WHERE id = 42"
+func Interpolate(bind Bind, query string, args []any) (string, error) {
+	placeholder, readStrategy, isNumbered := getBindInfo(bind)
+	if placeholder == 0 {
+		return "", fmt.Errorf("sqlz/parser: unknown bind: %d", bind)
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(query))
+
+	var quote rune
+	argIndex := 0
+
+	for i := 0; i < len(query); i++ {
+		ch := rune(query[i])
+
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+			sb.WriteByte(query[i])
+			continue
+
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+			sb.WriteByte(query[i])
+			continue
+
+		case ch != placeholder:
+			sb.WriteByte(query[i])
+			continue
+		}
+
+		// PostgreSQL type cast, e.g. '1'::int
+		if bind == BindColon && i+1 < len(query) && query[i+1] == ':' {
+			sb.WriteString("::")
+			i++
+			continue
+		}
+
+		end := i + 1
+		if bind == BindAt {
+			if end >= len(query) || query[end] != 'p' {
+				sb.WriteByte(query[i])
+				continue
+			}
+			end++
+		}
+
+		start := end
+		if readStrategy != nil {
+			for end < len(query) && readStrategy(rune(query[end])) {
+				end++
+			}
+			if end == start || (isNumbered && !isDigits(query[start:end])) {
+				sb.WriteString(query[i:end])
+				i = end - 1
+				continue
+			}
+		}
+
+		index := argIndex
+		if isNumbered {
+			n, _ := strconv.Atoi(query[start:end])
+			index = n - 1
+		}
+		argIndex++
+
+		if index < 0 || index >= len(args) {
+			return "", fmt.Errorf("sqlz/parser: missing argument for placeholder '%s'", query[i:end])
+		}
+
+		literal, err := formatLiteral(bind, args[index])
+		if err != nil {
+			return "", fmt.Errorf("sqlz/parser: placeholder '%s': %w", query[i:end], err)
+		}
+
+		sb.WriteString(literal)
+		i = end - 1
+	}
+
+	return sb.String(), nil
+}
+
+func isDigits(s string) bool {
+	for _, ch := range s {
+		if !unicode.IsDigit(ch) {
+			return false
+		}
+	}
+	return true
+}
+
+// formatLiteral returns the SQL literal of v respecting the dialect of bind,
+// [driver.Valuer] values are resolved first and pointers are followed.
+func formatLiteral(bind Bind, v any) (string, error) {
+	if valuer, ok := v.(driver.Valuer); ok {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return "NULL", nil
+		}
+
+		value, err := valuer.Value()
+		if err != nil {
+			return "", err
+		}
+		v = value
+	}
+
+	if literal, ok := InlineLiteral(bind, v); ok {
+		return literal, nil
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(v))
+
+	switch v := rv.Interface().(type) {
+	case time.Time:
+		if bind == BindDollar {
+			return quoteString(bind, v.Format("2006-01-02 15:04:05.999999Z07:00")), nil
+		}
+		return quoteString(bind, v.Format("2006-01-02 15:04:05.999999")), nil
+
+	case []byte:
+		if bind == BindDollar {
+			return `'\x` + hex.EncodeToString(v) + "'", nil
+		}
+		return "X'" + hex.EncodeToString(v) + "'", nil
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return quoteString(bind, rv.String()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, rv.Type().Bits()), nil
+	}
+
+	return "", fmt.Errorf("unsupported type %T", v)
+}
+
+// quoteString returns s as a quoted string literal, MySQL also treats
+// backslashes as escape characters by default.
+func quoteString(bind Bind, s string) string {
+	s = strings.ReplaceAll(s, "'", "''")
+	if bind == BindQuestion {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + s + "'"
+}
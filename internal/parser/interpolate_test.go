@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterpolate(t *testing.T) {
+	ts := time.Date(2025, 3, 14, 15, 9, 26, 535000000, time.UTC)
+	name := "O'Brien"
+	var nilPtr *int
+	args := []any{42, name, true, nil, ts, 3.5, &name, nilPtr, sql.NullString{}}
+
+	tests := []struct {
+		name     string
+		bind     Bind
+		input    string
+		args     []any
+		expected string
+	}{
+		{
+			name:     "question mixed types",
+			bind:     BindQuestion,
+			input:    "INSERT INTO user VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			args:     args,
+			expected: `INSERT INTO user VALUES (42, 'O''Brien', 1, NULL, '2025-03-14 15:09:26.535', 3.5, 'O''Brien', NULL, NULL)`,
+		},
+		{
+			name:     "dollar mixed types",
+			bind:     BindDollar,
+			input:    "INSERT INTO user VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+			args:     args,
+			expected: `INSERT INTO user VALUES (42, 'O''Brien', TRUE, NULL, '2025-03-14 15:09:26.535Z', 3.5, 'O''Brien', NULL, NULL)`,
+		},
+		{
+			name:     "dollar out of order",
+			bind:     BindDollar,
+			input:    "SELECT * FROM user WHERE name = $2 OR id = $1 OR parent_id = $1",
+			args:     []any{1, "Alice"},
+			expected: "SELECT * FROM user WHERE name = 'Alice' OR id = 1 OR parent_id = 1",
+		},
+		{
+			name:     "at",
+			bind:     BindAt,
+			input:    "SELECT * FROM user WHERE id = @p1 AND email = 'a@p2.com'",
+			args:     []any{uint(7)},
+			expected: "SELECT * FROM user WHERE id = 7 AND email = 'a@p2.com'",
+		},
+		{
+			name:     "colon with cast",
+			bind:     BindColon,
+			input:    "SELECT :id::text, :name FROM dual",
+			args:     []any{int8(1), "Rob"},
+			expected: "SELECT 1::text, 'Rob' FROM dual",
+		},
+		{
+			name:     "placeholder inside literal",
+			bind:     BindQuestion,
+			input:    "SELECT 'why?', ? FROM dual",
+			args:     []any{"yes"},
+			expected: "SELECT 'why?', 'yes' FROM dual",
+		},
+		{
+			name:     "mysql backslash",
+			bind:     BindQuestion,
+			input:    "SELECT ?",
+			args:     []any{`C:\temp`},
+			expected: `SELECT 'C:\\temp'`,
+		},
+		{
+			name:     "bytes",
+			bind:     BindQuestion,
+			input:    "SELECT ?",
+			args:     []any{[]byte{0xca, 0xfe}},
+			expected: "SELECT X'cafe'",
+		},
+		{
+			name:     "valuer",
+			bind:     BindDollar,
+			input:    "SELECT $1",
+			args:     []any{sql.NullInt64{Int64: 5, Valid: true}},
+			expected: "SELECT 5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Interpolate(tt.bind, tt.input, tt.args)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestInterpolate_errors(t *testing.T) {
+	t.Run("missing argument", func(t *testing.T) {
+		_, err := Interpolate(BindDollar, "SELECT $1, $2", []any{1})
+		assert.ErrorContains(t, err, "missing argument for placeholder '$2'")
+
+		_, err = Interpolate(BindQuestion, "SELECT ?, ?", []any{1})
+		assert.ErrorContains(t, err, "missing argument for placeholder '?'")
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		_, err := Interpolate(BindQuestion, "SELECT ?", []any{struct{}{}})
+		assert.ErrorContains(t, err, "placeholder '?': unsupported type struct {}")
+	})
+
+	t.Run("unknown bind", func(t *testing.T) {
+		_, err := Interpolate(BindUnknown, "SELECT 1", nil)
+		assert.ErrorContains(t, err, "unknown bind")
+	})
+}
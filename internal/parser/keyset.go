@@ -0,0 +1,140 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Keyset appends a keyset pagination clause to query, filtering by afterCol
+// greater than afterVal, ordering by afterCol and limiting the rows.
+// If afterVal is nil, the filter is omitted, which is the first page.
+// If query already has a WHERE clause, its predicate is parenthesized and joined
+// with the filter by AND, so OR conditions keep applying to every page. The filter
+// is placed before a GROUP BY, HAVING or WINDOW clause, if any, and placeholders
+// are numbered after the ones already in query:
+//
+//	Keyset(BindDollar, "SELECT * FROM user WHERE active = $1", "id", 42, 10)
+//	// Output: "SELECT * FROM user WHERE (active = $1) AND id > $2 ORDER BY id LIMIT $3", []any{42, 10}
+//
+// With [BindQuestion], those clauses must not have placeholders, as the ones
+// of the filter would be out of order.
+func Keyset(bind Bind, query, afterCol string, afterVal any, limit int) (string, []any) {
+	placeholder, _, _ := getBindInfo(bind)
+	if placeholder == 0 {
+		panic(fmt.Sprintf("sqlz/parser: unknown bind: %d", bind))
+	}
+
+	count := CountPlaceholders(bind, query)
+	next := func() string {
+		count++
+		switch bind {
+		case BindAt, BindNamedAt:
+			return "@p" + strconv.Itoa(count)
+		case BindColon:
+			return ":" + strconv.Itoa(count)
+		case BindDollar:
+			return "$" + strconv.Itoa(count)
+		}
+		return "?"
+	}
+
+	query = strings.TrimRightFunc(query, unicode.IsSpace)
+
+	var sb strings.Builder
+	var args []any
+	if afterVal != nil {
+		where := topLevelKeyword(query, "WHERE")
+		end := filterEnd(query, max(where, 0))
+
+		if where > -1 {
+			predicate := strings.TrimSpace(query[where+len("WHERE") : end])
+			sb.WriteString(query[:where] + "WHERE (" + predicate + ") AND ")
+		} else {
+			sb.WriteString(strings.TrimRightFunc(query[:end], unicode.IsSpace) + " WHERE ")
+		}
+		sb.WriteString(afterCol + " > " + next())
+		args = append(args, afterVal)
+
+		if end < len(query) {
+			sb.WriteString(" " + query[end:])
+		}
+	} else {
+		sb.WriteString(query)
+	}
+
+	sb.WriteString(" ORDER BY " + afterCol)
+
+	// SQL Server and Oracle don't support LIMIT
+	if bind == BindAt || bind == BindNamedAt || bind == BindColon {
+		sb.WriteString(" OFFSET 0 ROWS FETCH NEXT " + next() + " ROWS ONLY")
+	} else {
+		sb.WriteString(" LIMIT " + next())
+	}
+	args = append(args, limit)
+
+	return sb.String(), args
+}
+
+// filterEnd returns the index of the first GROUP BY, HAVING or WINDOW clause
+// of query from offset, where the WHERE predicate ends, or the length of query.
+func filterEnd(query string, offset int) int {
+	end := len(query)
+	for _, keyword := range []string{"GROUP", "HAVING", "WINDOW"} {
+		if i := topLevelKeyword(query[offset:], keyword); i > -1 {
+			end = min(end, offset+i)
+		}
+	}
+	return end
+}
+
+// topLevelKeyword returns the index of the first keyword of query outside
+// parentheses and literals, ignoring the ones of subqueries, or -1.
+func topLevelKeyword(query, keyword string) int {
+	var quote rune
+	depth := 0
+	start := -1
+
+	isKeyword := func(end int) bool {
+		word := start > -1 && depth == 0 && strings.EqualFold(query[start:end], keyword)
+		if !word {
+			start = -1
+		}
+		return word
+	}
+
+	for i, ch := range query {
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+			continue
+
+		case unicode.IsLetter(ch) || unicode.IsDigit(ch) || ch == '_':
+			if start == -1 {
+				start = i
+			}
+			continue
+		}
+
+		if isKeyword(i) {
+			return start
+		}
+
+		switch ch {
+		case '\'', '"', '`':
+			quote = ch
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+	}
+
+	if isKeyword(len(query)) {
+		return start
+	}
+	return -1
+}
@@ -0,0 +1,137 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyset(t *testing.T) {
+	tests := []struct {
+		name     string
+		bind     Bind
+		query    string
+		afterVal any
+		expected string
+		args     []any
+	}{
+		{
+			name:     "question",
+			bind:     BindQuestion,
+			query:    "SELECT * FROM user",
+			afterVal: 42,
+			expected: "SELECT * FROM user WHERE id > ? ORDER BY id LIMIT ?",
+			args:     []any{42, 10},
+		},
+		{
+			name:     "dollar",
+			bind:     BindDollar,
+			query:    "SELECT * FROM user",
+			afterVal: 42,
+			expected: "SELECT * FROM user WHERE id > $1 ORDER BY id LIMIT $2",
+			args:     []any{42, 10},
+		},
+		{
+			name:     "at",
+			bind:     BindAt,
+			query:    "SELECT * FROM user",
+			afterVal: 42,
+			expected: "SELECT * FROM user WHERE id > @p1 ORDER BY id OFFSET 0 ROWS FETCH NEXT @p2 ROWS ONLY",
+			args:     []any{42, 10},
+		},
+		{
+			name:     "colon",
+			bind:     BindColon,
+			query:    "SELECT * FROM user",
+			afterVal: 42,
+			expected: "SELECT * FROM user WHERE id > :1 ORDER BY id OFFSET 0 ROWS FETCH NEXT :2 ROWS ONLY",
+			args:     []any{42, 10},
+		},
+		{
+			name:     "first page",
+			bind:     BindDollar,
+			query:    "SELECT * FROM user",
+			afterVal: nil,
+			expected: "SELECT * FROM user ORDER BY id LIMIT $1",
+			args:     []any{10},
+		},
+		{
+			name:     "existing where and placeholders",
+			bind:     BindDollar,
+			query:    "SELECT * FROM user WHERE active = $1 AND role = $2\n",
+			afterVal: 42,
+			expected: "SELECT * FROM user WHERE (active = $1 AND role = $2) AND id > $3 ORDER BY id LIMIT $4",
+			args:     []any{42, 10},
+		},
+		{
+			name:     "existing where at",
+			bind:     BindAt,
+			query:    "SELECT * FROM user WHERE active = @p1",
+			afterVal: 42,
+			expected: "SELECT * FROM user WHERE (active = @p1) AND id > @p2 ORDER BY id OFFSET 0 ROWS FETCH NEXT @p3 ROWS ONLY",
+			args:     []any{42, 10},
+		},
+		{
+			name:     "existing where colon",
+			bind:     BindColon,
+			query:    "SELECT * FROM user WHERE active = :active",
+			afterVal: 42,
+			expected: "SELECT * FROM user WHERE (active = :active) AND id > :2 ORDER BY id OFFSET 0 ROWS FETCH NEXT :3 ROWS ONLY",
+			args:     []any{42, 10},
+		},
+		{
+			name:     "existing where with or",
+			bind:     BindQuestion,
+			query:    "SELECT * FROM user WHERE role = 'admin' OR role = ?",
+			afterVal: 42,
+			expected: "SELECT * FROM user WHERE (role = 'admin' OR role = ?) AND id > ? ORDER BY id LIMIT ?",
+			args:     []any{42, 10},
+		},
+		{
+			name:     "group by and having",
+			bind:     BindDollar,
+			query:    "SELECT id, count(*) FROM user WHERE a = 1 OR b = 2 GROUP BY id HAVING count(*) > $1",
+			afterVal: 42,
+			expected: "SELECT id, count(*) FROM user WHERE (a = 1 OR b = 2) AND id > $2 GROUP BY id HAVING count(*) > $1 ORDER BY id LIMIT $3",
+			args:     []any{42, 10},
+		},
+		{
+			name:     "group by without where",
+			bind:     BindQuestion,
+			query:    "SELECT id FROM user GROUP BY id",
+			afterVal: 42,
+			expected: "SELECT id FROM user WHERE id > ? GROUP BY id ORDER BY id LIMIT ?",
+			args:     []any{42, 10},
+		},
+		{
+			name:     "first page with where",
+			bind:     BindQuestion,
+			query:    "SELECT * FROM user WHERE a = 1 OR b = 2",
+			afterVal: nil,
+			expected: "SELECT * FROM user WHERE a = 1 OR b = 2 ORDER BY id LIMIT ?",
+			args:     []any{10},
+		},
+		{
+			name:     "where in subquery and literal",
+			bind:     BindQuestion,
+			query:    "SELECT * FROM (SELECT * FROM user WHERE active = ?) AS t JOIN x ON x.note = 'where'",
+			afterVal: 42,
+			expected: "SELECT * FROM (SELECT * FROM user WHERE active = ?) AS t JOIN x ON x.note = 'where' WHERE id > ? ORDER BY id LIMIT ?",
+			args:     []any{42, 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := Keyset(tt.bind, tt.query, "id", tt.afterVal, 10)
+			assert.Equal(t, tt.expected, query)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
+
+func TestKeyset_unknownBind(t *testing.T) {
+	assert.PanicsWithValue(t, "sqlz/parser: unknown bind: 0", func() {
+		Keyset(BindUnknown, "SELECT * FROM user", "id", 1, 10)
+	})
+}
@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"reflect"
+)
+
+// InlineLiteral returns the SQL literal of v respecting the dialect of bind,
+// it reports false if v has no dialect-specific literal.
+// Supported values are nil and booleans, pointers are followed:
+//
+//	InlineLiteral(BindDollar, true)   // Output: "TRUE", true
+//	InlineLiteral(BindQuestion, true) // Output: "1", true
+//	InlineLiteral(BindDollar, nil)    // Output: "NULL", true
+func InlineLiteral(bind Bind, v any) (string, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return "NULL", true
+		}
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() {
+		return "NULL", true
+	}
+
+	if rv.Kind() == reflect.Bool {
+		return boolLiteral(bind, rv.Bool()), true
+	}
+
+	return "", false
+}
+
+// boolLiteral returns the boolean literal for the dialect of bind, PostgreSQL
+// has a native boolean type, others are safer with integers.
+func boolLiteral(bind Bind, b bool) string {
+	if bind == BindDollar {
+		if b {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+
+	if b {
+		return "1"
+	}
+	return "0"
+}
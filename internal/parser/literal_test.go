@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInlineLiteral(t *testing.T) {
+	var nilPtr *bool
+	truePtr := new(bool)
+	*truePtr = true
+
+	tests := []struct {
+		name             string
+		input            any
+		expectedDollar   string
+		expectedQuestion string
+		expectedOk       bool
+	}{
+		{
+			name:             "true",
+			input:            true,
+			expectedDollar:   "TRUE",
+			expectedQuestion: "1",
+			expectedOk:       true,
+		},
+		{
+			name:             "false",
+			input:            false,
+			expectedDollar:   "FALSE",
+			expectedQuestion: "0",
+			expectedOk:       true,
+		},
+		{
+			name:             "pointer to bool",
+			input:            truePtr,
+			expectedDollar:   "TRUE",
+			expectedQuestion: "1",
+			expectedOk:       true,
+		},
+		{
+			name:             "nil",
+			input:            nil,
+			expectedDollar:   "NULL",
+			expectedQuestion: "NULL",
+			expectedOk:       true,
+		},
+		{
+			name:             "nil pointer",
+			input:            nilPtr,
+			expectedDollar:   "NULL",
+			expectedQuestion: "NULL",
+			expectedOk:       true,
+		},
+		{
+			name:       "unsupported",
+			input:      42,
+			expectedOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := InlineLiteral(BindDollar, tt.input)
+			assert.Equal(t, tt.expectedOk, ok)
+			assert.Equal(t, tt.expectedDollar, got)
+
+			got, ok = InlineLiteral(BindQuestion, tt.input)
+			assert.Equal(t, tt.expectedOk, ok)
+			assert.Equal(t, tt.expectedQuestion, got)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+)
+
+// IsMutationWithoutWhere reports whether query is an UPDATE or DELETE statement
+// without a WHERE clause, including after a leading WITH clause. Keywords inside
+// string literals, quoted identifiers, comments and subqueries are ignored:
+//
+//	IsMutationWithoutWhere("DELETE FROM user")                // Output: true
+//	IsMutationWithoutWhere("DELETE FROM user WHERE id = 1")   // Output: false
+//	IsMutationWithoutWhere("UPDATE user SET name = 'where'")  // Output: true
+//	IsMutationWithoutWhere("DELETE FROM user -- WHERE id = 1") // Output: true
+func IsMutationWithoutWhere(query string) bool {
+	words := keywords(query)
+	if len(words) > 0 && words[0] == "WITH" {
+		// the statement follows the common table expressions, which are in parentheses
+		words = words[1:]
+		for len(words) > 0 && !isStatementKeyword(words[0]) {
+			words = words[1:]
+		}
+	}
+
+	if len(words) == 0 {
+		return false
+	}
+
+	if words[0] != "UPDATE" && words[0] != "DELETE" {
+		return false
+	}
+
+	for _, word := range words[1:] {
+		if word == "WHERE" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isStatementKeyword reports whether word starts a statement following a WITH clause.
+func isStatementKeyword(word string) bool {
+	switch word {
+	case "SELECT", "INSERT", "UPDATE", "DELETE", "MERGE":
+		return true
+	}
+	return false
+}
+
+// keywords returns the uppercased words of query outside parentheses, comments
+// and literals delimited by single quotes, double quotes or backticks.
+func keywords(query string) []string {
+	var words []string
+	var quote, comment rune
+	depth, commentStart := 0, 0
+	start := -1
+
+	flush := func(end int) {
+		if start > -1 {
+			if depth == 0 {
+				words = append(words, strings.ToUpper(query[start:end]))
+			}
+			start = -1
+		}
+	}
+
+	for i, ch := range query {
+		switch {
+		case comment != 0:
+			if comment == '-' && ch == '\n' || comment == '*' && ch == '/' && i-1 > commentStart+1 && query[i-1] == '*' {
+				comment = 0
+			}
+
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+
+		case ch == '\'' || ch == '"' || ch == '`':
+			flush(i)
+			quote = ch
+
+		case ch == '-' && strings.HasPrefix(query[i+1:], "-"),
+			ch == '/' && strings.HasPrefix(query[i+1:], "*"):
+			flush(i)
+			comment, commentStart = rune(query[i+1]), i
+
+		case unicode.IsLetter(ch) || unicode.IsDigit(ch) || ch == '_':
+			if start == -1 {
+				start = i
+			}
+
+		default:
+			flush(i)
+			switch ch {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+		}
+	}
+	flush(len(query))
+
+	return words
+}
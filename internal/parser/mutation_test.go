@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsMutationWithoutWhere(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{name: "empty", input: "", expected: false},
+		{name: "select", input: "SELECT * FROM user", expected: false},
+		{name: "insert", input: "INSERT INTO user (id) VALUES (1)", expected: false},
+		{name: "delete without where", input: "DELETE FROM user", expected: true},
+		{name: "delete with where", input: "DELETE FROM user WHERE id = 1", expected: false},
+		{name: "lowercase delete", input: "delete from user", expected: true},
+		{name: "lowercase where", input: "delete from user where id = 1", expected: false},
+		{name: "update without where", input: "UPDATE user SET active = 0", expected: true},
+		{name: "update with where", input: "UPDATE user SET active = 0 WHERE id = ?", expected: false},
+		{name: "leading whitespace", input: "\n\t  DELETE FROM user", expected: true},
+		{name: "where in string literal", input: "UPDATE user SET name = 'where'", expected: true},
+		{name: "where in quoted identifier", input: `UPDATE user SET "where" = 1`, expected: true},
+		{name: "where in backticks", input: "UPDATE user SET `where` = 1", expected: true},
+		{name: "where as column prefix", input: "UPDATE user SET where_from = 1", expected: true},
+		{name: "where after newline", input: "DELETE FROM user\nWHERE\tid = 1", expected: false},
+		{name: "where after parenthesis", input: "DELETE FROM user WHERE(id = 1)", expected: false},
+		{name: "where in line comment", input: "DELETE FROM user -- WHERE id = 1", expected: true},
+		{name: "where in block comment", input: "DELETE FROM user /* WHERE id = 1 */", expected: true},
+		{name: "where after line comment", input: "DELETE FROM user -- it's fine\nWHERE id = 1", expected: false},
+		{name: "where after block comment", input: "DELETE /**/ FROM user /* x */ WHERE id = 1", expected: false},
+		{name: "where in subquery", input: "UPDATE user SET a = (SELECT b FROM u WHERE u.id = 1)", expected: true},
+		{name: "where after subquery", input: "UPDATE user SET a = (SELECT b FROM u) WHERE id = 1", expected: false},
+		{name: "with delete without where", input: "WITH x AS (SELECT id FROM u WHERE a = 1) DELETE FROM user", expected: true},
+		{name: "with delete with where", input: "WITH x AS (SELECT id FROM u) DELETE FROM user WHERE id IN (SELECT id FROM x)", expected: false},
+		{name: "with recursive update", input: "WITH RECURSIVE x (id) AS (SELECT 1), y AS (SELECT 2) UPDATE user SET a = 1", expected: true},
+		{name: "with select", input: "WITH x AS (SELECT 1) SELECT * FROM x", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsMutationWithoutWhere(tt.input))
+		})
+	}
+}
@@ -19,6 +19,7 @@ const (
 	BindColon         // placeholder ':name'
 	BindDollar        // placeholder '$1'
 	BindQuestion      // placeholder '?'
+	BindNamedAt       // placeholder '@name'
 )
 
 // Parser is an SQL query parser mostly for named queries.
@@ -36,6 +37,9 @@ type Parser struct {
 	// the slice length by ident index which have an "IN" clause.
 	// if there's items in this map we have to duplicate placeholder by count.
 	inClauseCountByIndex map[int]int
+
+	// placeholder index of the first slice found outside an "IN" clause, or -1.
+	sliceOutsideIn int
 }
 
 func (p *Parser) parse(skipIdents bool) (string, []string) {
@@ -121,6 +125,9 @@ func (p *Parser) tryReadIdent(skipIdents bool) {
 		case BindDollar:
 			p.output.WriteRune('$')
 			p.output.WriteString(strconv.Itoa(p.bindCount))
+		case BindNamedAt:
+			p.output.WriteRune('@')
+			p.output.WriteString(namedAtIdent(ident, i, count))
 		}
 
 		isLast := i == count-1
@@ -167,6 +174,12 @@ func (p *Parser) parseInNative() string {
 func (p *Parser) tryReadPlaceholder() {
 	placeholder, readStrategy, isNumbered := getBindInfo(p.bind)
 
+	// backslash escaped question mark, read next
+	if p.bind == BindQuestion && p.ch == '\\' && p.peek() == placeholder {
+		p.read()
+		return
+	}
+
 	if p.ch != rune(placeholder) {
 		return
 	}
@@ -184,9 +197,13 @@ func (p *Parser) tryReadPlaceholder() {
 		p.read()
 	}
 	p.identCount++
-	count := p.inClauseCountByIndex[p.identCount-1]
+	count, isSlice := p.inClauseCountByIndex[p.identCount-1]
 	count = cmp.Or(count, 1)
 
+	if isSlice && p.sliceOutsideIn == -1 && !p.insideInClause() {
+		p.sliceOutsideIn = p.identCount - 1
+	}
+
 	for i := range count {
 		p.bindCount++
 		p.output.WriteRune(placeholder)
@@ -196,6 +213,11 @@ func (p *Parser) tryReadPlaceholder() {
 		if p.bind == BindColon {
 			p.output.WriteString(ident)
 		}
+		if p.bind == BindNamedAt {
+			ident := namedAtIdent(ident, i, count)
+			p.output.WriteString(ident)
+			p.idents = append(p.idents, ident)
+		}
 		if isNumbered {
 			p.output.WriteString(strconv.Itoa(p.bindCount))
 		}
@@ -207,6 +229,32 @@ func (p *Parser) tryReadPlaceholder() {
 	}
 }
 
+// insideInClause reports whether the output so far ends inside the parentheses
+// of an "IN" clause, e.g. "id IN (" or "id NOT IN (1, ".
+func (p *Parser) insideInClause() bool {
+	output := p.output.String()
+	depth := 0
+
+	for i := len(output) - 1; i >= 0; i-- {
+		switch output[i] {
+		case ')':
+			depth++
+
+		case '(':
+			if depth > 0 {
+				depth--
+				continue
+			}
+
+			before := strings.TrimRightFunc(output[:i], unicode.IsSpace)
+			start := strings.LastIndexFunc(before, func(ch rune) bool { return !isIdentChar(ch) })
+			return strings.EqualFold(before[start+1:], "IN")
+		}
+	}
+
+	return false
+}
+
 type strategyFunc = func(ch rune) bool
 
 func getBindInfo(bind Bind) (rune, strategyFunc, bool) {
@@ -231,11 +279,26 @@ func getBindInfo(bind Bind) (rune, strategyFunc, bool) {
 
 	case BindQuestion:
 		placeholder = '?'
+
+	case BindNamedAt:
+		placeholder = '@'
+		readStrategy = isIdentChar
 	}
 
 	return placeholder, readStrategy, isNumbered
 }
 
+// namedAtIdent returns the [BindNamedAt] placeholder name of ident, dots are
+// not valid in names, so nested idents like "user.id" become "user_id".
+// Slices spread into count placeholders are suffixed by their 1-based position.
+func namedAtIdent(ident string, i, count int) string {
+	ident = strings.ReplaceAll(ident, ".", "_")
+	if count > 1 {
+		ident += "_" + strconv.Itoa(i+1)
+	}
+	return ident
+}
+
 func isIdentChar(ch rune) bool {
 	return ch == '_' || ch == '.' || unicode.IsLetter(ch) || unicode.IsNumber(ch)
 }
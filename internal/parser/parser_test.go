@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -248,6 +251,30 @@ func TestParseIn_Question(t *testing.T) {
 			expectedArgs:   []any{4, 8, 16, 8, 16, 32, 64},
 			expectError:    false,
 		},
+		{
+			name:           "multiple bind var and one backslash escaped",
+			input:          `SELECT * FROM user WHERE name = '\?' AND id IN (?) AND band_id IN (?)`,
+			args:           []any{[]int{4, 8, 16}, []int{8, 16, 32, 64}},
+			expectedOutput: "SELECT * FROM user WHERE name = '?' AND id IN (?,?,?) AND band_id IN (?,?,?,?)",
+			expectedArgs:   []any{4, 8, 16, 8, 16, 32, 64},
+			expectError:    false,
+		},
+		{
+			name:           "both escapes mixed",
+			input:          `SELECT data ?? 'a', data \? 'b' FROM user WHERE id IN (?)`,
+			args:           []any{[]int{4, 8}},
+			expectedOutput: "SELECT data ? 'a', data ? 'b' FROM user WHERE id IN (?,?)",
+			expectedArgs:   []any{4, 8},
+			expectError:    false,
+		},
+		{
+			name:           "backslash not before question mark is kept",
+			input:          `SELECT * FROM user WHERE path = 'C:\temp' AND id IN (?)`,
+			args:           []any{[]int{4, 8}},
+			expectedOutput: `SELECT * FROM user WHERE path = 'C:\temp' AND id IN (?,?)`,
+			expectedArgs:   []any{4, 8},
+			expectError:    false,
+		},
 		{
 			name:           "should not spread []byte",
 			input:          "SELECT * FROM user WHERE json = ?",
@@ -256,12 +283,26 @@ func TestParseIn_Question(t *testing.T) {
 			expectedArgs:   []any{[]byte{4, 8, 16}},
 			expectError:    false,
 		},
+		{
+			name:           "should not spread json.RawMessage",
+			input:          "SELECT * FROM user WHERE id IN (?) AND json = ?",
+			args:           []any{[]int{4, 8}, json.RawMessage(`{"a":1}`)},
+			expectedOutput: "SELECT * FROM user WHERE id IN (?,?) AND json = ?",
+			expectedArgs:   []any{4, 8, json.RawMessage(`{"a":1}`)},
+			expectError:    false,
+		},
 		{
 			name:        "wrong number of placeholders",
 			input:       "SELECT * FROM user WHERE name = ? AND id IN (?)",
 			args:        []any{4, []int{8, 16, 32, 64}, 8},
 			expectError: true,
 		},
+		{
+			name:        "slice outside in clause expects error",
+			input:       "SELECT * FROM user WHERE id = ?",
+			args:        []any{[]int{4, 8, 16}},
+			expectError: true,
+		},
 		{
 			name:        "empty slice expects error",
 			input:       "SELECT * FROM user WHERE id IN (?)",
@@ -532,6 +573,44 @@ func TestParseIn_Colon(t *testing.T) {
 	assert.Equal(t, expectedArgs, args)
 }
 
+func TestParse_NamedAt(t *testing.T) {
+	query, idents := Parse(BindNamedAt, "SELECT * FROM user WHERE id = :id AND name = :name OR parent_id = :id")
+	assert.Equal(t, "SELECT * FROM user WHERE id = @id AND name = @name OR parent_id = @id", query)
+	assert.Equal(t, []string{"id", "name", "id"}, idents)
+
+	query = ParseQuery(BindNamedAt, "SELECT @@ROWCOUNT, * FROM user WHERE id = :user.id AND '::id' <> ''")
+	assert.Equal(t, "SELECT @@ROWCOUNT, * FROM user WHERE id = @user_id AND ':id' <> ''", query)
+
+	idents = ParseIdents(BindNamedAt, "SELECT * FROM user WHERE id = :user.id")
+	assert.Equal(t, []string{"user.id"}, idents)
+}
+
+func TestParseIn_NamedAt(t *testing.T) {
+	input := "SELECT * FROM user WHERE name = @name AND id IN (@ids) AND '@@p' <> ''"
+	inputArgs := []any{"Alice", []int{4, 8, 16}}
+	expected := "SELECT * FROM user WHERE name = @name AND id IN (@ids_1,@ids_2,@ids_3) AND '@p' <> ''"
+	expectedArgs := []any{"Alice", 4, 8, 16}
+
+	query, args, err := ParseInClause(BindNamedAt, input, inputArgs)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, query)
+	assert.Equal(t, expectedArgs, args)
+
+	query, args, err = ParseInClause(BindNamedAt, "SELECT * FROM user WHERE id IN (@ids)", []any{[]int{4}})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM user WHERE id IN (@ids)", query)
+	assert.Equal(t, []any{4}, args)
+}
+
+func TestPlaceholderNames(t *testing.T) {
+	assert.Nil(t, PlaceholderNames("SELECT 1"))
+	assert.Equal(t,
+		[]string{"name", "ids_1", "ids_2", "name"},
+		PlaceholderNames("SELECT * FROM user WHERE name = @name AND id IN (@ids_1,@ids_2) OR alias = @name"),
+	)
+	assert.Equal(t, []string{"id"}, PlaceholderNames("SELECT @@ROWCOUNT, '@@name' FROM user WHERE id = @id"))
+}
+
 func TestParseNamed_Concurrency(t *testing.T) {
 	input := "SELECT * FROM user WHERE id = :id"
 	expectedQuery := "SELECT * FROM user WHERE id = ?"
@@ -546,6 +625,136 @@ func TestParseNamed_Concurrency(t *testing.T) {
 	}
 }
 
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "already normalized",
+			input:    "SELECT * FROM user WHERE id = ?",
+			expected: "SELECT * FROM user WHERE id = ?",
+		},
+		{
+			name:     "collapse whitespace",
+			input:    "\n\tSELECT *\n\t\tFROM user\n\tWHERE   id = ?\n",
+			expected: "SELECT * FROM user WHERE id = ?",
+		},
+		{
+			name:     "preserve single quoted literal",
+			input:    "SELECT *  FROM user WHERE name = 'Alice   in\n  Wonderland'",
+			expected: "SELECT * FROM user WHERE name = 'Alice   in\n  Wonderland'",
+		},
+		{
+			name:     "preserve escaped quote in literal",
+			input:    "SELECT  'it''s   fine',   \"a   b\",  `c   d`",
+			expected: "SELECT 'it''s   fine', \"a   b\", `c   d`",
+		},
+		{
+			name:     "quotes inside different quotes",
+			input:    `SELECT  "it's    ok"   FROM   user`,
+			expected: `SELECT "it's    ok" FROM user`,
+		},
+		{
+			name:     "keep newline after line comment",
+			input:    "SELECT *\n  FROM user -- active   only\n  WHERE   id = ?",
+			expected: "SELECT * FROM user -- active   only\nWHERE id = ?",
+		},
+		{
+			name:     "quote inside line comment",
+			input:    "SELECT  * -- it's the user\nFROM   user  WHERE name = 'a  b'",
+			expected: "SELECT * -- it's the user\nFROM user WHERE name = 'a  b'",
+		},
+		{
+			name:     "quote inside block comment",
+			input:    "SELECT  * /* it's   the user */  FROM   user",
+			expected: "SELECT * /* it's   the user */ FROM user",
+		},
+		{
+			name:     "backslash escaped quote",
+			input:    `SELECT  'a\'  b'   FROM user WHERE  c = "d\"  e"`,
+			expected: `SELECT 'a\'  b' FROM user WHERE c = "d\"  e"`,
+		},
+		{
+			name:     "escaped backslash before closing quote",
+			input:    `SELECT  'a\\'   FROM user`,
+			expected: `SELECT 'a\\' FROM user`,
+		},
+		{
+			name:     "dollar quoted string",
+			input:    "SELECT  $$a  'b'  c$$,   $fn$ x  $$ y $fn$   FROM user",
+			expected: "SELECT $$a  'b'  c$$, $fn$ x  $$ y $fn$ FROM user",
+		},
+		{
+			name:     "dollar placeholders",
+			input:    "SELECT *  FROM user WHERE id = $1   AND name = $2",
+			expected: "SELECT * FROM user WHERE id = $1 AND name = $2",
+		},
+		{
+			name:     "comment marker inside literal",
+			input:    "SELECT  '--  not a comment'   FROM user",
+			expected: "SELECT '--  not a comment' FROM user",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Normalize(tt.input))
+		})
+	}
+}
+
+func TestStripTrailingSemicolon(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "no semicolon",
+			input:    "SELECT * FROM user WHERE id = ?",
+			expected: "SELECT * FROM user WHERE id = ?",
+		},
+		{
+			name:     "trailing semicolon",
+			input:    "SELECT * FROM user WHERE id = ?;",
+			expected: "SELECT * FROM user WHERE id = ?",
+		},
+		{
+			name:     "trailing semicolon with whitespace",
+			input:    "SELECT * FROM user WHERE id = ? ;\n",
+			expected: "SELECT * FROM user WHERE id = ?",
+		},
+		{
+			name:     "only one semicolon is removed",
+			input:    "SELECT 1;;",
+			expected: "SELECT 1;",
+		},
+		{
+			name:     "semicolon inside literal",
+			input:    "SELECT * FROM user WHERE name = ';'",
+			expected: "SELECT * FROM user WHERE name = ';'",
+		},
+		{
+			name:     "semicolon inside literal and trailing",
+			input:    "INSERT INTO note (text) VALUES ('a;b');",
+			expected: "INSERT INTO note (text) VALUES ('a;b')",
+		},
+		{
+			name:     "semicolon inside unterminated literal",
+			input:    "SELECT 'a;",
+			expected: "SELECT 'a;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, StripTrailingSemicolon(tt.input))
+		})
+	}
+}
+
 // BenchmarkParse-12    	    3147	    367662 ns/op	  289145 B/op	      16 allocs/op
 func BenchmarkParse(b *testing.B) {
 	var sb strings.Builder
@@ -590,3 +799,107 @@ func BenchmarkParseIdents(b *testing.B) {
 		_ = ParseIdents(BindQuestion, input)
 	}
 }
+
+func TestParseInClause_sliceOutsideIn(t *testing.T) {
+	t.Run("recognized contexts", func(t *testing.T) {
+		inputs := []string{
+			"SELECT * FROM user WHERE id IN (?)",
+			"SELECT * FROM user WHERE id in(?)",
+			"SELECT * FROM user WHERE id NOT IN (?)",
+			"SELECT * FROM user WHERE id IN (1, ?)",
+			"SELECT * FROM user WHERE id IN (ABS(-1), ?)",
+			"SELECT * FROM user WHERE (id IN (?))",
+		}
+		for _, input := range inputs {
+			_, args, err := ParseInClause(BindQuestion, input, []any{[]int{4, 8}})
+			require.NoError(t, err, input)
+			assert.Equal(t, []any{4, 8}, args)
+		}
+	})
+
+	t.Run("equal position", func(t *testing.T) {
+		_, _, err := ParseInClause(BindDollar, "SELECT * FROM user WHERE name = $1 AND id = $2", []any{"Alice", []int{4, 8}})
+		var sliceErr *SliceOutsideInError
+		require.ErrorAs(t, err, &sliceErr)
+		assert.Equal(t, 1, sliceErr.Index)
+		assert.ErrorContains(t, err, "slice arg for non-IN parameter #2")
+	})
+
+	t.Run("inside other parentheses", func(t *testing.T) {
+		inputs := []string{
+			"SELECT * FROM user WHERE id = (?)",
+			"SELECT * FROM user WHERE id = ANY(?)",
+			"SELECT * FROM user WHERE id IN (1) AND (name = ?)",
+			"SELECT * FROM user WHERE is_admin(?)", // ends with "in", but is not the keyword
+		}
+		for _, input := range inputs {
+			_, _, err := ParseInClause(BindQuestion, input, []any{[]int{4, 8}})
+			assert.ErrorContains(t, err, "slice arg for non-IN parameter", input)
+		}
+	})
+}
+
+func TestIdentIndexes(t *testing.T) {
+	idents := []string{"name", "ids", "status", "name"}
+
+	got := IdentIndexes(idents, []any{"Alice", []int{4, 8, 16}, "active", "Alice"})
+	assert.Equal(t, map[string]int{"name": 0, "ids": 1, "status": 4}, got)
+
+	got = IdentIndexes(idents, []any{"Alice", []byte("raw"), "active", "Alice"})
+	assert.Equal(t, map[string]int{"name": 0, "ids": 1, "status": 2}, got)
+
+	got = IdentIndexes(idents, nil)
+	assert.Equal(t, map[string]int{"name": 0, "ids": 1, "status": 2}, got)
+}
+
+func TestCountPlaceholders(t *testing.T) {
+	assert.Equal(t, 0, CountPlaceholders(BindQuestion, "SELECT 1"))
+	assert.Equal(t, 2, CountPlaceholders(BindQuestion, "SELECT * FROM user WHERE id = ? AND name = ?"))
+	assert.Equal(t, 1, CountPlaceholders(BindQuestion, `SELECT data ?? 'key', data \? 'key' FROM doc WHERE id = ?`))
+	assert.Equal(t, 3, CountPlaceholders(BindDollar, "SELECT * FROM user WHERE id IN ($1, $2) OR parent_id = $1"))
+	assert.Equal(t, 2, CountPlaceholders(BindAt, "SELECT * FROM user WHERE id = @p1 AND name = @p2"))
+	assert.Equal(t, 2, CountPlaceholders(BindColon, "SELECT * FROM user WHERE id = :id AND name = :name"))
+	assert.Equal(t, 2, CountPlaceholders(BindNamedAt, "SELECT * FROM user WHERE id = @id AND name = @name"))
+}
+
+func TestRenumber(t *testing.T) {
+	assert.Equal(t, "INSERT INTO t (a) VALUES ($1),($2),($3)", Renumber(BindDollar, "INSERT INTO t (a) VALUES ($1),($1),($1)"))
+	assert.Equal(t, "INSERT INTO t (a, b) VALUES (@p1, 'x'),(@p2, 'x')", Renumber(BindAt, "INSERT INTO t (a, b) VALUES (@p1, 'x'),(@p1, 'x')"))
+	assert.Equal(t, "INSERT INTO t (a) VALUES (?),(?)", Renumber(BindQuestion, "INSERT INTO t (a)\n\tVALUES (?),(?)"))
+	assert.Equal(t, "SELECT data ?? 'key' FROM doc WHERE id = $1", Renumber(BindDollar, "SELECT data ?? 'key' FROM doc WHERE id = $3"))
+}
+
+// status is an enum stored as an integer, implementing [driver.Valuer].
+type status int
+
+func (s status) Value() (driver.Value, error) { return int64(s), nil }
+
+func TestParseInClause_pointers(t *testing.T) {
+	one, two := 1, 2
+	nullTime := sql.NullTime{}
+
+	t.Run("slice of pointers", func(t *testing.T) {
+		query, args, err := ParseInClause(BindQuestion, "SELECT * FROM user WHERE id IN (?)", []any{[]*int{&one, nil, &two}})
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM user WHERE id IN (?,?,?)", query)
+		assert.Equal(t, []any{1, nil, 2}, args)
+	})
+
+	t.Run("slice of any holding pointers", func(t *testing.T) {
+		_, args, err := ParseInClause(BindDollar, "SELECT * FROM user WHERE id IN ($1)", []any{[]any{&one, nil, int64(2)}})
+		require.NoError(t, err)
+		assert.Equal(t, []any{1, nil, int64(2)}, args)
+	})
+
+	t.Run("valuers are kept", func(t *testing.T) {
+		_, args, err := ParseInClause(BindQuestion, "SELECT * FROM user WHERE status IN (?)", []any{[]status{1, 2}})
+		require.NoError(t, err)
+		assert.Equal(t, []any{status(1), status(2)}, args)
+	})
+
+	t.Run("valuer pointers are kept", func(t *testing.T) {
+		_, args, err := ParseInClause(BindQuestion, "SELECT * FROM user WHERE deleted_at IN (?)", []any{[]*sql.NullTime{&nullTime, nil}})
+		require.NoError(t, err)
+		assert.Equal(t, []any{&nullTime, nil}, args)
+	})
+}
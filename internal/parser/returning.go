@@ -0,0 +1,19 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+)
+
+// StripReturning returns query without its RETURNING clause, which must be
+// the last one, and whether it had one; RETURNING of subqueries and literals are ignored.
+//
+//	StripReturning("INSERT INTO user (name) VALUES (?) RETURNING id")
+//	// Output: "INSERT INTO user (name) VALUES (?)", true
+func StripReturning(query string) (string, bool) {
+	i := topLevelKeyword(query, "RETURNING")
+	if i == -1 {
+		return query, false
+	}
+	return strings.TrimRightFunc(query[:i], unicode.IsSpace), true
+}
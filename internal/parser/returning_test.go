@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripReturning(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  string
+		returning bool
+	}{
+		{
+			name:      "no returning",
+			input:     "INSERT INTO user (name) VALUES (?)",
+			expected:  "INSERT INTO user (name) VALUES (?)",
+			returning: false,
+		},
+		{
+			name:      "returning",
+			input:     "INSERT INTO user (name) VALUES ($1), ($2)\n\treturning id, created_at",
+			expected:  "INSERT INTO user (name) VALUES ($1), ($2)",
+			returning: true,
+		},
+		{
+			name:      "returning in literal",
+			input:     "INSERT INTO note (text) VALUES ('returning')",
+			expected:  "INSERT INTO note (text) VALUES ('returning')",
+			returning: false,
+		},
+		{
+			name:      "returning in subquery",
+			input:     "WITH t AS (DELETE FROM tmp RETURNING id) INSERT INTO user (id) SELECT id FROM t",
+			expected:  "WITH t AS (DELETE FROM tmp RETURNING id) INSERT INTO user (id) SELECT id FROM t",
+			returning: false,
+		},
+		{
+			name:      "identifier containing returning",
+			input:     "INSERT INTO user (is_returning) VALUES (?)",
+			expected:  "INSERT INTO user (is_returning) VALUES (?)",
+			returning: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, returning := StripReturning(tt.input)
+			assert.Equal(t, tt.expected, query)
+			assert.Equal(t, tt.returning, returning)
+		})
+	}
+}
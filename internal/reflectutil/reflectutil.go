@@ -2,6 +2,7 @@ package reflectutil
 
 import (
 	"reflect"
+	"strconv"
 )
 
 // Type is similar to [reflect.Kind], but adds support for type of slices.
@@ -20,6 +21,28 @@ const (
 	SliceStruct    = Slice | Struct
 )
 
+func (t Type) String() string {
+	switch t {
+	case Invalid:
+		return "Invalid"
+	case Primitive:
+		return "Primitive"
+	case Map:
+		return "Map"
+	case Struct:
+		return "Struct"
+	case Slice:
+		return "Slice"
+	case SlicePrimitive:
+		return "SlicePrimitive"
+	case SliceMap:
+		return "SliceMap"
+	case SliceStruct:
+		return "SliceStruct"
+	}
+	return "Type(" + strconv.FormatUint(uint64(t), 10) + ")"
+}
+
 func (t Type) IsSlice() bool {
 	return (t & Slice) != 0
 }
@@ -52,6 +75,10 @@ func TypeOf(t reflect.Type) Type {
 		return Struct
 
 	case reflect.Slice:
+		// byte slices are scanned as a single value, including named ones like "type Blob []byte"
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Primitive
+		}
 		if et := TypeOf(t.Elem()); et > 0 {
 			return Slice | et
 		}
@@ -78,6 +78,13 @@ func TestTypeOfAny(t *testing.T) {
 		assert.Equal(t, SliceStruct, TypeOfAny(v))
 	})
 
+	t.Run("bytes", func(t *testing.T) {
+		type Blob []byte
+		assert.Equal(t, Primitive, TypeOfAny([]byte{}))
+		assert.Equal(t, Primitive, TypeOfAny(Blob{}))
+		assert.Equal(t, SlicePrimitive, TypeOfAny([]Blob{}))
+	})
+
 	t.Run("invalid", func(t *testing.T) {
 		var v func()
 		assert.Equal(t, Invalid, TypeOfAny(v))
@@ -109,6 +116,13 @@ func TestTypeOfAny(t *testing.T) {
 	})
 }
 
+func TestType_String(t *testing.T) {
+	assert.Equal(t, "Invalid", Invalid.String())
+	assert.Equal(t, "Primitive", Primitive.String())
+	assert.Equal(t, "SliceStruct", SliceStruct.String())
+	assert.Equal(t, "Type(28)", (Struct | Map | Slice).String())
+}
+
 func TestInit(t *testing.T) {
 	t.Run("non-pointer value returned as-is", func(t *testing.T) {
 		v := reflect.ValueOf(42)
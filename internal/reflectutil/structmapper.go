@@ -1,33 +1,124 @@
 package reflectutil
 
 import (
+	"maps"
 	"reflect"
+	"slices"
 	"strings"
 )
 
+// RowNumOption is the struct tag option marking a field that receives the
+// 1-based row number instead of a column, as in `db:",rownum"`.
+const RowNumOption = "rownum"
+
+// CSVOption is the struct tag option marking a string slice field that is
+// scanned from a delimited string column, as in `db:"tags,csv"`.
+const CSVOption = "csv"
+
+// JSONOption is the struct tag option marking a field that is bound as its JSON
+// encoding and scanned by decoding a JSON column, as in `db:"data,json"`.
+const JSONOption = "json"
+
+// VersionOption is the struct tag option marking an integer field used for
+// optimistic concurrency by generated updates, as in `db:"version,version"`.
+const VersionOption = "version"
+
+// ReadOnlyOption is the struct tag option marking a field that is scanned,
+// but skipped from generated inserts, e.g. database generated ids, as in `db:"id,readonly"`.
+const ReadOnlyOption = "readonly"
+
+// PKOption is the struct tag option marking the primary key field, used to
+// generate lookups by primary key, as in `db:"id,pk"`.
+const PKOption = "pk"
+
+// ExtraOption is the struct tag option marking a map[string]any field whose
+// keys bind the named parameters not found as fields, as in `db:",extra"`.
+const ExtraOption = "extra"
+
+// TimeFormatOption is the struct tag option setting the layout a [time.Time]
+// field is parsed with from a text column, as in `db:"dob,timeformat=2006-01-02"`.
+// As options are comma separated, the layout can't contain a comma.
+const TimeFormatOption = "timeformat"
+
 // structMapper is a helper to map struct fields index by tag/name.
 type structMapper struct {
-	tag        string
-	sep        string
-	nameMapper func(string) string
-	indexByKey map[string][]int
+	tag         string
+	sep         string
+	nameMapper  func(string) string
+	indexByKey  map[string][]int
+	rowNumIndex []int
+	extraIndex  []int
+	ambiguous   map[string]bool
+}
+
+func newStructMapper(tag, sep string, nameMapper func(string) string) *structMapper {
+	return &structMapper{tag, sep, nameMapper, make(map[string][]int), nil, nil, make(map[string]bool)}
 }
 
 // StructFieldMap maps the structType fields, tag is the struct tag to search for,
 // sep is the sepatator for nested structs, and nameMapper transforms the
 // field name in case the tag was not found.
+//
+// Keys follow Go's promotion rules: when multiple fields map to the same key,
+// the shallowest one wins, and if they are at the same depth the key is
+// ambiguous and left out of the map, see [AmbiguousKeys].
 func StructFieldMap(structType reflect.Type, tag, sep string, nameMapper func(string) string) map[string][]int {
 	structType = Deref(structType)
 	if structType.Kind() != reflect.Struct {
 		panic("sqlz/reflectutil: reflect.Type must be a struct, got " + structType.String())
 	}
 
-	sm := &structMapper{tag, sep, nameMapper, make(map[string][]int)}
+	sm := newStructMapper(tag, sep, nameMapper)
 	sm.traverse(structType)
 
 	return sm.indexByKey
 }
 
+// AmbiguousKeys returns the sorted keys that [StructFieldMap] leaves out
+// because multiple fields map to them at the same depth, e.g. two embedded
+// structs with a field of the same name.
+func AmbiguousKeys(structType reflect.Type, tag, sep string, nameMapper func(string) string) []string {
+	structType = Deref(structType)
+	if structType.Kind() != reflect.Struct {
+		panic("sqlz/reflectutil: reflect.Type must be a struct, got " + structType.String())
+	}
+
+	sm := newStructMapper(tag, sep, nameMapper)
+	sm.traverse(structType)
+
+	keys := slices.Collect(maps.Keys(sm.ambiguous))
+	slices.Sort(keys)
+	return keys
+}
+
+// RowNumIndex returns the index of the first structType field tagged with
+// [RowNumOption], or nil if there's none, tag is the struct tag to search for.
+func RowNumIndex(structType reflect.Type, tag string) []int {
+	structType = Deref(structType)
+	if structType.Kind() != reflect.Struct {
+		panic("sqlz/reflectutil: reflect.Type must be a struct, got " + structType.String())
+	}
+
+	sm := newStructMapper(tag, "", strings.ToLower)
+	sm.traverse(structType)
+
+	return sm.rowNumIndex
+}
+
+// ExtraIndex returns the index of the first structType field tagged with
+// [ExtraOption], or nil if there's none, tag is the struct tag to search for.
+func ExtraIndex(structType reflect.Type, tag string) []int {
+	structType = Deref(structType)
+	if structType.Kind() != reflect.Struct {
+		panic("sqlz/reflectutil: reflect.Type must be a struct, got " + structType.String())
+	}
+
+	sm := newStructMapper(tag, "", strings.ToLower)
+	sm.traverse(structType)
+
+	return sm.extraIndex
+}
+
 type node struct {
 	t     reflect.Type
 	path  []string
@@ -81,13 +172,27 @@ func (sm *structMapper) traverse(t reflect.Type) {
 			}
 
 			curr.index = append(curr.index, field.Index...)
+
+			// row number fields are not mapped to any column
+			if HasTagOption(field, sm.tag, RowNumOption) {
+				if sm.rowNumIndex == nil {
+					sm.rowNumIndex = curr.index
+				}
+				continue
+			}
+
+			// extra fields are not mapped to any column either
+			if HasTagOption(field, sm.tag, ExtraOption) {
+				if sm.extraIndex == nil {
+					sm.extraIndex = curr.index
+				}
+				continue
+			}
+
 			if !field.Anonymous && !inline {
 				curr.path = append(curr.path, name)
 
-				key := strings.Join(curr.path, sm.sep)
-				if _, exists := sm.indexByKey[key]; !exists {
-					sm.indexByKey[key] = curr.index
-				}
+				sm.add(strings.Join(curr.path, sm.sep), curr.index)
 			}
 
 			if fieldType.Kind() == reflect.Struct {
@@ -97,14 +202,32 @@ func (sm *structMapper) traverse(t reflect.Type) {
 	}
 }
 
+// add maps key to index, unless it's already mapped by a shallower field,
+// BFS guarantees existing keys are never deeper than index.
+func (sm *structMapper) add(key string, index []int) {
+	if sm.ambiguous[key] {
+		return
+	}
+
+	existing, exists := sm.indexByKey[key]
+	if !exists {
+		sm.indexByKey[key] = index
+		return
+	}
+
+	if len(existing) == len(index) {
+		sm.ambiguous[key] = true
+		delete(sm.indexByKey, key)
+	}
+}
+
 func fieldTag(field reflect.StructField, structTag string) (tag string, inline bool) {
 	tag = field.Tag.Get(structTag)
 
-	// test with >= 1 in case of a tag named "inline"
-	inline = strings.LastIndex(tag, "inline") >= 1
-
-	// check for possible comma as in "...,omitempty"
+	// check for possible comma as in "...,omitempty", options are only read
+	// after it, so the name may be an arbitrary column alias
 	if i := strings.Index(tag, ","); i > -1 {
+		inline = slices.Contains(tagOptions(tag), "inline")
 		tag = tag[:i]
 	}
 
@@ -117,6 +240,31 @@ func fieldTag(field reflect.StructField, structTag string) (tag string, inline b
 	return tag, inline
 }
 
+// tagOptions returns the comma separated options that follow the name of tag.
+func tagOptions(tag string) []string {
+	i := strings.Index(tag, ",")
+	if i == -1 {
+		return nil
+	}
+	return strings.Split(tag[i+1:], ",")
+}
+
+// HasTagOption reports whether the structTag of field contains option.
+func HasTagOption(field reflect.StructField, structTag, option string) bool {
+	return slices.Contains(tagOptions(field.Tag.Get(structTag)), option)
+}
+
+// TagOptionValue returns the value of an option in the form "option=value"
+// of the structTag of field, and whether it was found.
+func TagOptionValue(field reflect.StructField, structTag, option string) (string, bool) {
+	for _, opt := range tagOptions(field.Tag.Get(structTag)) {
+		if value, ok := strings.CutPrefix(opt, option+"="); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
 // FieldByIndex returns the struct field from v, initializing any nested nil pointers.
 func FieldByIndex(v reflect.Value, index []int) reflect.Value {
 	v = reflect.Indirect(v)
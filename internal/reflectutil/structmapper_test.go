@@ -71,6 +71,51 @@ func TestStructFieldMap_inline(t *testing.T) {
 	assert.Equal(t, expect, got)
 }
 
+func TestStructFieldMap_rownum(t *testing.T) {
+	type Base struct {
+		RowNum int `json:",rownum"`
+	}
+
+	type User struct {
+		Id   int
+		Name string
+		Base
+	}
+
+	expect := map[string][]int{
+		"id":   {0},
+		"name": {1},
+	}
+
+	got := StructFieldMap(reflect.TypeFor[User](), "json", "_", strings.ToLower)
+	assert.Equal(t, expect, got)
+	assert.Equal(t, []int{2, 0}, RowNumIndex(reflect.TypeFor[User](), "json"))
+	assert.Equal(t, []int{2, 0}, RowNumIndex(reflect.TypeFor[*User](), "json"))
+	assert.Nil(t, RowNumIndex(reflect.TypeFor[Base](), "db"))
+}
+
+func TestStructFieldMap_extra(t *testing.T) {
+	type Base struct {
+		Extra map[string]any `json:",extra"`
+	}
+
+	type User struct {
+		Id     int
+		Params map[string]any `json:"params,extra"`
+		Base
+	}
+
+	expect := map[string][]int{
+		"id": {0},
+	}
+
+	got := StructFieldMap(reflect.TypeFor[User](), "json", "_", strings.ToLower)
+	assert.Equal(t, expect, got)
+	assert.Equal(t, []int{1}, ExtraIndex(reflect.TypeFor[User](), "json"))
+	assert.Equal(t, []int{0}, ExtraIndex(reflect.TypeFor[*Base](), "json"))
+	assert.Nil(t, ExtraIndex(reflect.TypeFor[User](), "db"))
+}
+
 func TestStructFieldMap_circular(t *testing.T) {
 	type Person struct {
 		Parent *Person
@@ -92,6 +137,71 @@ func TestStructFieldMap_circular(t *testing.T) {
 	assert.Equal(t, expect, got)
 }
 
+func TestStructFieldMap_precedence(t *testing.T) {
+	type Audit struct {
+		Id        int
+		CreatedAt time.Time
+	}
+
+	type Owner struct {
+		Id   int
+		Name string
+	}
+
+	t.Run("shallower field wins", func(t *testing.T) {
+		type User struct {
+			Audit
+			Id   int
+			Name string
+		}
+
+		got := StructFieldMap(reflect.TypeFor[User](), "json", ".", strings.ToLower)
+		assert.Equal(t, []int{1}, got["id"])
+		assert.Equal(t, []int{0, 1}, got["createdat"])
+		assert.Empty(t, AmbiguousKeys(reflect.TypeFor[User](), "json", ".", strings.ToLower))
+	})
+
+	t.Run("tag of shallower field wins", func(t *testing.T) {
+		type User struct {
+			*Owner
+			UserName string `json:"name"`
+		}
+
+		got := StructFieldMap(reflect.TypeFor[User](), "json", ".", strings.ToLower)
+		assert.Equal(t, []int{1}, got["name"])
+		assert.Equal(t, []int{0, 0}, got["id"])
+	})
+
+	t.Run("equal depth is ambiguous", func(t *testing.T) {
+		type User struct {
+			Audit
+			*Owner
+			Email string
+		}
+
+		got := StructFieldMap(reflect.TypeFor[User](), "json", ".", strings.ToLower)
+		assert.NotContains(t, got, "id")
+		assert.Equal(t, []int{0, 1}, got["createdat"])
+		assert.Equal(t, []int{1, 1}, got["name"])
+		assert.Equal(t, []string{"id"}, AmbiguousKeys(reflect.TypeFor[User](), "json", ".", strings.ToLower))
+	})
+
+	t.Run("deeper field does not resolve ambiguity", func(t *testing.T) {
+		type Base struct {
+			Audit
+		}
+
+		type User struct {
+			Audit
+			Owner
+			Base
+		}
+
+		got := StructFieldMap(reflect.TypeFor[User](), "json", ".", strings.ToLower)
+		assert.NotContains(t, got, "id")
+	})
+}
+
 func TestFieldByIndex(t *testing.T) {
 	type Person struct {
 		Id         int
@@ -155,6 +265,10 @@ func TestFieldTag(t *testing.T) {
 		Field string `json:"inline,inline"`
 	}
 
+	type AliasEdgeCase struct {
+		Field string `json:"data->>'inline'"`
+	}
+
 	typ := reflect.TypeOf(Sample{})
 
 	t.Run("tag not found", func(t *testing.T) {
@@ -206,6 +320,13 @@ func TestFieldTag(t *testing.T) {
 		assert.Equal(t, "inline", tag)
 	})
 
+	t.Run("alias containing inline", func(t *testing.T) {
+		f, _ := reflect.TypeFor[AliasEdgeCase]().FieldByName("Field")
+		tag, inline := fieldTag(f, "json")
+		assert.False(t, inline)
+		assert.Equal(t, "data->>'inline'", tag)
+	})
+
 	t.Run("tag with dash", func(t *testing.T) {
 		f, _ := typ.FieldByName("WithIgnore")
 		tag, inline := fieldTag(f, "json")
@@ -254,3 +375,25 @@ func BenchmarkStructFieldMap_circular(b *testing.B) {
 		_ = StructFieldMap(reflect.TypeFor[Person](), "json", ".", strings.ToLower)
 	}
 }
+
+func TestTagOptionValue(t *testing.T) {
+	type Sample struct {
+		Dob     string `db:"dob,timeformat=2006-01-02"`
+		Created string `db:",readonly,timeformat=2006-01-02 15:04:05"`
+		Name    string `db:"timeformat=x"`
+	}
+
+	typ := reflect.TypeFor[Sample]()
+
+	value, ok := TagOptionValue(typ.Field(0), "db", TimeFormatOption)
+	assert.True(t, ok)
+	assert.Equal(t, "2006-01-02", value)
+
+	value, ok = TagOptionValue(typ.Field(1), "db", TimeFormatOption)
+	assert.True(t, ok)
+	assert.Equal(t, "2006-01-02 15:04:05", value)
+
+	// the name is not an option
+	_, ok = TagOptionValue(typ.Field(2), "db", TimeFormatOption)
+	assert.False(t, ok)
+}
@@ -0,0 +1,77 @@
+package reflectutil
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+)
+
+var valuerType = reflect.TypeFor[driver.Valuer]()
+
+// StructRows converts rows, a slice of structs or pointers to structs, into a
+// 2D slice of values in the order of columns, e.g. to feed COPY or custom bulk
+// loaders. Fields are mapped like [StructFieldMap], by tag, or by their name
+// transformed by nameMapper, and nested fields are joined with sep.
+// Nil pointers result in nil values, [driver.Valuer] fields are kept as is.
+func StructRows(rows any, columns []string, tag, sep string, nameMapper func(string) string) ([][]any, error) {
+	sliceValue := reflect.Indirect(reflect.ValueOf(rows))
+	if sliceValue.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("sqlz/reflectutil: rows must be a slice of structs, got %T", rows)
+	}
+
+	elType := Deref(sliceValue.Type().Elem())
+	if elType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("sqlz/reflectutil: rows must be a slice of structs, got %T", rows)
+	}
+
+	indexByKey := StructFieldMap(elType, tag, sep, nameMapper)
+	indexes := make([][]int, len(columns))
+	for i, col := range columns {
+		index, ok := indexByKey[col]
+		if !ok {
+			return nil, fmt.Errorf("sqlz/reflectutil: field not found: '%s' (maybe unexported?)", col)
+		}
+		indexes[i] = index
+	}
+
+	out := make([][]any, sliceValue.Len())
+	for i := range sliceValue.Len() {
+		elValue := reflect.Indirect(sliceValue.Index(i))
+		if !elValue.IsValid() {
+			return nil, fmt.Errorf("sqlz/reflectutil: rows[%d] is nil pointer", i)
+		}
+
+		row := make([]any, len(columns))
+		for j, index := range indexes {
+			fv, err := elValue.FieldByIndexErr(index)
+			if err != nil {
+				continue // nil pointer in path
+			}
+			row[j] = fieldValue(fv)
+		}
+		out[i] = row
+	}
+
+	return out, nil
+}
+
+// fieldValue returns the value of a struct field to be sent to the database.
+func fieldValue(v reflect.Value) any {
+	if v.Type().Implements(valuerType) {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return nil
+		}
+		return v.Interface()
+	}
+
+	v = reflect.Indirect(v)
+	if !v.IsValid() {
+		return nil
+	}
+
+	if v.Type().Implements(valuerType) {
+		return v.Interface()
+	}
+
+	return TypedValue(v)
+}
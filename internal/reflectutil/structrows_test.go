@@ -0,0 +1,95 @@
+package reflectutil
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type email string
+
+func (e email) Value() (driver.Value, error) {
+	return strings.ToLower(string(e)), nil
+}
+
+func TestStructRows(t *testing.T) {
+	type Address struct {
+		City string `db:"city"`
+	}
+
+	type User struct {
+		Id       int            `db:"id"`
+		Name     string         `db:"name"`
+		Nickname *string        `db:"nickname"`
+		Email    email          `db:"email"`
+		Phone    sql.NullString `db:"phone"`
+		Address  *Address       `db:"address"`
+		Active   bool
+	}
+
+	nick := "ali"
+	users := []User{
+		{
+			Id:       1,
+			Name:     "Alice",
+			Nickname: &nick,
+			Email:    "Alice@Example.com",
+			Phone:    sql.NullString{String: "555", Valid: true},
+			Address:  &Address{City: "Wonderland"},
+			Active:   true,
+		},
+		{Id: 2, Name: "Rob", Email: "rob@example.com"},
+	}
+
+	t.Run("column subset", func(t *testing.T) {
+		got, err := StructRows(users, []string{"name", "id"}, "db", ".", strings.ToLower)
+		require.NoError(t, err)
+		assert.Equal(t, [][]any{{"Alice", 1}, {"Rob", 2}}, got)
+	})
+
+	t.Run("pointer and valuer fields", func(t *testing.T) {
+		columns := []string{"nickname", "email", "phone", "address.city", "active"}
+		got, err := StructRows(users, columns, "db", ".", strings.ToLower)
+		require.NoError(t, err)
+
+		expect := [][]any{
+			{"ali", email("Alice@Example.com"), sql.NullString{String: "555", Valid: true}, "Wonderland", true},
+			{nil, email("rob@example.com"), sql.NullString{}, nil, false},
+		}
+		assert.Equal(t, expect, got)
+	})
+
+	t.Run("name mapper", func(t *testing.T) {
+		got, err := StructRows(users, []string{"ACTIVE", "id"}, "db", ".", strings.ToUpper)
+		require.NoError(t, err)
+		assert.Equal(t, [][]any{{true, 1}, {false, 2}}, got)
+	})
+
+	t.Run("slice of pointers", func(t *testing.T) {
+		got, err := StructRows([]*User{&users[0], &users[1]}, []string{"id"}, "db", ".", strings.ToLower)
+		require.NoError(t, err)
+		assert.Equal(t, [][]any{{1}, {2}}, got)
+	})
+
+	t.Run("nil element", func(t *testing.T) {
+		_, err := StructRows([]*User{&users[0], nil}, []string{"id"}, "db", ".", strings.ToLower)
+		assert.ErrorContains(t, err, "rows[1] is nil pointer")
+	})
+
+	t.Run("field not found", func(t *testing.T) {
+		_, err := StructRows(users, []string{"id", "unknown"}, "db", ".", strings.ToLower)
+		assert.ErrorContains(t, err, "field not found: 'unknown'")
+	})
+
+	t.Run("not a slice of structs", func(t *testing.T) {
+		_, err := StructRows(users[0], []string{"id"}, "db", ".", strings.ToLower)
+		assert.ErrorContains(t, err, "must be a slice of structs")
+
+		_, err = StructRows([]int{1}, []string{"id"}, "db", ".", strings.ToLower)
+		assert.ErrorContains(t, err, "must be a slice of structs")
+	})
+}
@@ -57,6 +57,24 @@ func (c *lruCache[K, V]) put(key K, val V) (evicted bool) {
 	return evicted
 }
 
+func (c *lruCache[K, V]) remove(key K) (removed bool) {
+	defer c.mutex.Unlock()
+	c.mutex.Lock()
+
+	el, ok := c.m[key]
+	if !ok {
+		return false
+	}
+
+	c.l.Remove(el)
+	delete(c.m, key)
+	if c.onEvict != nil {
+		c.onEvict(key, el.Value.(entry[K, V]).val)
+	}
+
+	return true
+}
+
 func (c *lruCache[K, V]) evict() {
 	el := c.l.Remove(c.l.Back()).(entry[K, V])
 	delete(c.m, el.key)
@@ -50,6 +50,16 @@ func TestLRUCache(t *testing.T) {
 		assert.Equal(t, cap, c.l.Len())
 		assert.Equal(t, cap, len(c.m))
 	})
+
+	t.Run("remove", func(t *testing.T) {
+		assert.True(t, c.remove("bar"))
+		assert.False(t, c.remove("bar"))
+
+		_, ok := c.get("bar")
+		assert.False(t, ok)
+		assert.Equal(t, 1, c.l.Len())
+		assert.Equal(t, 1, len(c.m))
+	})
 }
 
 func TestLRUCache_concurrency(t *testing.T) {
@@ -46,6 +46,12 @@ func (c *StmtCache) Put(key string, stmt stmt) (evicted bool) {
 	return c.put(hashKey(key), stmt)
 }
 
+// Remove removes the entry of key, closing its prepared statement,
+// returns whether it was cached.
+func (c *StmtCache) Remove(key string) (removed bool) {
+	return c.remove(hashKey(key))
+}
+
 // Clear removes all entries from the cache, closing all prepared statements.
 func (c *StmtCache) Clear() {
 	for el := c.l.Front(); el != nil; el = el.Next() {
@@ -79,6 +79,19 @@ func TestStmtCache(t *testing.T) {
 		assert.Equal(t, cap, c.Len())
 	})
 
+	t.Run("remove", func(t *testing.T) {
+		c := New(cap)
+		quxStmt := &mockStmt{}
+		c.Put("qux", quxStmt)
+		assert.True(t, c.Remove("qux"))
+		assert.True(t, quxStmt.closeCalled)
+		assert.False(t, c.Remove("qux"))
+
+		_, ok := c.Get("qux")
+		assert.False(t, ok)
+		assert.Equal(t, 0, c.Len())
+	})
+
 	t.Run("clear", func(t *testing.T) {
 		assert.False(t, barStmt.closeCalled)
 		assert.False(t, bazStmt.closeCalled)
@@ -0,0 +1,70 @@
+package sqlz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// MultiDB aggregates several [DB] instances, typically horizontal shards
+// sharing the same schema. It's safe for concurrent use by multiple goroutines.
+type MultiDB struct {
+	shards []*DB
+}
+
+// NewMultiDB returns a [MultiDB] that fans out queries to every shard.
+func NewMultiDB(shards ...*DB) *MultiDB {
+	if len(shards) == 0 {
+		panic("sqlz: MultiDB requires at least one shard")
+	}
+	return &MultiDB{shards}
+}
+
+// Shards returns the underlying [DB] instances, in the order they were provided.
+func (m *MultiDB) Shards() []*DB { return m.shards }
+
+// Select executes the query concurrently on every shard and appends all rows
+// into dest, which must be a pointer to a slice. Rows are merged in shard order.
+//
+// If some shards fail, the rows from the successful ones are still appended,
+// and the returned error joins every shard error with [errors.Join].
+//
+// The args are for any placeholder parameters in the query, they are
+// resolved independently by each shard, according to its own [Options].
+func (m *MultiDB) Select(ctx context.Context, dest any, query string, args ...any) error {
+	if err := assertSlicePtr(dest); err != nil {
+		return err
+	}
+
+	destValue := reflect.ValueOf(dest)
+	sliceType := destValue.Elem().Type()
+	results := make([]reflect.Value, len(m.shards))
+	errs := make([]error, len(m.shards))
+
+	var wg sync.WaitGroup
+	for i, shard := range m.shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := reflect.New(sliceType)
+			if err := shard.Query(ctx, query, args...).Scan(result.Interface()); err != nil {
+				errs[i] = fmt.Errorf("sqlz: shard %d: %w", i, err)
+				return
+			}
+			results[i] = result.Elem()
+		}()
+	}
+	wg.Wait()
+
+	merged := destValue.Elem()
+	for _, result := range results {
+		if result.IsValid() {
+			merged = reflect.AppendSlice(merged, result)
+		}
+	}
+	destValue.Elem().Set(merged)
+
+	return errors.Join(errs...)
+}
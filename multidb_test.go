@@ -0,0 +1,80 @@
+package sqlz
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMultiDB_panic(t *testing.T) {
+	defer func() {
+		assert.Contains(t, recover(), "at least one shard")
+	}()
+
+	NewMultiDB()
+}
+
+func TestMultiDB_Select_validate_dest(t *testing.T) {
+	m := NewMultiDB(New("sqlite3", &sql.DB{}, nil))
+
+	var s []string
+	err := m.Select(ctx, s, "SELECT 1")
+	assert.ErrorContains(t, err, "pointer to a slice")
+
+	var str string
+	err = m.Select(ctx, &str, "SELECT 1")
+	assert.ErrorContains(t, err, "pointer to a slice")
+}
+
+func TestMultiDB_Select_merge(t *testing.T) {
+	type User struct {
+		Id   int
+		Name string
+	}
+
+	newShard := func(rows ...[]driver.Value) *DB {
+		pool := sql.OpenDB(&countingConnector{columns: []string{"id", "name"}, rows: rows})
+		t.Cleanup(func() { pool.Close() })
+		return New("mock", pool, &Options{Bind: BindQuestion})
+	}
+
+	m := NewMultiDB(
+		newShard([]driver.Value{int64(1), "Alice"}, []driver.Value{int64(2), "Rob"}),
+		newShard(),
+		newShard([]driver.Value{int64(3), "John"}, []driver.Value{int64(4), "Jane"}),
+	)
+
+	users := []User{{0, "existing"}}
+	err := m.Select(ctx, &users, "SELECT id, name FROM user WHERE active = ?", true)
+	require.NoError(t, err)
+	expect := []User{{0, "existing"}, {1, "Alice"}, {2, "Rob"}, {3, "John"}, {4, "Jane"}}
+	assert.Equal(t, expect, users)
+}
+
+func TestMultiDB_Select_partial_failure(t *testing.T) {
+	newPool := func() *sql.DB {
+		pool := sql.OpenDB(&countingConnector{columns: []string{"greeting"}, rows: [][]driver.Value{{"Hello World"}}})
+		t.Cleanup(func() { pool.Close() })
+		return pool
+	}
+
+	closed := newPool()
+	closed.Close()
+
+	m := NewMultiDB(
+		New("mock", newPool(), &Options{Bind: BindQuestion}),
+		New("mock", closed, &Options{Bind: BindQuestion}),
+		New("mock", newPool(), &Options{Bind: BindQuestion}),
+	)
+
+	ss := []string{"existing"}
+	err := m.Select(ctx, &ss, "SELECT greeting")
+	assert.ErrorContains(t, err, "shard 1")
+	assert.ErrorContains(t, err, "database is closed")
+	assert.NotContains(t, err.Error(), "shard 0")
+	assert.NotContains(t, err.Error(), "shard 2")
+	assert.Equal(t, []string{"existing", "Hello World", "Hello World"}, ss)
+}
@@ -1,10 +1,15 @@
 package sqlz
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
-	"regexp"
+	"slices"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/rfberaldo/sqlz/internal/parser"
 	"github.com/rfberaldo/sqlz/internal/reflectutil"
@@ -13,10 +18,13 @@ import (
 type namedQuery struct {
 	*config
 	fieldIndexByKey map[string][]int
+	jsonKeys        map[string]bool // keys of fields tagged with ",json"
+	extraIndex      []int           // index of the field tagged with ",extra", if any
 
 	// result
-	query string
-	args  []any
+	query        string
+	args         []any
+	indexByIdent map[string]int // first placeholder index by ident
 }
 
 func processNamed(query string, arg any, cfg *config) (string, []any, error) {
@@ -26,6 +34,15 @@ func processNamed(query string, arg any, cfg *config) (string, []any, error) {
 		return "", nil, err
 	}
 
+	if n.verifyArgCount {
+		if count := parser.CountPlaceholders(n.bind, n.query); count != len(n.args) {
+			return "", nil, fmt.Errorf(
+				"sqlz/named: arguments mismatch after compiling query: placeholders %d arguments %d: %s",
+				count, len(n.args), n.query,
+			)
+		}
+	}
+
 	return n.query, n.args, nil
 }
 
@@ -47,7 +64,11 @@ func (n *namedQuery) process(query string, arg any) error {
 }
 
 func (n *namedQuery) processOne(query string, argValue reflect.Value, kind reflect.Kind) (err error) {
-	query, idents := parser.Parse(n.bind, query)
+	parse := parser.Parse
+	if n.fastParse {
+		parse = parser.ParseFast
+	}
+	query, idents := parse(n.bind, query)
 
 	switch kind {
 	case reflect.Map:
@@ -61,27 +82,52 @@ func (n *namedQuery) processOne(query string, argValue reflect.Value, kind refle
 		return err
 	}
 
+	n.indexByIdent = parser.IdentIndexes(idents, n.args)
 	n.query, n.args, err = parser.ParseInClause(n.bind, query, n.args)
 	if err != nil {
+		var sliceErr *parser.SliceOutsideInError
+		if errors.As(err, &sliceErr) && sliceErr.Index < len(idents) {
+			return fmt.Errorf(
+				"sqlz/named: slice arg for non-IN parameter ':%s', slices are only spread inside 'IN (...)'",
+				idents[sliceErr.Index],
+			)
+		}
 		return err
 	}
 
 	return nil
 }
 
-func (n *namedQuery) structValue(v reflect.Value) any {
+func (n *namedQuery) structValue(v reflect.Value) (any, error) {
+	// checked before dereferencing, pointers to zero times are bound as is
+	if n.zeroTimeAsNull && v.IsValid() && v.Type() == timeType && v.IsZero() {
+		return nil, nil
+	}
+
 	v = reflect.Indirect(v)
 	if !v.IsValid() {
-		return nil
+		return nil, nil
 	}
 
 	// not testing pointer receiver, as [driver.Valuer] must have value receiver
 	if v.Type().Implements(valuerType) {
-		return v.Interface()
+		return v.Interface(), nil
+	}
+
+	if n.normalizeTimesToUTC && v.Type() == timeType {
+		return v.Interface().(time.Time).UTC(), nil
+	}
+
+	if text, ok := ipToText(v.Interface()); ok {
+		return text, nil
+	}
+
+	if text, ok, err := marshalText(v); ok {
+		return text, err
 	}
 
 	// this helps allocating less than necessary
-	return reflectutil.TypedValue(v)
+	return reflectutil.TypedValue(v), nil
 }
 
 // bindStructArgs maps idents to the argValue struct fields, binding their values,
@@ -100,23 +146,128 @@ func (n *namedQuery) bindStructArgs(idents []string, argValue reflect.Value) err
 		n.fieldIndexByKey = reflectutil.StructFieldMap(
 			argValue.Type(), n.structTag, ".", n.fieldNameTransformer,
 		)
+		n.resolveJSONKeys(argValue.Type())
+		n.extraIndex = reflectutil.ExtraIndex(argValue.Type(), n.structTag)
 	}
 
 	for _, ident := range idents {
 		index, ok := n.fieldIndexByKey[ident]
 		if !ok {
-			return fmt.Errorf("sqlz/named: field not found: '%s' (maybe unexported?)", ident)
+			ambiguous := reflectutil.AmbiguousKeys(argValue.Type(), n.structTag, ".", n.fieldNameTransformer)
+			if slices.Contains(ambiguous, ident) {
+				return fmt.Errorf(
+					"sqlz/named: ambiguous ':%s', mapped by multiple fields at the same depth of %s",
+					ident, argValue.Type(),
+				)
+			}
+			value, ok, err := n.extraArg(ident, argValue)
+			if err != nil {
+				return err
+			}
+			if ok {
+				n.args = append(n.args, value)
+				continue
+			}
+			if n.bindMissingAsNull {
+				n.args = append(n.args, nil)
+				continue
+			}
+			return fmt.Errorf(
+				"sqlz/named: no value for ':%s', field not found in struct %s (maybe unexported or missing a '%s' tag?)",
+				ident, argValue.Type(), n.structTag,
+			)
 		}
 		v, err := argValue.FieldByIndexErr(index)
 		if err != nil {
 			return fmt.Errorf("sqlz/named: field is nil pointer: '%s'", ident)
 		}
-		n.args = append(n.args, n.structValue(v))
+		value, err := n.fieldArg(v, n.jsonKeys[ident])
+		if err != nil {
+			return fmt.Errorf("sqlz/named: field '%s': %w", ident, err)
+		}
+		n.args = append(n.args, value)
 	}
 
 	return nil
 }
 
+// extraArg returns the arg bound for ident from the argValue field tagged with
+// [reflectutil.ExtraOption], reporting whether it has the key.
+func (n *namedQuery) extraArg(ident string, argValue reflect.Value) (any, bool, error) {
+	if n.extraIndex == nil {
+		return nil, false, nil
+	}
+
+	v, err := argValue.FieldByIndexErr(n.extraIndex)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	m, ok := v.Interface().(map[string]any)
+	if !ok {
+		return nil, false, fmt.Errorf("sqlz/named: extra field must be of type map[string]any, got %s", v.Type())
+	}
+
+	value, ok := getMapValue(ident, m)
+	if !ok {
+		return nil, false, nil
+	}
+
+	value, err = n.mapArg(value)
+	if err != nil {
+		return nil, false, fmt.Errorf("sqlz/named: extra key '%s': %w", ident, err)
+	}
+
+	return value, true, nil
+}
+
+// fieldArg returns the arg bound for the struct field v, which is encoded as
+// JSON if isJSON, see [reflectutil.JSONOption].
+func (n *namedQuery) fieldArg(v reflect.Value, isJSON bool) (any, error) {
+	if isJSON {
+		return marshalJSON(v)
+	}
+
+	if v.Kind() == reflect.Func {
+		lazy, err := evalLazy(v.Interface())
+		if err != nil {
+			return nil, err
+		}
+		v = reflect.ValueOf(lazy)
+	}
+
+	return n.structValue(v)
+}
+
+// resolveJSONKeys flags the keys of the structType fields tagged with the "json" option.
+func (n *namedQuery) resolveJSONKeys(structType reflect.Type) {
+	for key, index := range n.fieldIndexByKey {
+		field := structType.FieldByIndex(index)
+		if !reflectutil.HasTagOption(field, n.structTag, reflectutil.JSONOption) {
+			continue
+		}
+		if n.jsonKeys == nil {
+			n.jsonKeys = make(map[string]bool)
+		}
+		n.jsonKeys[key] = true
+	}
+}
+
+// marshalJSON returns the JSON encoding of v as a string, which is accepted
+// by JSON columns of every driver, nil pointers are bound as NULL.
+func marshalJSON(v reflect.Value) (any, error) {
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
 // bindMapArgs maps idents to the argValue map keys, binding their values,
 // binded args may have slices, meaning an "IN" clause.
 func (n *namedQuery) bindMapArgs(idents []string, argValue reflect.Value) error {
@@ -131,14 +282,47 @@ func (n *namedQuery) bindMapArgs(idents []string, argValue reflect.Value) error
 
 	for _, ident := range idents {
 		value, ok := getMapValue(ident, m)
+		if !ok && n.bindMissingAsNull {
+			n.args = append(n.args, nil)
+			continue
+		}
 		if !ok {
 			return fmt.Errorf("sqlz/named: could not find '%s' in %+v", ident, m)
 		}
+		value, err := n.mapArg(value)
+		if err != nil {
+			return fmt.Errorf("sqlz/named: key '%s': %w", ident, err)
+		}
 		n.args = append(n.args, value)
 	}
 	return nil
 }
 
+// mapArg returns the arg bound for the map value.
+func (n *namedQuery) mapArg(value any) (any, error) {
+	value, err := evalLazy(value)
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := value.(time.Time); ok && n.zeroTimeAsNull && t.IsZero() {
+		value = nil
+	}
+	if n.normalizeTimesToUTC {
+		value = timeToUTC(value)
+	}
+	value, _ = ipToText(value)
+	if _, ok := value.(driver.Valuer); !ok {
+		text, ok, err := marshalText(reflect.Indirect(reflect.ValueOf(value)))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			value = text
+		}
+	}
+	return value, nil
+}
+
 func (n *namedQuery) processSlice(query string, sliceValue reflect.Value) error {
 	if sliceValue.Len() == 0 {
 		return fmt.Errorf("sqlz/named: slice is zero length: %s", sliceValue.Type())
@@ -162,7 +346,9 @@ func (n *namedQuery) bindSliceArgs(
 	sliceValue reflect.Value,
 	fn func(idents []string, argValue reflect.Value) error,
 ) (err error) {
-	idents := parser.ParseIdents(n.bind, query)
+	bind := batchBind(n.bind)
+	idents := parser.ParseIdents(bind, query)
+	n.indexByIdent = parser.IdentIndexes(idents, nil)
 	if n.args == nil {
 		n.args = make([]any, 0, len(idents)*sliceValue.Len())
 	}
@@ -174,8 +360,8 @@ func (n *namedQuery) bindSliceArgs(
 	}
 
 	// if bind is '?', parse query before expanding
-	if n.bind == parser.BindQuestion {
-		n.query = parser.ParseQuery(n.bind, query)
+	if bind == parser.BindQuestion {
+		n.query = parser.ParseQuery(bind, query)
 		n.query, err = expandInsertSyntax(n.query, sliceValue.Len())
 		return err
 	}
@@ -185,21 +371,33 @@ func (n *namedQuery) bindSliceArgs(
 		return err
 	}
 
-	n.query = parser.ParseQuery(n.bind, n.query)
+	n.query = parser.ParseQuery(bind, n.query)
 
 	return nil
 }
 
-var regValues = regexp.MustCompile(`(?i)\)\s*VALUES\s*\(`)
+// batchBind returns the bind of batch inserts, with [parser.BindNamedAt] every
+// row would repeat the same names, numbered placeholders are used instead,
+// as "@p1" is a valid name too.
+func batchBind(bind parser.Bind) parser.Bind {
+	if bind == parser.BindNamedAt {
+		return parser.BindAt
+	}
+	return bind
+}
 
-// expandInsertSyntax multiply the 'VALUES' part of a INSERT query by count.
+// expandInsertSyntax multiply the 'VALUES' part of a INSERT query by count,
+// a leading WITH clause is kept as is, INSERT ... SELECT is not supported.
 func expandInsertSyntax(query string, count int) (string, error) {
-	loc := regValues.FindStringIndex(query)
-	if loc == nil {
-		return "", fmt.Errorf("sqlz/named: slice is only supported in INSERT query with 'VALUES' clause")
+	valuesIdx := parser.InsertValuesIndex(query)
+	if valuesIdx == -1 {
+		return "", fmt.Errorf("sqlz/named: slice is only supported in INSERT query with 'VALUES' clause, not INSERT ... SELECT")
 	}
 
-	openIdx := loc[1] - 1
+	openIdx := valuesIdx + len("VALUES")
+	for openIdx < len(query) && unicode.IsSpace(rune(query[openIdx])) {
+		openIdx++
+	}
 	closeIdx := endingParensIndex(query[openIdx:])
 	if closeIdx == -1 {
 		return "", fmt.Errorf("sqlz/named: could not parse batch INSERT, missing ending parenthesis")
@@ -214,7 +412,7 @@ func expandInsertSyntax(query string, count int) (string, error) {
 }
 
 // endingParensIndex find the ending parenthesis of a string starting with '(',
-// returns -1 if not found.
+// parentheses inside quoted literals are ignored, returns -1 if not found.
 //
 //	endingParensIndex("(NOW())") // Output: 6
 func endingParensIndex(s string) int {
@@ -222,8 +420,19 @@ func endingParensIndex(s string) int {
 		return -1
 	}
 
+	var quote rune
 	count := 0
 	for i, ch := range s {
+		if quote != 0 {
+			if ch == quote {
+				quote = 0
+			}
+			continue
+		}
+		if ch == '\'' || ch == '"' || ch == '`' {
+			quote = ch
+			continue
+		}
 		if ch == '(' {
 			count++
 			continue
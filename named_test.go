@@ -1,7 +1,14 @@
 package sqlz
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/rfberaldo/sqlz/internal/parser"
 	"github.com/stretchr/testify/assert"
@@ -234,6 +241,17 @@ func TestProcessNamed(t *testing.T) {
 			expectedArgs:     []any{1, "Alice", 2, "Bob"},
 			expectError:      false,
 		},
+		{
+			name:             "slice with value expressions per row",
+			inputQuery:       "INSERT INTO users (id, name, score) VALUES (:id, LOWER(:name), COALESCE(:x, 0))",
+			inputArg:         []map[string]any{{"id": 1, "name": "Alice", "x": 10}, {"id": 2, "name": "Bob", "x": nil}},
+			expectedAt:       "INSERT INTO users (id, name, score) VALUES (@p1, LOWER(@p2), COALESCE(@p3, 0)),(@p4, LOWER(@p5), COALESCE(@p6, 0))",
+			expectedColon:    "INSERT INTO users (id, name, score) VALUES (:id, LOWER(:name), COALESCE(:x, 0)),(:id, LOWER(:name), COALESCE(:x, 0))",
+			expectedDollar:   "INSERT INTO users (id, name, score) VALUES ($1, LOWER($2), COALESCE($3, 0)),($4, LOWER($5), COALESCE($6, 0))",
+			expectedQuestion: "INSERT INTO users (id, name, score) VALUES (?, LOWER(?), COALESCE(?, 0)),(?, LOWER(?), COALESCE(?, 0))",
+			expectedArgs:     []any{1, "Alice", 10, 2, "Bob", nil},
+			expectError:      false,
+		},
 		{
 			name:             "in clause with named map",
 			inputQuery:       "SELECT * FROM user WHERE id IN (:ids)",
@@ -281,6 +299,24 @@ func TestProcessNamed(t *testing.T) {
 			expectedArgs:     []any{"Alice", 4, 5, 6},
 			expectError:      false,
 		},
+		{
+			name:             "in clause with slice of pointers",
+			inputQuery:       "SELECT * FROM user WHERE id IN (:ids)",
+			inputArg:         map[string]any{"ids": []*int{ptrTo(4), nil, ptrTo(6)}},
+			expectedAt:       "SELECT * FROM user WHERE id IN (@p1,@p2,@p3)",
+			expectedColon:    "SELECT * FROM user WHERE id IN (:ids,:ids,:ids)",
+			expectedDollar:   "SELECT * FROM user WHERE id IN ($1,$2,$3)",
+			expectedQuestion: "SELECT * FROM user WHERE id IN (?,?,?)",
+			expectedArgs:     []any{4, nil, 6},
+			expectError:      false,
+		},
+		{
+			name:              "slice bound outside in clause",
+			inputQuery:        "SELECT * FROM user WHERE name = :name AND id = :ids",
+			inputArg:          map[string]any{"name": "Alice", "ids": []int{4, 5, 6}},
+			expectError:       true,
+			expectErrContains: "slice arg for non-IN parameter ':ids'",
+		},
 		{
 			name:              "invalid argument type",
 			inputQuery:        "SELECT * FROM user WHERE id = :id",
@@ -310,6 +346,16 @@ func TestProcessNamed(t *testing.T) {
 			}{ID: 1},
 			expectError: true,
 		},
+		{
+			name:       "missing value in batch insert names ident and struct type",
+			inputQuery: "INSERT INTO users (id, name) VALUES (:id, :name)",
+			inputArg: []basicStruct{
+				{Identifier: 1, FullName: "Alice"},
+			},
+			structTag:         "json",
+			expectError:       true,
+			expectErrContains: "no value for ':id', field not found in struct sqlz.basicStruct (maybe unexported or missing a 'json' tag?)",
+		},
 		{
 			name:        "missing named parameter in map",
 			inputQuery:  "SELECT * FROM user WHERE id = :id AND name = :name",
@@ -397,6 +443,498 @@ func TestProcessNamed_concurrency(t *testing.T) {
 	}
 }
 
+func TestProcessNamed_timesToUTC(t *testing.T) {
+	loc := time.FixedZone("UTC-3", -3*60*60)
+	ts := time.Date(2025, 9, 29, 12, 0, 0, 0, loc)
+	expectedArgs := []any{ts.UTC(), ts.UTC()}
+	query := "INSERT INTO event (a, b) VALUES (:a, :b)"
+
+	cfg := &config{bind: parser.BindQuestion, normalizeTimesToUTC: true}
+
+	t.Run("struct", func(t *testing.T) {
+		arg := struct {
+			A time.Time
+			B *time.Time
+		}{ts, &ts}
+		_, args, err := processNamed(query, arg, cfg)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedArgs, args)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		arg := map[string]any{"a": ts, "b": &ts}
+		_, args, err := processNamed(query, arg, cfg)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedArgs, args)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		arg := map[string]any{"a": ts, "b": ts}
+		_, args, err := processNamed(query, arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{ts, ts}, args)
+	})
+}
+
+type status int
+
+const (
+	statusActive status = iota + 1
+	statusBanned
+)
+
+// MarshalText implements [encoding.TextMarshaler].
+func (s status) MarshalText() ([]byte, error) {
+	switch s {
+	case statusActive:
+		return []byte("active"), nil
+	case statusBanned:
+		return []byte("banned"), nil
+	}
+	return nil, fmt.Errorf("invalid status: %d", s)
+}
+
+type role string
+
+// MarshalText implements [encoding.TextMarshaler] with pointer receiver.
+func (r *role) MarshalText() ([]byte, error) {
+	return []byte(strings.ToUpper(string(*r))), nil
+}
+
+func TestProcessNamed_zeroTimeAsNull(t *testing.T) {
+	ts := time.Date(2025, 9, 29, 12, 0, 0, 0, time.UTC)
+	var zero time.Time
+	query := "INSERT INTO event (a, b, c) VALUES (:a, :b, :c)"
+	cfg := &config{bind: parser.BindQuestion, zeroTimeAsNull: true}
+
+	t.Run("struct", func(t *testing.T) {
+		arg := struct {
+			A time.Time
+			B *time.Time
+			C time.Time
+		}{zero, &zero, ts}
+		_, args, err := processNamed(query, arg, cfg)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{nil, zero, ts}, args)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		arg := map[string]any{"a": zero, "b": &zero, "c": ts}
+		_, args, err := processNamed(query, arg, cfg)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{nil, &zero, ts}, args)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		arg := map[string]any{"a": zero, "b": zero, "c": ts}
+		_, args, err := processNamed(query, arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{zero, zero, ts}, args)
+	})
+}
+
+func TestProcessNamed_textMarshaler(t *testing.T) {
+	query := "INSERT INTO user (status, role) VALUES (:status, :role)"
+
+	t.Run("struct", func(t *testing.T) {
+		arg := &struct {
+			Status status
+			Role   role
+		}{statusBanned, "admin"}
+		_, args, err := processNamed(query, arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"banned", "ADMIN"}, args)
+	})
+
+	t.Run("struct with pointer field", func(t *testing.T) {
+		s := statusActive
+		arg := struct {
+			Status *status
+			Role   *role
+		}{&s, nil}
+		_, args, err := processNamed(query, arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"active", nil}, args)
+	})
+
+	t.Run("struct slice", func(t *testing.T) {
+		type user struct {
+			Status status
+			Role   string
+		}
+		arg := []user{{statusActive, "a"}, {statusBanned, "b"}}
+		_, args, err := processNamed(query, arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"active", "a", "banned", "b"}, args)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		r := role("guest")
+		arg := map[string]any{"status": statusActive, "role": &r}
+		_, args, err := processNamed(query, arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"active", "GUEST"}, args)
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		arg := map[string]any{"status": status(42), "role": "x"}
+		_, _, err := processNamed(query, arg, nil)
+		assert.ErrorContains(t, err, "invalid status: 42")
+
+		argStruct := struct {
+			Status status
+			Role   string
+		}{status(42), "x"}
+		_, _, err = processNamed(query, argStruct, nil)
+		assert.ErrorContains(t, err, "invalid status: 42")
+	})
+
+	t.Run("time is not marshaled", func(t *testing.T) {
+		ts := time.Date(2025, 9, 29, 12, 0, 0, 0, time.UTC)
+		arg := map[string]any{"status": ts, "role": &ts}
+		_, args, err := processNamed(query, arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{ts, &ts}, args)
+	})
+}
+
+func TestProcessNamed_ip(t *testing.T) {
+	query := "INSERT INTO host (addr, network, legacy) VALUES (:addr, :network, :legacy)"
+
+	t.Run("struct", func(t *testing.T) {
+		arg := struct {
+			Addr    netip.Addr
+			Network *netip.Prefix
+			Legacy  net.IP
+		}{netip.MustParseAddr("192.168.0.1"), nil, net.ParseIP("::1")}
+		_, args, err := processNamed(query, arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"192.168.0.1", nil, "::1"}, args)
+	})
+
+	t.Run("zero values are NULL", func(t *testing.T) {
+		arg := struct {
+			Addr    netip.Addr
+			Network netip.Prefix
+			Legacy  net.IP
+		}{}
+		_, args, err := processNamed(query, arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{nil, nil, nil}, args)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		prefix := netip.MustParsePrefix("10.0.0.0/8")
+		arg := map[string]any{"addr": netip.Addr{}, "network": &prefix, "legacy": net.ParseIP("10.0.0.1")}
+		_, args, err := processNamed(query, arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{nil, "10.0.0.0/8", "10.0.0.1"}, args)
+	})
+}
+
+func TestProcessNamed_jsonRawMessage(t *testing.T) {
+	data := json.RawMessage(`{"tags": ["a", "b"]}`)
+
+	t.Run("struct", func(t *testing.T) {
+		arg := struct {
+			Id   int
+			Data json.RawMessage
+		}{1, data}
+		query, args, err := processNamed("INSERT INTO doc (id, data) VALUES (:id, :data)", arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "INSERT INTO doc (id, data) VALUES (?, ?)", query)
+		assert.Equal(t, []any{1, []byte(data)}, args)
+	})
+
+	t.Run("map is not spread", func(t *testing.T) {
+		arg := map[string]any{"data": data, "ids": []int{1, 2}}
+		query, args, err := processNamed("SELECT * FROM doc WHERE data = :data AND id IN (:ids)", arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM doc WHERE data = ? AND id IN (?,?)", query)
+		assert.Equal(t, []any{data, 1, 2}, args)
+	})
+}
+
+// orderStatus is an enum stored as an integer, its [driver.Valuer]
+// rejects unknown values.
+type orderStatus int
+
+const (
+	orderPending orderStatus = iota + 1
+	orderShipped
+)
+
+func (s orderStatus) Value() (driver.Value, error) {
+	if s < orderPending || s > orderShipped {
+		return nil, fmt.Errorf("unknown order status: %d", s)
+	}
+	return int64(s), nil
+}
+
+func TestProcessNamed_valuerEnum(t *testing.T) {
+	type Order struct {
+		Id       int
+		Status   orderStatus
+		Previous *orderStatus
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		pending := orderPending
+		arg := Order{1, orderShipped, &pending}
+		_, args, err := processNamed("INSERT INTO orders (id, status, previous) VALUES (:id, :status, :previous)", arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{1, orderShipped, orderPending}, args)
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		arg := []Order{{1, orderPending, nil}, {2, orderShipped, nil}}
+		_, args, err := processNamed("INSERT INTO orders (id, status) VALUES (:id, :status)", arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{1, orderPending, 2, orderShipped}, args)
+	})
+
+	t.Run("map with in clause", func(t *testing.T) {
+		arg := map[string]any{"status": orderPending, "statuses": []orderStatus{orderPending, orderShipped}}
+		query, args, err := processNamed("SELECT * FROM orders WHERE status = :status OR status IN (:statuses)", arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM orders WHERE status = ? OR status IN (?,?)", query)
+		assert.Equal(t, []any{orderPending, orderPending, orderShipped}, args)
+	})
+}
+
+func TestProcessNamed_bindMissingAsNull(t *testing.T) {
+	cfg := &config{bindMissingAsNull: true}
+	query := "UPDATE user SET name = :name, email = :email WHERE id = :id"
+
+	t.Run("struct", func(t *testing.T) {
+		arg := struct {
+			Id   int
+			Name string
+		}{1, "Alice"}
+
+		_, _, err := processNamed(query, arg, nil)
+		assert.ErrorContains(t, err, "no value for ':email'")
+
+		compiled, args, err := processNamed(query, arg, cfg)
+		assert.NoError(t, err)
+		assert.Equal(t, "UPDATE user SET name = ?, email = ? WHERE id = ?", compiled)
+		assert.Equal(t, []any{"Alice", nil, 1}, args)
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		type User struct{ Name string }
+		arg := []User{{"Alice"}, {"Bob"}}
+		_, args, err := processNamed("INSERT INTO user (name, email) VALUES (:name, :email)", arg, cfg)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"Alice", nil, "Bob", nil}, args)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		arg := map[string]any{"id": 1, "name": "Alice"}
+		_, args, err := processNamed(query, arg, cfg)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"Alice", nil, 1}, args)
+	})
+
+	t.Run("ambiguous", func(t *testing.T) {
+		type A struct{ Email string }
+		type B struct{ Email string }
+		arg := struct {
+			A
+			B
+			Id   int
+			Name string
+		}{Id: 1}
+		_, _, err := processNamed(query, arg, cfg)
+		assert.ErrorContains(t, err, "ambiguous ':email'")
+	})
+}
+
+func TestProcessNamed_extraOption(t *testing.T) {
+	type Filter struct {
+		Status string
+		Params map[string]any `db:",extra"`
+	}
+
+	query := "SELECT * FROM user WHERE status = :status AND age > :min_age AND id IN (:ids)"
+
+	t.Run("struct", func(t *testing.T) {
+		arg := Filter{"active", map[string]any{"min_age": 18, "ids": []int{1, 2}, "status": "ignored"}}
+		compiled, args, err := processNamed(query, arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM user WHERE status = ? AND age > ? AND id IN (?,?)", compiled)
+		assert.Equal(t, []any{"active", 18, 1, 2}, args)
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		arg := []Filter{
+			{"a", map[string]any{"role": "admin"}},
+			{"b", map[string]any{"role": "user"}},
+		}
+		_, args, err := processNamed("INSERT INTO user (status, role) VALUES (:status, :role)", arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"a", "admin", "b", "user"}, args)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		arg := Filter{"active", nil}
+		_, _, err := processNamed(query, arg, nil)
+		assert.ErrorContains(t, err, "no value for ':min_age'")
+
+		_, args, err := processNamed("SELECT :status, :min_age", arg, &config{bindMissingAsNull: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"active", nil}, args)
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		arg := struct {
+			Params map[string]int `db:",extra"`
+		}{map[string]int{"id": 1}}
+		_, _, err := processNamed("SELECT :id", arg, nil)
+		assert.ErrorContains(t, err, "extra field must be of type map[string]any, got map[string]int")
+	})
+}
+
+func TestProcessNamed_jsonOption(t *testing.T) {
+	type Meta struct {
+		Tags []string `json:"tags"`
+	}
+
+	type Doc struct {
+		Id    int
+		Meta  Meta           `db:"meta,json"`
+		Attrs map[string]int `db:"attrs,json"`
+		Ptr   *Meta          `db:"ptr,json"`
+	}
+
+	query := "INSERT INTO doc (id, meta, attrs, ptr) VALUES (:id, :meta, :attrs, :ptr)"
+
+	t.Run("struct", func(t *testing.T) {
+		arg := Doc{1, Meta{[]string{"a"}}, map[string]int{"x": 1}, nil}
+		_, args, err := processNamed(query, arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{1, `{"tags":["a"]}`, `{"x":1}`, nil}, args)
+	})
+
+	t.Run("struct slice", func(t *testing.T) {
+		arg := []Doc{{Id: 1}, {Id: 2, Ptr: &Meta{[]string{"b"}}}}
+		query, args, err := processNamed(query, arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "INSERT INTO doc (id, meta, attrs, ptr) VALUES (?, ?, ?, ?),(?, ?, ?, ?)", query)
+		assert.Equal(t, []any{1, `{"tags":null}`, "null", nil, 2, `{"tags":null}`, "null", `{"tags":["b"]}`}, args)
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		arg := struct {
+			Fn func() `db:"fn,json"`
+		}{func() {}}
+		_, _, err := processNamed("SELECT :fn", arg, nil)
+		assert.ErrorContains(t, err, "field 'fn'")
+	})
+}
+
+func TestProcessNamed_lazyValue(t *testing.T) {
+	query := "INSERT INTO user (id, created_at) VALUES (:id, :created_at)"
+	ts := time.Date(2025, 9, 29, 12, 0, 0, 0, time.FixedZone("BRT", -3*60*60))
+	now := func() (any, error) { return ts, nil }
+
+	t.Run("map", func(t *testing.T) {
+		arg := map[string]any{"id": 1, "created_at": now}
+		_, args, err := processNamed(query, arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{1, ts}, args)
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		arg := struct {
+			Id        int
+			CreatedAt func() (any, error)
+		}{1, now}
+		_, args, err := processNamed(query, arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{1, ts}, args)
+	})
+
+	t.Run("evaluated per row", func(t *testing.T) {
+		calls := 0
+		next := func() (any, error) {
+			calls++
+			return calls, nil
+		}
+		arg := []map[string]any{
+			{"id": next, "created_at": ts},
+			{"id": next, "created_at": ts},
+		}
+		_, args, err := processNamed(query, arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{1, ts, 2, ts}, args)
+	})
+
+	t.Run("result is normalized", func(t *testing.T) {
+		arg := map[string]any{"id": func() (any, error) { return statusBanned, nil }, "created_at": now}
+		_, args, err := processNamed(query, arg, &config{normalizeTimesToUTC: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"banned", ts.UTC()}, args)
+	})
+
+	t.Run("nil func", func(t *testing.T) {
+		arg := struct {
+			Id        int
+			CreatedAt func() (any, error)
+		}{Id: 1}
+		_, args, err := processNamed(query, arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{1, nil}, args)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		fail := func() (any, error) { return nil, assert.AnError }
+		_, _, err := processNamed(query, map[string]any{"id": 1, "created_at": fail}, nil)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "key 'created_at': evaluating func value")
+
+		arg := struct {
+			Id        int
+			CreatedAt func() (any, error)
+		}{1, fail}
+		_, _, err = processNamed(query, arg, nil)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "field 'created_at': evaluating func value")
+	})
+}
+
+func TestProcessNamed_embedPrecedence(t *testing.T) {
+	type Audit struct {
+		Id        int
+		CreatedBy string
+	}
+
+	type Owner struct {
+		Id int
+	}
+
+	query := "INSERT INTO user (id, created_by) VALUES (:id, :created_by)"
+
+	t.Run("outer field wins", func(t *testing.T) {
+		arg := struct {
+			Audit
+			Id int
+		}{Audit{1, "admin"}, 2}
+		_, args, err := processNamed(query, arg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{2, "admin"}, args)
+	})
+
+	t.Run("ambiguous at equal depth", func(t *testing.T) {
+		arg := struct {
+			Audit
+			Owner
+		}{Audit{1, "admin"}, Owner{2}}
+		_, _, err := processNamed(query, arg, nil)
+		assert.ErrorContains(t, err, "ambiguous ':id'")
+	})
+}
+
 func TestExpandInsertSyntax(t *testing.T) {
 	input := "INSERT INTO xx (a,b,c) VALUES (?,?,?) ON CONFLICT IGNORE"
 	result, err := expandInsertSyntax(input, 3)
@@ -405,6 +943,146 @@ func TestExpandInsertSyntax(t *testing.T) {
 	assert.Equal(t, expect, result)
 }
 
+func TestExpandInsertSyntax_cte(t *testing.T) {
+	input := "WITH src AS (SELECT * FROM (VALUES (1)) v) INSERT INTO xx (a,b) VALUES (?,?)"
+	result, err := expandInsertSyntax(input, 2)
+	assert.NoError(t, err)
+	expect := "WITH src AS (SELECT * FROM (VALUES (1)) v) INSERT INTO xx (a,b) VALUES (?,?),(?,?)"
+	assert.Equal(t, expect, result)
+
+	_, err = expandInsertSyntax("WITH src AS (SELECT 1 AS a) INSERT INTO xx (a) SELECT a FROM src", 2)
+	assert.ErrorContains(t, err, "not INSERT ... SELECT")
+
+	_, err = expandInsertSyntax("INSERT INTO xx (a) SELECT a FROM (VALUES (1)) v", 2)
+	assert.ErrorContains(t, err, "not INSERT ... SELECT")
+}
+
+func TestProcessNamed_cteBatchInsert(t *testing.T) {
+	query := `WITH cfg AS (SELECT 1 AS v) INSERT INTO user (id, name) VALUES (:id, :name)`
+	arg := []map[string]any{
+		{"id": 1, "name": "Alice"},
+		{"id": 2, "name": "Bob"},
+	}
+
+	output, args, err := processNamed(query, arg, &config{bind: parser.BindDollar})
+	assert.NoError(t, err)
+	assert.Equal(t, `WITH cfg AS (SELECT 1 AS v) INSERT INTO user (id, name) VALUES ($1, $2),($3, $4)`, output)
+	assert.Equal(t, []any{1, "Alice", 2, "Bob"}, args)
+
+	_, _, err = processNamed(`WITH cfg AS (SELECT :id AS id) INSERT INTO user (id) SELECT id FROM cfg`, arg, &config{bind: parser.BindDollar})
+	assert.ErrorContains(t, err, "not INSERT ... SELECT")
+}
+
+func TestProcessNamed_multiLineBatchInsert(t *testing.T) {
+	query := `
+		INSERT INTO user (
+			id,
+			name,
+			note
+		)
+		VALUES
+		(
+			:id,
+			COALESCE(
+				:name,
+				'(unknown)'
+			),
+			CONCAT(:id, ')')
+		)
+		ON CONFLICT DO NOTHING`
+
+	arg := []map[string]any{
+		{"id": 1, "name": "Alice"},
+		{"id": 2, "name": nil},
+	}
+
+	tests := []struct {
+		bind   parser.Bind
+		values string
+	}{
+		{parser.BindQuestion, "( ?, COALESCE( ?, '(unknown)' ), CONCAT(?, ')') ),( ?, COALESCE( ?, '(unknown)' ), CONCAT(?, ')') )"},
+		{parser.BindDollar, "( $1, COALESCE( $2, '(unknown)' ), CONCAT($3, ')') ),( $4, COALESCE( $5, '(unknown)' ), CONCAT($6, ')') )"},
+		{parser.BindAt, "( @p1, COALESCE( @p2, '(unknown)' ), CONCAT(@p3, ')') ),( @p4, COALESCE( @p5, '(unknown)' ), CONCAT(@p6, ')') )"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.bind), func(t *testing.T) {
+			output, args, err := processNamed(strings.TrimSpace(query), arg, &config{bind: tt.bind})
+			assert.NoError(t, err)
+			expect := "INSERT INTO user ( id, name, note ) VALUES " + tt.values + " ON CONFLICT DO NOTHING"
+			assert.Equal(t, expect, output)
+			assert.Equal(t, []any{1, "Alice", 1, 2, nil, 2}, args)
+		})
+	}
+}
+
+func TestProcessNamed_verifyArgCount(t *testing.T) {
+	// a native placeholder mixed into a named query is left untouched by the
+	// parser, so the compiled query has one more placeholder than args
+	query := "SELECT * FROM user WHERE id = :id AND name = ?"
+	arg := map[string]any{"id": 1}
+
+	for _, bind := range []parser.Bind{parser.BindQuestion, parser.BindDollar} {
+		t.Run(fmt.Sprint(bind), func(t *testing.T) {
+			nativeQuery := strings.ReplaceAll(query, "?", "$2")
+			if bind == parser.BindQuestion {
+				nativeQuery = query
+			}
+
+			_, _, err := processNamed(nativeQuery, arg, &config{bind: bind, verifyArgCount: true})
+			assert.ErrorContains(t, err, "arguments mismatch after compiling query: placeholders 2 arguments 1")
+
+			_, args, err := processNamed(nativeQuery, arg, &config{bind: bind})
+			assert.NoError(t, err)
+			assert.Equal(t, []any{1}, args)
+		})
+	}
+
+	t.Run("matching", func(t *testing.T) {
+		cfg := &config{bind: parser.BindDollar, verifyArgCount: true}
+
+		arg := map[string]any{"ids": []int{1, 2, 3}, "name": "Alice"}
+		_, args, err := processNamed("SELECT * FROM user WHERE id IN (:ids) OR name = :name", arg, cfg)
+		assert.NoError(t, err)
+		assert.Len(t, args, 4)
+
+		batch := []map[string]any{{"id": 1}, {"id": 2}}
+		_, args, err = processNamed("INSERT INTO user (id) VALUES (:id)", batch, cfg)
+		assert.NoError(t, err)
+		assert.Len(t, args, 2)
+	})
+}
+
+func TestProcessNamed_fastParse(t *testing.T) {
+	cfg := &config{bind: parser.BindDollar, fastParse: true}
+
+	t.Run("simple query", func(t *testing.T) {
+		arg := map[string]any{"id": 1, "name": "Alice"}
+		query, args, err := processNamed("SELECT *\n\tFROM user\n\tWHERE id = :id OR name = :name", arg, cfg)
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT *\n\tFROM user\n\tWHERE id = $1 OR name = $2", query)
+		assert.Equal(t, []any{1, "Alice"}, args)
+
+		arg = map[string]any{"ids": []int{1, 2}, "name": "Alice"}
+		query, args, err = processNamed("SELECT * FROM user WHERE id IN (:ids) OR name = :name", arg, cfg)
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM user WHERE id IN ($1,$2) OR name = $3", query)
+		assert.Equal(t, []any{1, 2, "Alice"}, args)
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		arg := map[string]any{"id": 1}
+		input := "SELECT id::text FROM user WHERE name = ':id' AND id = :id"
+
+		query, args, err := processNamed(input, arg, cfg)
+		assert.NoError(t, err)
+		expectQuery, expectArgs, err := processNamed(input, arg, &config{bind: parser.BindDollar})
+		assert.NoError(t, err)
+		assert.Equal(t, expectQuery, query)
+		assert.Equal(t, expectArgs, args)
+	})
+}
+
 func TestEndingParensIndex(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -471,6 +1149,16 @@ func TestEndingParensIndex(t *testing.T) {
 			input:    "(ABC,DEF,NOW(),NOW())",
 			expected: 20,
 		},
+		{
+			name:     "multi-line",
+			input:    "(\n\t:a,\n\tCOALESCE(\n\t\t:b,\n\t\t0\n\t)\n)",
+			expected: 31,
+		},
+		{
+			name:     "parens inside literals",
+			input:    "(':)', \"(\", `)`, 'it''s (')x",
+			expected: 26,
+		},
 	}
 
 	for _, tt := range tests {
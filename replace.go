@@ -0,0 +1,139 @@
+package sqlz
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/rfberaldo/sqlz/internal/reflectutil"
+)
+
+// ReplaceAll replaces the entire content of table with rows, a slice of structs,
+// atomically: within a transaction, it deletes every row of the table and then
+// batch inserts rows, rolling back if any of them fails.
+// Columns are the fields of the struct, mapped the same way as scanning,
+// see [Options.StructTag] and [Options.FieldNameTransformer]; fields tagged with
+// the "readonly" option, as in `db:"id,readonly"`, are skipped.
+//
+// The table name is used as is, it must not come from user input.
+func (db *DB) ReplaceAll(ctx context.Context, table string, rows any) (err error) {
+	if reflectutil.TypeOfAny(rows) != reflectutil.SliceStruct {
+		return fmt.Errorf("sqlz: rows must be a slice of structs, got %T", rows)
+	}
+
+	rowsValue := reflect.Indirect(reflect.ValueOf(rows))
+	columns, idents, err := db.base.insertColumns(rowsValue.Type().Elem())
+	if err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("sqlz: no columns found in %s", reflectutil.Deref(rowsValue.Type().Elem()))
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err := tx.Exec(ctx, "DELETE FROM "+table, AllowNoWhere()); err != nil {
+		return fmt.Errorf("sqlz: deleting rows from %s: %w", table, err)
+	}
+
+	if rowsValue.Len() > 0 {
+		query := fmt.Sprintf(
+			"INSERT INTO %s (%s) VALUES (:%s)",
+			table, strings.Join(columns, ", "), strings.Join(idents, ", :"),
+		)
+		if _, err := tx.Exec(ctx, query, rows); err != nil {
+			return fmt.Errorf("sqlz: inserting rows into %s: %w", table, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// insertColumns returns the column names and the named query identifiers of
+// the fields of structType, in declaration order. Nested structs are flattened,
+// unless they are column values, like [time.Time], a [driver.Valuer] or tagged
+// with [reflectutil.JSONOption].
+// Fields tagged with [reflectutil.ReadOnlyOption] are skipped, and columns mapped
+// by multiple fields at the same depth return an error, rather than being left out.
+func (c *base) insertColumns(structType reflect.Type) (columns, idents []string, err error) {
+	structType = reflectutil.Deref(structType)
+	if ambiguous := reflectutil.AmbiguousKeys(structType, c.structTag, "_", c.fieldNameTransformer); len(ambiguous) > 0 {
+		return nil, nil, fmt.Errorf(
+			"sqlz: ambiguous columns '%s', mapped by multiple fields at the same depth of %s",
+			strings.Join(ambiguous, "', '"), structType,
+		)
+	}
+
+	columnByIndex := reflectutil.StructFieldMap(structType, c.structTag, "_", c.fieldNameTransformer)
+	identByIndex := make(map[string]string)
+	for ident, index := range reflectutil.StructFieldMap(structType, c.structTag, ".", c.fieldNameTransformer) {
+		identByIndex[fmt.Sprint(index)] = ident
+	}
+
+	type field struct {
+		index  []int
+		column string
+		ident  string
+	}
+
+	var fields []field
+	for column, index := range columnByIndex {
+		if !isColumnField(structType, index, c.structTag) {
+			continue
+		}
+		fields = append(fields, field{index, column, identByIndex[fmt.Sprint(index)]})
+	}
+
+	slices.SortFunc(fields, func(a, b field) int { return slices.Compare(a.index, b.index) })
+
+	for _, f := range fields {
+		columns = append(columns, f.column)
+		idents = append(idents, f.ident)
+	}
+
+	return columns, idents, nil
+}
+
+// isColumnField reports whether the field at index holds a writable column
+// value, rather than being a struct to be flattened, part of a column value,
+// or read-only, including when nested in a read-only struct.
+func isColumnField(t reflect.Type, index []int, tag string) bool {
+	for i, fieldIndex := range index {
+		field := t.Field(fieldIndex)
+		if reflectutil.HasTagOption(field, tag, reflectutil.ReadOnlyOption) {
+			return false
+		}
+
+		t = reflectutil.Deref(field.Type)
+		isLast := i == len(index)-1
+
+		if isColumnValue(t) || reflectutil.HasTagOption(field, tag, reflectutil.JSONOption) {
+			return isLast
+		}
+	}
+
+	return t.Kind() != reflect.Struct
+}
+
+// isColumnValue reports whether t is sent to the database as a single value.
+func isColumnValue(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	return t == timeType ||
+		isIPType(t) ||
+		isScannable(t) ||
+		t.Implements(valuerType) ||
+		reflect.PointerTo(t).Implements(valuerType)
+}
@@ -0,0 +1,190 @@
+package sqlz
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDB_ReplaceAll(t *testing.T) {
+	type Country struct {
+		Code string
+		Name string
+	}
+
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, nil)
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := db.Exec(ctx, th.fmt(`CREATE TABLE %s (code VARCHAR(2) PRIMARY KEY, name VARCHAR(100) NOT NULL)`))
+		require.NoError(t, err)
+
+		_, err = db.Exec(ctx, th.fmt(`INSERT INTO %s (code, name) VALUES ('xx', 'Unknown')`))
+		require.NoError(t, err)
+
+		selectAll := th.fmt(`SELECT code, name FROM %s ORDER BY code`)
+
+		t.Run("replaces content", func(t *testing.T) {
+			countries := []Country{{"br", "Brazil"}, {"pt", "Portugal"}}
+			err := db.ReplaceAll(ctx, th.tableName, countries)
+			require.NoError(t, err)
+
+			var got []Country
+			err = db.Query(ctx, selectAll).Scan(&got)
+			require.NoError(t, err)
+			assert.Equal(t, countries, got)
+		})
+
+		t.Run("rolls back on error", func(t *testing.T) {
+			duplicated := []*Country{{"de", "Germany"}, {"de", "Germany"}}
+			err := db.ReplaceAll(ctx, th.tableName, duplicated)
+			require.Error(t, err)
+			assert.ErrorContains(t, err, "inserting rows into")
+
+			var got []Country
+			err = db.Query(ctx, selectAll).Scan(&got)
+			require.NoError(t, err)
+			assert.Equal(t, []Country{{"br", "Brazil"}, {"pt", "Portugal"}}, got)
+		})
+
+		t.Run("empty rows", func(t *testing.T) {
+			err := db.ReplaceAll(ctx, th.tableName, []Country{})
+			require.NoError(t, err)
+
+			var got []Country
+			err = db.Query(ctx, selectAll).Scan(&got)
+			require.NoError(t, err)
+			assert.Empty(t, got)
+		})
+	})
+}
+
+func TestDB_ReplaceAll_validate_rows(t *testing.T) {
+	db := New("sqlite3", &sql.DB{}, nil)
+
+	err := db.ReplaceAll(ctx, "country", []string{"br"})
+	assert.ErrorContains(t, err, "rows must be a slice of structs")
+
+	err = db.ReplaceAll(ctx, "country", struct{ Code string }{"br"})
+	assert.ErrorContains(t, err, "rows must be a slice of structs")
+
+	err = db.ReplaceAll(ctx, "country", []struct{ code string }{{"br"}})
+	assert.ErrorContains(t, err, "no columns found")
+}
+
+func TestBase_insertColumns(t *testing.T) {
+	type Audit struct {
+		CreatedAt time.Time
+		CreatedBy sql.NullString
+	}
+
+	type Address struct {
+		City string
+	}
+
+	type User struct {
+		Id       int `db:"user_id"`
+		Name     string
+		Address  *Address
+		internal bool
+		Audit
+	}
+
+	base := newBase(nil)
+	columns, idents, err := base.insertColumns(reflect.TypeFor[User]())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user_id", "name", "address_city", "created_at", "created_by"}, columns)
+	assert.Equal(t, []string{"user_id", "name", "address.city", "created_at", "created_by"}, idents)
+}
+
+func TestBase_insertColumns_ambiguous(t *testing.T) {
+	type Owner struct {
+		Id   int
+		Name string
+	}
+
+	type Team struct {
+		Id   int
+		Name string
+	}
+
+	type Project struct {
+		Owner
+		Team
+		Title string
+	}
+
+	base := newBase(nil)
+	_, _, err := base.insertColumns(reflect.TypeFor[Project]())
+	assert.ErrorContains(t, err, "ambiguous columns 'id', 'name', mapped by multiple fields at the same depth")
+
+	db := New("mock", sql.OpenDB(&countingConnector{}), &Options{Bind: BindQuestion})
+	err = db.Update(ctx, "project", "title", &Project{Title: "sqlz"})
+	assert.ErrorContains(t, err, "ambiguous columns 'id', 'name'")
+}
+
+func TestBase_insertColumns_json(t *testing.T) {
+	type Meta struct {
+		Tags []string
+	}
+
+	type Doc struct {
+		Id   int
+		Meta Meta `db:"meta,json"`
+	}
+
+	base := newBase(nil)
+	columns, idents, err := base.insertColumns(reflect.TypeFor[Doc]())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "meta"}, columns)
+	assert.Equal(t, []string{"id", "meta"}, idents)
+}
+
+func TestBase_insertColumns_readonly(t *testing.T) {
+	type Audit struct {
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}
+
+	type User struct {
+		Id    int    `db:"id,readonly"`
+		Name  string `db:"name"`
+		Email string
+		Audit Audit `db:",readonly"`
+	}
+
+	base := newBase(nil)
+	columns, idents, err := base.insertColumns(reflect.TypeFor[User]())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "email"}, columns)
+	assert.Equal(t, []string{"name", "email"}, idents)
+
+	t.Run("still scanned", func(t *testing.T) {
+		ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		row := -1
+		rows := &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"id", "name", "audit_created_at"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < 1
+			},
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*int) = 42
+				*dest[1].(*string) = "Alice"
+				*dest[2].(*time.Time) = ts
+				return nil
+			},
+		}
+
+		var user User
+		err := newRowScanner(rows, nil).Scan(&user)
+		require.NoError(t, err)
+		assert.Equal(t, User{Id: 42, Name: "Alice", Audit: Audit{CreatedAt: ts}}, user)
+	})
+}
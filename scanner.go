@@ -1,16 +1,28 @@
 package sqlz
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
 	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/rfberaldo/sqlz/internal/reflectutil"
 )
 
-// rows defines the minimal interface for iterating over
-// and scanning database query results. It is satisfied by [sql.Rows].
-type rows interface {
+// Rows defines the minimal interface for iterating over and scanning database
+// query results, see [NewScanner]. It is satisfied by [sql.Rows].
+// [Scanner.ScanAuto] also uses the column types of rows implementing
+// ColumnTypes, as [sql.Rows] does, otherwise it returns values as is.
+type Rows interface {
 	Close() error
 	Columns() ([]string, error)
 	Err() error
@@ -18,32 +30,54 @@ type rows interface {
 	Scan(dest ...any) error
 }
 
-// Scanner is the result of calling [DB.Query] or [DB.QueryRow].
+// columnTyper is implemented by [Rows] reporting column types, like [sql.Rows].
+type columnTyper interface {
+	ColumnTypes() ([]*sql.ColumnType, error)
+}
+
+// Scanner is the result of calling [DB.Query], [DB.QueryRow] or [NewScanner].
 type Scanner struct {
 	*config
 
 	// one of these two will be non-nil:
 	err  error // deferred error
-	rows rows
+	rows Rows
 
+	ctx             context.Context // optional, stops manual iteration when done
 	manualIterating bool
 	columns         []string
 	queryRow        bool
 	destType        reflectutil.Type
 	fieldIndexByKey map[string][]int
-	ptrs            []any // slice of pointers for scan, used in all methods
-	values          []any // slice of values from rows, used in map scanning
-	noop            any   // ignored fields sink
+	rowNumIndex     []int // index of the struct field tagged with ",rownum"
+	rowNum          int   // 1-based position of the row being scanned
+	rowsScanned     int   // rows successfully scanned by [Scanner.Scan]
+	nullableStructs []nullableStruct
+	nullableByCol   []int          // index of nullableStructs by column, -1 if none
+	csvByCol        []bool         // whether the field of the column is tagged with ",csv"
+	jsonByCol       []bool         // whether the field of the column is tagged with ",json"
+	timeFormatByCol []string       // layout of the field of the column tagged with ",timeformat=", if any
+	timers          []timedScanner // by column, see [Options.CollectColumnTimings]
+	timedPtrs       []any
+	columnMap       map[string]string // set by [WithColumnMap]
+	mapKey          reflect.Value     // key of a map of structs or values destination, the first column
+	mapValues       bool              // whether the mapKey destination is a map of values
+	ignoredByCol    []bool            // whether the column is an ignored duplicate, see [Options.DuplicateColumnMode]
+	ptrs            []any             // slice of pointers for scan, used in all methods
+	values          []any             // slice of values from rows, used in map scanning
+	rawValues       []any             // values of [Scanner.RawValues], apart from the ones of other destinations
+	rawPtrs         []any             // pointers to rawValues
+	noop            any               // ignored fields sink
 }
 
-func newScanner(rows rows, cfg *config) *Scanner {
+func newScanner(rows Rows, cfg *config) *Scanner {
 	return &Scanner{
 		config: applyDefaults(cfg),
 		rows:   rows,
 	}
 }
 
-func newRowScanner(rows rows, cfg *config) *Scanner {
+func newRowScanner(rows Rows, cfg *config) *Scanner {
 	return &Scanner{
 		config:   applyDefaults(cfg),
 		rows:     rows,
@@ -65,16 +99,104 @@ func (s *Scanner) resolveColumns() (err error) {
 		return fmt.Errorf("sqlz/scan: no columns in result set")
 	}
 
-	seen := make(map[string]bool, len(s.columns))
-	for _, col := range s.columns {
-		if _, ok := seen[col]; ok {
+	if s.maxColumns > 0 && len(s.columns) > s.maxColumns {
+		return fmt.Errorf("sqlz/scan: too many columns in result set: got %d, limit is %d", len(s.columns), s.maxColumns)
+	}
+
+	if s.stripColumnTablePrefix {
+		for i, col := range s.columns {
+			if pos := strings.LastIndexByte(col, '.'); pos > -1 {
+				s.columns[i] = col[pos+1:]
+			}
+		}
+	}
+
+	firstByCol := make(map[string]int, len(s.columns))
+	for i, col := range s.columns {
+		first, ok := firstByCol[col]
+		if !ok {
+			firstByCol[col] = i
+			continue
+		}
+		if s.duplicateColumnMode == DuplicateColumnError {
 			return fmt.Errorf("sqlz/scan: duplicate column name: '%s'", col)
 		}
-		seen[col] = true
+		s.ignoreDuplicate(first, i)
+		firstByCol[col] = s.keptDuplicate(first, i)
 	}
 	return nil
 }
 
+// ignoreDuplicate flags the column that is not scanned of the duplicate columns
+// first and i, with first < i, see [Options.DuplicateColumnMode].
+func (s *Scanner) ignoreDuplicate(first, i int) {
+	if s.ignoredByCol == nil {
+		s.ignoredByCol = make([]bool, len(s.columns))
+	}
+
+	if s.duplicateColumnMode == DuplicateColumnFirst {
+		s.ignoredByCol[i] = true
+	} else {
+		s.ignoredByCol[first] = true
+	}
+}
+
+// keptDuplicate returns the column that is scanned of the duplicate columns first and i.
+func (s *Scanner) keptDuplicate(first, i int) int {
+	if s.duplicateColumnMode == DuplicateColumnFirst {
+		return first
+	}
+	return i
+}
+
+// resolveDuplicateFields flags the columns mapped to the same struct field as
+// another column; [DuplicateColumnError] keeps the last of them, as it's not an error.
+func (s *Scanner) resolveDuplicateFields() {
+	colByField := make(map[string]int, len(s.columns))
+	for i, col := range s.columns {
+		if i == 0 && s.mapKey.IsValid() || s.ignoredByCol != nil && s.ignoredByCol[i] {
+			continue
+		}
+
+		index, ok := s.fieldIndexByKey[col]
+		if !ok {
+			continue
+		}
+
+		key := fmt.Sprint(index)
+		first, ok := colByField[key]
+		if !ok {
+			colByField[key] = i
+			continue
+		}
+		s.ignoreDuplicate(first, i)
+		colByField[key] = s.keptDuplicate(first, i)
+	}
+}
+
+// AfterScanner is implemented by struct destinations which need to run after
+// being populated, e.g. to derive computed fields or validate them.
+// AfterScan is called once per row, an error stops scanning and is returned
+// by [Scanner.Scan] or [Scanner.ScanRow].
+type AfterScanner interface {
+	AfterScan() error
+}
+
+// ErrUnsupportedDest is returned when scanning into an unsupported destination
+// type, use [errors.As] to get the offending type:
+//
+//	var destErr *sqlz.ErrUnsupportedDest
+//	if errors.As(err, &destErr) {
+//		log.Printf("cannot scan into %s", destErr.Type)
+//	}
+type ErrUnsupportedDest struct {
+	Type reflect.Type // type of the destination, as passed to Scan
+}
+
+func (e *ErrUnsupportedDest) Error() string {
+	return fmt.Sprintf("sqlz/scan: unsupported destination type: %s", e.Type)
+}
+
 func (s *Scanner) resolveDestType(dest any) error {
 	if s.destType != reflectutil.Invalid {
 		return nil
@@ -82,8 +204,48 @@ func (s *Scanner) resolveDestType(dest any) error {
 
 	s.destType = reflectutil.TypeOfAny(dest)
 
+	// IP types are scanned from their text form, see [ipScanner]
+	if destType := reflect.TypeOf(dest); destType != nil {
+		destType = reflectutil.Deref(destType)
+		switch {
+		case isIPType(destType):
+			s.destType = reflectutil.Primitive
+		case destType.Kind() == reflect.Slice && isIPType(destType.Elem()):
+			s.destType = reflectutil.SlicePrimitive
+
+		// otherwise unsupported kinds implementing [sql.Scanner], e.g. [UUID] arrays
+		case s.destType == reflectutil.Invalid && isScannable(destType):
+			s.destType = reflectutil.Primitive
+		case s.destType == reflectutil.Invalid && destType.Kind() == reflect.Slice && isScannable(destType.Elem()):
+			s.destType = reflectutil.SlicePrimitive
+		}
+	}
+
 	if s.destType == reflectutil.Invalid {
-		return fmt.Errorf("sqlz/scan: unsupported destination type: %T", dest)
+		return &ErrUnsupportedDest{reflect.TypeOf(dest)}
+	}
+
+	if s.destType == reflectutil.Map && isStructMap(reflect.TypeOf(dest)) {
+		if len(s.columns) < 2 {
+			return fmt.Errorf(
+				"sqlz/scan: query must return at least 2 columns to scan into a map of structs, got %d",
+				len(s.columns),
+			)
+		}
+		s.mapKey = reflect.New(reflectutil.Deref(reflect.TypeOf(dest)).Key()).Elem()
+		return nil
+	}
+
+	if s.destType == reflectutil.Map && isValueMap(reflect.TypeOf(dest)) {
+		if len(s.columns) != 2 {
+			return fmt.Errorf(
+				"sqlz/scan: query must return 2 columns to scan into a map of values, got %d",
+				len(s.columns),
+			)
+		}
+		s.mapKey = reflect.New(reflectutil.Deref(reflect.TypeOf(dest)).Key()).Elem()
+		s.mapValues = true
+		return nil
 	}
 
 	if !s.manualIterating && !s.queryRow && !s.destType.IsSlice() {
@@ -111,7 +273,9 @@ func (s *Scanner) Scan(dest any) (err error) {
 		panic("sqlz/scan: Scan cannot be used with manual iteration, use ScanRow instead")
 	}
 
+	// rows are only closed by scanAll, don't hold the connection if rejected before it
 	if err := s.resolveColumns(); err != nil {
+		s.rows.Close()
 		return err
 	}
 
@@ -144,204 +308,1457 @@ func (s *Scanner) ScanRow(dest any) (err error) {
 	return s.scanOne(dest)
 }
 
-func (s *Scanner) scanAll(dest any) (err error) {
+// ScanFirst scans the first row into dest regardless of type, and returns a new
+// [Scanner] for the remaining rows, to be consumed with [Scanner.Scan] or a
+// [Scanner.NextRow] loop, which is useful for "header row + rest" results.
+// If there are no rows, it returns [sql.ErrNoRows].
+// ScanFirst should not be called more than once per [Scanner] instance,
+// nor mixed with other scanning methods on it.
+func (s *Scanner) ScanFirst(dest any) (rest *Scanner, err error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if s.manualIterating {
+		panic("sqlz/scan: ScanFirst cannot be used with manual iteration, use ScanRow instead")
+	}
+
 	defer func() {
-		if errClose := s.rows.Close(); errClose != nil {
-			err = fmt.Errorf("sqlz/scan: closing rows: %w", errClose)
+		if err != nil {
+			s.rows.Close()
 		}
 	}()
 
-	rowCount := 0
-	for s.rows.Next() {
-		if err := s.scanOne(dest); err != nil {
-			return err
-		}
-		rowCount++
+	if err := s.resolveColumns(); err != nil {
+		return nil, err
+	}
 
-		if s.queryRow && rowCount > 1 {
-			return fmt.Errorf("sqlz/scan: expected one row, got more")
+	if !s.rows.Next() {
+		if err := s.rows.Err(); err != nil {
+			return nil, nextRowError(err)
 		}
+		return nil, sql.ErrNoRows
 	}
 
-	if err := s.rows.Err(); err != nil {
-		return fmt.Errorf("sqlz/scan: preparing next row: %w", err)
+	// dest type may differ from the remaining rows, so each one has its own state
+	first := &Scanner{config: s.config, rows: s.rows, columns: s.columns, manualIterating: true}
+	if err := first.resolveDestType(dest); err != nil {
+		return nil, err
 	}
-
-	if s.queryRow && rowCount == 0 {
-		return sql.ErrNoRows
+	if err := first.scanOne(dest); err != nil {
+		return nil, err
 	}
 
-	return err
+	return &Scanner{config: s.config, rows: s.rows, columns: s.columns, ctx: s.ctx, rowNum: 1}, nil
 }
 
-func (s *Scanner) scanOne(dest any) (err error) {
-	destValue := reflectutil.Init(reflect.ValueOf(dest))
-	if !destValue.CanSet() {
-		return fmt.Errorf("sqlz/scan: destination must be addressable: %T", dest)
+// RawValues scans the current row and returns its raw column values, in the same
+// order as the columns, it must be called inside a [NextRow] loop.
+// The returned slice is reused on every call, it must not be retained across rows.
+func (s *Scanner) RawValues() ([]any, error) {
+	if s.err != nil {
+		return nil, s.err
 	}
 
-	if s.destType.IsSlice() {
-		if destValue.Len() == destValue.Cap() {
-			destValue.Grow(1)
+	if !s.manualIterating {
+		panic("sqlz/scan: RawValues can only be used with manual iteration")
+	}
+
+	if err := s.resolveColumns(); err != nil {
+		return nil, err
+	}
+
+	if s.rawPtrs == nil {
+		s.rawValues = make([]any, len(s.columns))
+		s.rawPtrs = make([]any, len(s.columns))
+		for i := range s.rawValues {
+			s.rawPtrs[i] = &s.rawValues[i]
 		}
-		destValue.SetLen(destValue.Len() + 1)
 	}
 
-	switch s.destType {
-	case reflectutil.Primitive:
-		return s.scan(dest)
+	if err := s.rows.Scan(s.rawPtrs...); err != nil {
+		return nil, fmt.Errorf("sqlz/scan: scanning raw values: %w", err)
+	}
 
-	case reflectutil.SlicePrimitive:
-		elValue := destValue.Index(destValue.Len() - 1)
-		return s.scan(elValue.Addr().Interface())
+	return s.rawValues, nil
+}
 
-	case reflectutil.Struct:
-		return s.scanStruct(dest)
+// ScanColumn automatically iterates over rows and scans only the named column,
+// ignoring the others, into dest, which must be a pointer to a slice:
+//
+//	var emails []string
+//	err := db.Query(ctx, "SELECT id, name, email FROM user").ScanColumn(&emails, "email")
+//
+// From [DB.QueryRow], dest is a pointer to a single value instead,
+// and it returns [sql.ErrNoRows] if there are no rows.
+// ScanColumn should not be called more than once per [Scanner] instance.
+func (s *Scanner) ScanColumn(dest any, column string) (err error) {
+	if s.err != nil {
+		return s.err
+	}
 
-	case reflectutil.SliceStruct:
-		elValue := destValue.Index(destValue.Len() - 1)
-		return s.scanStruct(elValue.Addr().Interface())
+	if s.manualIterating {
+		panic("sqlz/scan: ScanColumn cannot be used with manual iteration, use ScanRow instead")
+	}
 
-	case reflectutil.Map:
-		return s.scanMap(destValue.Interface())
+	defer func() {
+		if errClose := s.rows.Close(); errClose != nil {
+			err = fmt.Errorf("sqlz/scan: closing rows: %w", errClose)
+		}
+	}()
 
-	case reflectutil.SliceMap:
-		elValue := destValue.Index(destValue.Len() - 1)
-		elValue = reflectutil.Init(elValue)
-		return s.scanMap(elValue.Interface())
+	if err := s.resolveColumns(); err != nil {
+		return err
 	}
 
-	panic("sqlz/scan: type not handled, got " + destValue.Type().String())
-}
+	index := slices.Index(s.columns, column)
+	if index == -1 {
+		return fmt.Errorf("sqlz/scan: column not found: '%s'", column)
+	}
 
-func (s *Scanner) scan(dest ...any) error {
-	s.ptrs = s.ptrs[:0] // empty slice keeping the underlying array
-	s.ptrs = append(s.ptrs, dest...)
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Pointer || destValue.IsNil() {
+		return fmt.Errorf("sqlz/scan: destination must be a non-nil pointer, got %T", dest)
+	}
+	destValue = destValue.Elem()
+	if !s.queryRow && destValue.Kind() != reflect.Slice {
+		return fmt.Errorf("sqlz/scan: destination must be a slice to scan multiple rows, got %T", dest)
+	}
 
-	if err := s.rows.Scan(s.ptrs...); err != nil {
-		return fmt.Errorf("sqlz/scan: scanning row: %w", err)
+	s.ptrs = make([]any, len(s.columns))
+	for i := range s.ptrs {
+		s.ptrs[i] = &s.noop
+	}
+
+	for s.rows.Next() {
+		s.rowsScanned++
+		if s.queryRow && s.rowsScanned > 1 {
+			return fmt.Errorf("sqlz/scan: expected one row, got more")
+		}
+
+		s.ptrs[index] = dest
+		if !s.queryRow {
+			destValue.Grow(1)
+			destValue.SetLen(destValue.Len() + 1)
+			s.ptrs[index] = destValue.Index(destValue.Len() - 1).Addr().Interface()
+		}
+
+		if err := s.scanPtrs(); err != nil {
+			return fmt.Errorf("sqlz/scan: scanning column '%s': %w", column, err)
+		}
+	}
+
+	if err := s.rows.Err(); err != nil {
+		return nextRowError(err)
+	}
+
+	if s.queryRow && s.rowsScanned == 0 {
+		return sql.ErrNoRows
 	}
 
 	return nil
 }
 
-func (s *Scanner) scanMap(dest any) error {
-	m, errMap := assertMap(dest)
-	if errMap != nil {
-		return errMap
+// FieldPlan returns the index of the struct field each column is scanned into,
+// given the struct type of dest, e.g. *User or *[]User, which helps debugging
+// mapping issues; rows are not consumed, so it can be called before scanning.
+// Like scanning, missing fields return an error, unless [Options.IgnoreMissingFields]
+// is set, then their columns are left out, as are ignored duplicate columns.
+func (s *Scanner) FieldPlan(dest any) (map[string][]int, error) {
+	if s.err != nil {
+		return nil, s.err
 	}
 
-	s.setMapPtrs()
+	if err := s.resolveColumns(); err != nil {
+		return nil, err
+	}
 
-	if err := s.rows.Scan(s.ptrs...); err != nil {
-		return fmt.Errorf("sqlz/scan: scanning row into map: %w", err)
+	t := reflect.TypeOf(dest)
+	if t != nil {
+		t = reflectutil.Deref(t)
+		if t.Kind() == reflect.Slice {
+			t = reflectutil.Deref(t.Elem())
+		}
+	}
+	if t == nil || t.Kind() != reflect.Struct || isScannable(t) {
+		return nil, fmt.Errorf("sqlz/scan: field plan destination must be a struct, got %T", dest)
+	}
+
+	// resolved apart from the scanning state, dest may differ from the scanned one
+	prev, prevIgnored := s.fieldIndexByKey, slices.Clone(s.ignoredByCol)
+	defer func() { s.fieldIndexByKey, s.ignoredByCol = prev, prevIgnored }()
+
+	s.fieldIndexByKey = reflectutil.StructFieldMap(t, s.structTag, "_", s.fieldNameTransformer)
+	s.resolveOrdinalKeys()
+	s.resolveColumnMap(t)
+	if err := s.checkAmbiguousColumns(t); err != nil {
+		return nil, err
 	}
+	s.resolveDuplicateFields()
 
+	plan := make(map[string][]int, len(s.columns))
 	for i, col := range s.columns {
-		v := s.values[i]
-		if v, ok := v.([]byte); ok {
-			m[col] = string(v)
+		if s.ignoredByCol != nil && s.ignoredByCol[i] {
 			continue
 		}
-		m[col] = v
+
+		index, ok := s.fieldIndexByKey[col]
+		if !ok {
+			if !s.ignoreMissingFields {
+				return nil, fmt.Errorf("sqlz/scan: struct field not found: '%s' (maybe unexported?)", col)
+			}
+			continue
+		}
+		plan[col] = index
 	}
 
-	return nil
+	return plan, nil
 }
 
-func (s *Scanner) setMapPtrs() {
-	if s.ptrs != nil {
-		return
+// ScanAuto automatically iterates over rows and returns each one as a map,
+// using the column types reported by the driver to produce typed values,
+// e.g. int64, float64, bool, string or [time.Time]; NULL values are nil.
+// If the driver does not report column types, values are returned as is.
+// ScanAuto should not be called more than once per [Scanner] instance.
+func (s *Scanner) ScanAuto() (result []map[string]any, err error) {
+	if s.err != nil {
+		return nil, s.err
 	}
 
-	s.values = make([]any, len(s.columns))
-	s.ptrs = make([]any, len(s.columns))
+	if s.manualIterating {
+		panic("sqlz/scan: ScanAuto cannot be used with manual iteration, use ScanRow instead")
+	}
 
-	for i := range s.values {
-		s.ptrs[i] = &s.values[i]
+	defer func() {
+		if errClose := s.rows.Close(); errClose != nil {
+			err = fmt.Errorf("sqlz/scan: closing rows: %w", errClose)
+		}
+	}()
+
+	if err := s.resolveColumns(); err != nil {
+		return nil, err
 	}
-}
 
-func isScannable(t reflect.Type) bool {
-	return reflect.PointerTo(t).Implements(scannerType) || t.Implements(scannerType)
-}
+	if err := s.setAutoPtrs(); err != nil {
+		return nil, err
+	}
 
-func (s *Scanner) scanStruct(dest any) error {
-	destValue := reflectutil.Init(reflect.ValueOf(dest))
+	for s.rows.Next() {
+		if err := s.rows.Scan(s.ptrs...); err != nil {
+			return nil, fmt.Errorf("sqlz/scan: scanning row: %w", err)
+		}
 
-	// if implements [sql.Scanner], just scan it natively
-	if isScannable(destValue.Type()) {
-		return s.scan(dest)
+		m := make(map[string]any, len(s.columns))
+		for i, col := range s.columns {
+			if s.ignoredByCol != nil && s.ignoredByCol[i] {
+				continue
+			}
+			m[col] = autoValue(s.ptrs[i])
+		}
+		result = append(result, m)
+
+		if s.queryRow && len(result) > 1 {
+			return nil, fmt.Errorf("sqlz/scan: expected one row, got more")
+		}
 	}
 
-	if err := s.setStructPtrs(destValue); err != nil {
-		return err
+	if err := s.rows.Err(); err != nil {
+		return nil, nextRowError(err)
 	}
 
-	if err := s.rows.Scan(s.ptrs...); err != nil {
-		return fmt.Errorf("sqlz/scan: scanning row into struct: %w", err)
+	if s.queryRow && len(result) == 0 {
+		return nil, sql.ErrNoRows
 	}
 
-	return nil
+	return result, nil
 }
 
-func (s *Scanner) setStructPtrs(v reflect.Value) error {
-	if s.ptrs == nil {
-		s.ptrs = make([]any, len(s.columns))
+// setAutoPtrs allocates a pointer to pointer of each column scan type,
+// so NULL values are represented as a nil pointer.
+func (s *Scanner) setAutoPtrs() error {
+	var colTypes []*sql.ColumnType
+	if rows, ok := s.rows.(columnTyper); ok {
+		var err error
+		colTypes, err = rows.ColumnTypes()
+		if err != nil {
+			return fmt.Errorf("sqlz/scan: getting column types: %w", err)
+		}
 	}
 
-	if s.fieldIndexByKey == nil {
-		s.fieldIndexByKey = reflectutil.StructFieldMap(
-			v.Type(), s.structTag, "_", s.fieldNameTransformer,
-		)
+	s.ptrs = make([]any, len(s.columns))
+	for i := range s.ptrs {
+		scanType := anyType
+		if len(colTypes) == len(s.columns) {
+			scanType = autoScanType(colTypes[i].ScanType())
+		}
+		s.ptrs[i] = reflect.New(reflect.PointerTo(scanType)).Interface()
 	}
 
-	for i, col := range s.columns {
-		index, ok := s.fieldIndexByKey[col]
-		if !ok {
-			if !s.ignoreMissingFields {
-				return fmt.Errorf("sqlz/scan: struct field not found: '%s' (maybe unexported?)", col)
-			}
-			s.ptrs[i] = &s.noop
-			continue
-		}
+	return nil
+}
 
-		fv := reflectutil.FieldByIndex(v, index)
-		if !fv.IsValid() {
-			return fmt.Errorf("sqlz/scan: invalid struct field: '%s'", col)
-		}
-		s.ptrs[i] = fv.Addr().Interface()
+// autoScanType returns a safe type to scan into given the driver scan type,
+// falls back to any for unknown types.
+func autoScanType(t reflect.Type) reflect.Type {
+	if t == nil {
+		return anyType
 	}
 
-	return nil
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return anyType
+	}
+
+	// [sql.RawBytes] is only valid until the next call to Next
+	if t == rawBytesType {
+		return bytesType
+	}
+
+	return t
 }
 
-// Close closes [Scanner], preventing further enumeration, and returning the connection to the pool.
-// Close is idempotent and does not affect the result of [Scanner.Err].
-func (s *Scanner) Close() error {
-	if s.rows == nil {
+// autoValue dereferences a pointer allocated by [Scanner.setAutoPtrs],
+// unwrapping nullable types like [sql.NullInt64].
+func autoValue(ptr any) any {
+	v := reflect.ValueOf(ptr).Elem()
+	if v.IsNil() {
 		return nil
 	}
-	if err := s.rows.Close(); err != nil {
-		return fmt.Errorf("sqlz/scan: closing rows: %w", err)
+
+	val := v.Elem().Interface()
+	if valuer, ok := val.(driver.Valuer); ok {
+		if dv, err := valuer.Value(); err == nil {
+			return dv
+		}
 	}
-	return nil
+
+	if b, ok := val.([]byte); ok && v.Elem().Type() == anyType {
+		return string(b)
+	}
+
+	return val
 }
 
-// NextRow prepares the next result row for reading with [Scanner.ScanRow].
+// Pair is a key/value row scanned by [Pairs].
+type Pair[K, V any] struct {
+	Key   K
+	Value V
+}
+
+// Pairs automatically iterates over rows of a two-column result and returns them
+// as key/value pairs, the first column being the key. Unlike scanning into a map,
+// the rows order and duplicate keys are preserved.
+// Pairs should not be called more than once per [Scanner] instance.
+func Pairs[K, V any](s *Scanner) (result []Pair[K, V], err error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if s.manualIterating {
+		panic("sqlz/scan: Pairs cannot be used with manual iteration, use ScanRow instead")
+	}
+
+	defer func() {
+		if errClose := s.rows.Close(); errClose != nil {
+			err = fmt.Errorf("sqlz/scan: closing rows: %w", errClose)
+		}
+	}()
+
+	if err := s.resolveColumns(); err != nil {
+		return nil, err
+	}
+
+	if len(s.columns) != 2 {
+		return nil, fmt.Errorf("sqlz/scan: query must return 2 columns to scan into pairs, got %d", len(s.columns))
+	}
+
+	for s.rows.Next() {
+		var p Pair[K, V]
+		if err := s.scan(&p.Key, &p.Value); err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+
+		if s.queryRow && len(result) > 1 {
+			return nil, fmt.Errorf("sqlz/scan: expected one row, got more")
+		}
+	}
+
+	if err := s.rows.Err(); err != nil {
+		return nil, nextRowError(err)
+	}
+
+	if s.queryRow && len(result) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return result, nil
+}
+
+// Iter returns a sequence of the rows, each scanned into a fresh T regardless of
+// its type, which streams large results without loading them into a slice:
+//
+//	for user, err := range sqlz.Iter[User](db.Query(ctx, "SELECT * FROM user")) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// Rows are closed when the sequence ends, including by breaking out of the loop.
+// Errors end the sequence, they are yielded once with the zero T, either of
+// scanning a row, or of [Scanner.Err] after the last row.
+// Iter should not be used more than once per [Scanner] instance.
+func Iter[T any](s *Scanner) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		if s.err != nil {
+			yield(zero, s.err)
+			return
+		}
+
+		if s.manualIterating {
+			panic("sqlz/scan: Iter cannot be used with manual iteration, use ScanRow instead")
+		}
+
+		defer s.rows.Close()
+
+		if err := s.resolveColumns(); err != nil {
+			yield(zero, err)
+			return
+		}
+
+		for s.NextRow() {
+			var dest T
+			if err := s.resolveDestType(&dest); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			if err := s.scanOne(&dest); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			if !yield(dest, nil) {
+				return
+			}
+		}
+
+		if err := s.Err(); err != nil {
+			yield(zero, err)
+			return
+		}
+
+		if err := s.rows.Close(); err != nil {
+			yield(zero, fmt.Errorf("sqlz/scan: closing rows: %w", err))
+		}
+	}
+}
+
+func (s *Scanner) scanAll(dest any) (err error) {
+	defer func() {
+		if errClose := s.rows.Close(); errClose != nil {
+			err = fmt.Errorf("sqlz/scan: closing rows: %w", errClose)
+		}
+	}()
+
+	for s.rows.Next() {
+		if err := s.scanOne(dest); err != nil {
+			return err
+		}
+		s.rowsScanned++
+
+		if s.queryRow && s.rowsScanned > 1 {
+			return fmt.Errorf("sqlz/scan: expected one row, got more")
+		}
+	}
+
+	if err := s.rows.Err(); err != nil {
+		return nextRowError(err)
+	}
+
+	if s.queryRow && s.rowsScanned == 0 {
+		return sql.ErrNoRows
+	}
+
+	return err
+}
+
+func (s *Scanner) scanOne(dest any) (err error) {
+	s.rowNum++
+
+	// unlike row maps, a map of structs or values accumulates every row
+	if s.mapKey.IsValid() {
+		return s.scanStructMap(dest)
+	}
+
+	destValue := reflectutil.Init(reflect.ValueOf(dest))
+	if !destValue.CanSet() {
+		return fmt.Errorf("sqlz/scan: destination must be addressable: %T", dest)
+	}
+
+	if s.destType.IsSlice() {
+		if destValue.Len() == destValue.Cap() {
+			destValue.Grow(1)
+		}
+		destValue.SetLen(destValue.Len() + 1)
+	}
+
+	switch s.destType {
+	case reflectutil.Primitive:
+		if isIPType(destValue.Type()) {
+			return s.scan(&ipScanner{s.columns[0], destValue})
+		}
+		return s.scan(dest)
+
+	case reflectutil.SlicePrimitive:
+		elValue := destValue.Index(destValue.Len() - 1)
+		if isIPType(elValue.Type()) {
+			return s.scan(&ipScanner{s.columns[0], elValue})
+		}
+		return s.scan(elValue.Addr().Interface())
+
+	case reflectutil.Struct:
+		return s.scanStruct(dest)
+
+	case reflectutil.SliceStruct:
+		elValue := destValue.Index(destValue.Len() - 1)
+		return s.scanStruct(elValue.Addr().Interface())
+
+	case reflectutil.Map:
+		return s.scanMap(destValue.Interface())
+
+	case reflectutil.SliceMap:
+		elValue := destValue.Index(destValue.Len() - 1)
+		elValue = reflectutil.Init(elValue)
+		return s.scanMap(elValue.Interface())
+	}
+
+	return fmt.Errorf("sqlz/scan: destination type not handled: %s (resolved as %s)", destValue.Type(), s.destType)
+}
+
+func (s *Scanner) scan(dest ...any) error {
+	s.ptrs = s.ptrs[:0] // empty slice keeping the underlying array
+	s.ptrs = append(s.ptrs, dest...)
+
+	if err := s.scanPtrs(); err != nil {
+		return fmt.Errorf("sqlz/scan: scanning row: %w", err)
+	}
+
+	return nil
+}
+
+// scanPtrs scans the current row into s.ptrs, timing the ones implementing
+// [sql.Scanner] if [Options.CollectColumnTimings] is set.
+func (s *Scanner) scanPtrs() error {
+	if !s.collectColumnTimings {
+		return s.rows.Scan(s.ptrs...)
+	}
+
+	// the number of ptrs is the same for every row
+	if s.timers == nil {
+		s.timers = make([]timedScanner, len(s.ptrs))
+		s.timedPtrs = make([]any, len(s.ptrs))
+	}
+
+	for i, ptr := range s.ptrs {
+		if scanner, ok := ptr.(sql.Scanner); ok {
+			s.timers[i].dest = scanner
+			s.timedPtrs[i] = &s.timers[i]
+			continue
+		}
+		s.timedPtrs[i] = ptr
+	}
+
+	return s.rows.Scan(s.timedPtrs...)
+}
+
+// ColumnTimings returns the total time spent scanning each column across the
+// scanned rows, if [Options.CollectColumnTimings] is set, otherwise nil.
+// The driver converts plain values in bulk, so only the columns scanned by a
+// [sql.Scanner] are timed, e.g. custom types decoding BLOBs, or fields with
+// a converter, "csv" or "json" option.
+func (s *Scanner) ColumnTimings() map[string]time.Duration {
+	if s.timers == nil {
+		return nil
+	}
+
+	timings := make(map[string]time.Duration)
+	for i, timer := range s.timers {
+		if timer.timed && i < len(s.columns) {
+			timings[s.columns[i]] = timer.elapsed
+		}
+	}
+
+	return timings
+}
+
+// isStructMap reports whether t, or the type it points to, is a map of structs
+// or pointers to structs, like map[int]*User, rather than a row map.
+func isStructMap(t reflect.Type) bool {
+	t = reflectutil.Deref(t)
+	if t.Kind() != reflect.Map {
+		return false
+	}
+
+	elem := reflectutil.Deref(t.Elem())
+	return elem.Kind() == reflect.Struct &&
+		elem != timeType &&
+		!isIPType(elem) &&
+		!isScannable(elem)
+}
+
+// isValueMap reports whether t, or the type it points to, is a map of values,
+// like map[int]string, rather than a row map.
+func isValueMap(t reflect.Type) bool {
+	t = reflectutil.Deref(t)
+	if t.Kind() != reflect.Map || t == rowMapType {
+		return false
+	}
+
+	elem := reflectutil.Deref(t.Elem())
+	return reflectutil.TypeOf(elem) == reflectutil.Primitive ||
+		elem == timeType ||
+		isIPType(elem) ||
+		isScannable(elem)
+}
+
+// scanStructMap scans the current row into the map of structs m, keyed by
+// the first column, with the remaining columns scanned into the struct;
+// or into the map of values m, with the second column as the value.
+// Existing entries of m are kept, rows with the same key are overwritten.
+func (s *Scanner) scanStructMap(dest any) error {
+	m := reflect.Indirect(reflect.ValueOf(dest))
+	if !m.CanSet() {
+		return fmt.Errorf("sqlz/scan: destination must be addressable: %T", dest)
+	}
+	if m.IsNil() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+
+	valueType := m.Type().Elem()
+	if s.mapValues {
+		return s.scanValueMap(m, valueType)
+	}
+
+	elem := reflect.New(reflectutil.Deref(valueType))
+	if err := s.scanStruct(elem.Interface()); err != nil {
+		return err
+	}
+
+	if valueType.Kind() != reflect.Pointer {
+		elem = elem.Elem()
+	}
+	m.SetMapIndex(s.mapKey, elem)
+
+	return nil
+}
+
+func (s *Scanner) scanValueMap(m reflect.Value, valueType reflect.Type) error {
+	elem := reflect.New(valueType)
+
+	var ptr any = elem.Interface()
+	if isIPType(valueType) {
+		ptr = &ipScanner{s.columns[1], elem.Elem()}
+	}
+
+	if err := s.scan(s.mapKey.Addr().Interface(), ptr); err != nil {
+		return err
+	}
+	m.SetMapIndex(s.mapKey, elem.Elem())
+
+	return nil
+}
+
+func (s *Scanner) scanMap(dest any) error {
+	m, errMap := assertMap(dest)
+	if errMap != nil {
+		return errMap
+	}
+
+	s.setMapPtrs()
+
+	if err := s.scanPtrs(); err != nil {
+		return fmt.Errorf("sqlz/scan: scanning row into map: %w", err)
+	}
+
+	for i, col := range s.columns {
+		if s.ignoredByCol != nil && s.ignoredByCol[i] {
+			continue
+		}
+		v := s.values[i]
+		if v, ok := v.([]byte); ok {
+			m[col] = string(v)
+			continue
+		}
+		m[col] = v
+	}
+
+	return nil
+}
+
+func (s *Scanner) setMapPtrs() {
+	if s.ptrs != nil {
+		return
+	}
+
+	s.values = make([]any, len(s.columns))
+	s.ptrs = make([]any, len(s.columns))
+
+	for i := range s.values {
+		s.ptrs[i] = &s.values[i]
+	}
+}
+
+func isScannable(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(scannerType) || t.Implements(scannerType)
+}
+
+func (s *Scanner) scanStruct(dest any) error {
+	destValue := reflectutil.Init(reflect.ValueOf(dest))
+
+	// if implements [sql.Scanner], just scan it natively
+	if isScannable(destValue.Type()) {
+		return s.scan(dest)
+	}
+
+	if err := s.setStructPtrs(destValue); err != nil {
+		return err
+	}
+
+	if err := s.scanPtrs(); err != nil {
+		return fmt.Errorf("sqlz/scan: scanning row into struct: %w", err)
+	}
+
+	if err := s.setNullableStructs(destValue); err != nil {
+		return err
+	}
+
+	if s.rowNumIndex != nil {
+		if err := s.setRowNum(destValue); err != nil {
+			return err
+		}
+	}
+
+	if hook, ok := destValue.Addr().Interface().(AfterScanner); ok {
+		if err := hook.AfterScan(); err != nil {
+			return fmt.Errorf("sqlz/scan: after scan of row %d: %w", s.rowNum, err)
+		}
+	}
+
+	return nil
+}
+
+// setRowNum sets the current row number into the field tagged with ",rownum".
+func (s *Scanner) setRowNum(v reflect.Value) error {
+	fv := reflectutil.FieldByIndex(v, s.rowNumIndex)
+
+	switch {
+	case fv.CanInt():
+		fv.SetInt(int64(s.rowNum))
+	case fv.CanUint():
+		fv.SetUint(uint64(s.rowNum))
+	default:
+		return fmt.Errorf("sqlz/scan: rownum field must be an integer, got %s", fv.Type())
+	}
+
+	return nil
+}
+
+func (s *Scanner) setStructPtrs(v reflect.Value) error {
+	if s.ptrs == nil {
+		s.ptrs = make([]any, len(s.columns))
+	}
+
+	if s.fieldIndexByKey == nil {
+		s.fieldIndexByKey = reflectutil.StructFieldMap(
+			v.Type(), s.structTag, "_", s.fieldNameTransformer,
+		)
+		s.resolveOrdinalKeys()
+		s.resolveColumnMap(v.Type())
+		if err := s.checkAmbiguousColumns(v.Type()); err != nil {
+			return err
+		}
+		s.resolveDuplicateFields()
+		s.rowNumIndex = reflectutil.RowNumIndex(v.Type(), s.structTag)
+
+		if err := s.resolveOptionColumns(v.Type()); err != nil {
+			return err
+		}
+
+		if s.nilAllNullStructs {
+			s.resolveNullableStructs(v.Type())
+		}
+	}
+
+	for i, col := range s.columns {
+		// the first column is the key of a map of structs destination
+		if i == 0 && s.mapKey.IsValid() {
+			s.ptrs[i] = s.mapKey.Addr().Interface()
+			continue
+		}
+
+		if s.ignoredByCol != nil && s.ignoredByCol[i] {
+			s.ptrs[i] = &s.noop
+			continue
+		}
+
+		index, ok := s.fieldIndexByKey[col]
+		if !ok {
+			if !s.ignoreMissingFields {
+				return fmt.Errorf("sqlz/scan: struct field not found: '%s' (maybe unexported?)", col)
+			}
+			s.ptrs[i] = &s.noop
+			continue
+		}
+
+		// scan by reference, the field is only set after knowing if all columns are NULL
+		if s.nullableByCol != nil && s.nullableByCol[i] > -1 {
+			fieldType := v.Type().FieldByIndex(index).Type
+			s.ptrs[i] = reflect.New(reflect.PointerTo(fieldType)).Interface()
+			continue
+		}
+
+		fv := reflectutil.FieldByIndex(v, index)
+		if !fv.IsValid() {
+			return fmt.Errorf("sqlz/scan: invalid struct field: '%s'", col)
+		}
+
+		if s.timeFormatByCol != nil && s.timeFormatByCol[i] != "" {
+			s.ptrs[i] = &timeFormatScanner{col, fv, s.timeFormatByCol[i]}
+			continue
+		}
+
+		if convert, ok := s.fieldConverters[fv.Type()]; ok {
+			s.ptrs[i] = &convertScanner{col, fv, convert}
+			continue
+		}
+
+		if isIPType(fv.Type()) {
+			s.ptrs[i] = &ipScanner{col, fv}
+			continue
+		}
+
+		if s.csvByCol != nil && s.csvByCol[i] {
+			s.ptrs[i] = &csvScanner{col, fv, s.csvDelimiter, s.nullCollectionMode}
+			continue
+		}
+
+		if s.jsonByCol != nil && s.jsonByCol[i] {
+			s.ptrs[i] = &jsonScanner{col, fv, s.nullCollectionMode}
+			continue
+		}
+
+		if s.strictNumericRange && isSizedNumber(fv.Type()) && !isScannable(fv.Type()) {
+			s.ptrs[i] = &rangeScanner{col, fv}
+			continue
+		}
+
+		s.ptrs[i] = fv.Addr().Interface()
+	}
+
+	return nil
+}
+
+// resolveOrdinalKeys maps fields tagged by column ordinal, like `db:"#0"`, to the
+// name of the column in that position, taking precedence over name matching.
+func (s *Scanner) resolveOrdinalKeys() {
+	for key, index := range s.fieldIndexByKey {
+		if !strings.HasPrefix(key, "#") {
+			continue
+		}
+
+		ordinal, err := strconv.Atoi(key[1:])
+		if err != nil || ordinal < 0 || ordinal >= len(s.columns) {
+			continue
+		}
+
+		s.fieldIndexByKey[s.columns[ordinal]] = index
+	}
+}
+
+// resolveOptionColumns flags the columns mapped to fields tagged with the "json"
+// option, the "csv" option, which must be a slice of strings, and the
+// "timeformat" option, which must be a time.Time.
+func (s *Scanner) resolveOptionColumns(t reflect.Type) error {
+	for i, col := range s.columns {
+		index, ok := s.fieldIndexByKey[col]
+		if !ok {
+			continue
+		}
+
+		field := t.FieldByIndex(index)
+		if reflectutil.HasTagOption(field, s.structTag, reflectutil.JSONOption) {
+			if s.jsonByCol == nil {
+				s.jsonByCol = make([]bool, len(s.columns))
+			}
+			s.jsonByCol[i] = true
+			continue
+		}
+
+		if layout, ok := reflectutil.TagOptionValue(field, s.structTag, reflectutil.TimeFormatOption); ok {
+			if reflectutil.Deref(field.Type) != timeType {
+				return fmt.Errorf("sqlz/scan: timeformat field must be a time.Time, got %s: '%s'", field.Type, col)
+			}
+			if s.timeFormatByCol == nil {
+				s.timeFormatByCol = make([]string, len(s.columns))
+			}
+			s.timeFormatByCol[i] = layout
+			continue
+		}
+
+		if !reflectutil.HasTagOption(field, s.structTag, reflectutil.CSVOption) {
+			continue
+		}
+
+		if field.Type.Kind() != reflect.Slice || field.Type.Elem().Kind() != reflect.String {
+			return fmt.Errorf("sqlz/scan: csv field must be a string slice, got %s: '%s'", field.Type, col)
+		}
+
+		if s.csvByCol == nil {
+			s.csvByCol = make([]bool, len(s.columns))
+		}
+		s.csvByCol[i] = true
+	}
+
+	return nil
+}
+
+// withColumnMap sets the per query column map, see [WithColumnMap].
+func (s *Scanner) withColumnMap(m map[string]string) *Scanner {
+	s.columnMap = m
+	return s
+}
+
+// resolveColumnMap maps the columns of [WithColumnMap] to the field of the
+// given key, or else the field of the given Go name, overriding the normal mapping.
+func (s *Scanner) resolveColumnMap(t reflect.Type) {
+	for col, target := range s.columnMap {
+		if index, ok := s.fieldIndexByKey[target]; ok {
+			s.fieldIndexByKey[col] = index
+			continue
+		}
+
+		if field, ok := t.FieldByName(target); ok && field.IsExported() {
+			s.fieldIndexByKey[col] = field.Index
+		}
+	}
+}
+
+// checkAmbiguousColumns returns an error if a column is not mapped because
+// multiple fields at the same depth map to it, even if missing fields are ignored.
+func (s *Scanner) checkAmbiguousColumns(t reflect.Type) error {
+	missing := slices.ContainsFunc(s.columns, func(col string) bool {
+		_, ok := s.fieldIndexByKey[col]
+		return !ok
+	})
+	if !missing {
+		return nil
+	}
+
+	ambiguous := reflectutil.AmbiguousKeys(t, s.structTag, "_", s.fieldNameTransformer)
+	for _, col := range s.columns {
+		if _, ok := s.fieldIndexByKey[col]; !ok && slices.Contains(ambiguous, col) {
+			return fmt.Errorf("sqlz/scan: ambiguous column '%s', mapped by multiple fields at the same depth of %s", col, t)
+		}
+	}
+
+	return nil
+}
+
+// nullableStruct is a nested or embedded struct pointer field, which is left nil
+// when all of its columns are NULL, see [Options.NilAllNullStructs].
+type nullableStruct struct {
+	index   []int // index of the struct pointer field
+	columns []int // positions of the columns mapped inside it
+}
+
+// resolveNullableStructs groups the columns by the outermost struct pointer field
+// containing them, columns with a field converter, csv or json option are not grouped.
+func (s *Scanner) resolveNullableStructs(t reflect.Type) {
+	s.nullableByCol = make([]int, len(s.columns))
+	groupByKey := make(map[string]int)
+
+	for i, col := range s.columns {
+		s.nullableByCol[i] = -1
+
+		if i == 0 && s.mapKey.IsValid() || s.ignoredByCol != nil && s.ignoredByCol[i] {
+			continue
+		}
+
+		index, ok := s.fieldIndexByKey[col]
+		if !ok {
+			continue
+		}
+
+		if s.timeFormatByCol != nil && s.timeFormatByCol[i] != "" {
+			continue
+		}
+
+		fieldType := t.FieldByIndex(index).Type
+		if _, ok := s.fieldConverters[fieldType]; ok {
+			continue
+		}
+
+		if s.strictNumericRange && isSizedNumber(fieldType) && !isScannable(fieldType) {
+			continue
+		}
+
+		if isIPType(fieldType) {
+			continue
+		}
+
+		if s.csvByCol != nil && s.csvByCol[i] {
+			continue
+		}
+
+		if s.jsonByCol != nil && s.jsonByCol[i] {
+			continue
+		}
+
+		ptrIndex := structPtrIndex(t, index)
+		if ptrIndex == nil {
+			continue
+		}
+
+		key := fmt.Sprint(ptrIndex)
+		g, ok := groupByKey[key]
+		if !ok {
+			g = len(s.nullableStructs)
+			groupByKey[key] = g
+			s.nullableStructs = append(s.nullableStructs, nullableStruct{index: ptrIndex})
+		}
+		s.nullableStructs[g].columns = append(s.nullableStructs[g].columns, i)
+		s.nullableByCol[i] = g
+	}
+}
+
+// structPtrIndex returns the index prefix of the outermost struct pointer field
+// along the field index, excluding the field itself, or nil if there's none.
+func structPtrIndex(t reflect.Type, index []int) []int {
+	for i := range len(index) - 1 {
+		f := t.Field(index[i])
+		if f.Type.Kind() == reflect.Pointer && f.Type.Elem().Kind() == reflect.Struct {
+			return index[:i+1]
+		}
+		t = f.Type
+	}
+	return nil
+}
+
+// setNullableStructs sets the fields of each nullable struct from the scanned
+// values, or sets the struct pointer to nil if all of them are NULL.
+func (s *Scanner) setNullableStructs(v reflect.Value) error {
+	for _, ns := range s.nullableStructs {
+		allNull := true
+		for _, i := range ns.columns {
+			if !reflect.ValueOf(s.ptrs[i]).Elem().IsNil() {
+				allNull = false
+				break
+			}
+		}
+
+		if allNull {
+			reflectutil.FieldByIndex(v, ns.index).SetZero()
+			continue
+		}
+
+		for _, i := range ns.columns {
+			fv := reflectutil.FieldByIndex(v, s.fieldIndexByKey[s.columns[i]])
+			ptr := reflect.ValueOf(s.ptrs[i]).Elem()
+			if !ptr.IsNil() {
+				fv.Set(ptr.Elem())
+				continue
+			}
+
+			switch fv.Kind() {
+			case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+			default:
+				if !isScannable(fv.Type()) {
+					return fmt.Errorf(
+						"sqlz/scan: converting NULL to %s is unsupported: '%s'", fv.Type(), s.columns[i],
+					)
+				}
+			}
+			fv.SetZero()
+		}
+	}
+
+	return nil
+}
+
+// RowsScanned returns the number of rows scanned into the destination by
+// [Scanner.Scan], it should be called after Scan returns.
+func (s *Scanner) RowsScanned() int {
+	return s.rowsScanned
+}
+
+// convertScanner is a [sql.Scanner] shim that sets a struct field
+// with the result of a converter registered in [Options.FieldConverters].
+type convertScanner struct {
+	col     string
+	field   reflect.Value
+	convert func(any) (any, error)
+}
+
+func (c *convertScanner) Scan(src any) error {
+	v, err := c.convert(src)
+	if err != nil {
+		return fmt.Errorf("converting column '%s': %w", c.col, err)
+	}
+
+	if v == nil {
+		c.field.SetZero()
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if !rv.Type().AssignableTo(c.field.Type()) {
+		return fmt.Errorf(
+			"converting column '%s': %T is not assignable to %s", c.col, v, c.field.Type(),
+		)
+	}
+	c.field.Set(rv)
+
+	return nil
+}
+
+// csvScanner is a [sql.Scanner] shim that splits a delimited string column
+// into a string slice field, see [reflectutil.CSVOption].
+type csvScanner struct {
+	col      string
+	field    reflect.Value
+	sep      string
+	nullMode NullCollectionMode
+}
+
+func (c *csvScanner) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		setNullCollection(c.field, c.nullMode)
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("converting column '%s': csv field requires a string column, got %T", c.col, src)
+	}
+
+	var parts []string
+	if s != "" {
+		parts = strings.Split(s, c.sep)
+	}
+
+	slice := reflect.MakeSlice(c.field.Type(), len(parts), len(parts))
+	for i, part := range parts {
+		slice.Index(i).SetString(part)
+	}
+	c.field.Set(slice)
+
+	return nil
+}
+
+// ipScanner is a [sql.Scanner] shim that parses the text form of an IP address
+// column, like Postgres INET and CIDR, into a field of one of [ipTypes].
+type ipScanner struct {
+	col   string
+	field reflect.Value
+}
+
+func (c *ipScanner) Scan(src any) error {
+	var text []byte
+	switch v := src.(type) {
+	case nil:
+		c.field.SetZero()
+		return nil
+	case string:
+		text = []byte(v)
+	case []byte:
+		text = v
+	case fmt.Stringer:
+		text = []byte(v.String())
+	default:
+		return fmt.Errorf("converting column '%s': IP field requires a text column, got %T", c.col, src)
+	}
+
+	field := c.field
+	if field.Kind() == reflect.Pointer {
+		field.Set(reflect.New(field.Type().Elem()))
+		field = field.Elem()
+	}
+
+	// all IP types implement it with pointer receiver
+	u := field.Addr().Interface().(encoding.TextUnmarshaler)
+	if err := u.UnmarshalText(text); err != nil {
+		return fmt.Errorf("converting column '%s': %w", c.col, err)
+	}
+
+	return nil
+}
+
+// isSizedNumber reports whether t, or its element if a pointer, is an integer
+// or float kind narrower than 64 bits, see [Options.StrictNumericRange].
+func isSizedNumber(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint,
+		reflect.Float32:
+		return t.Bits() < 64
+	}
+
+	return false
+}
+
+// rangeScanner is a [sql.Scanner] shim that scans a numeric column into
+// a 64-bit value, then checks that it fits in the sized numeric field,
+// see [Options.StrictNumericRange].
+type rangeScanner struct {
+	col   string
+	field reflect.Value
+}
+
+func (c *rangeScanner) Scan(src any) error {
+	field := c.field
+	if field.Kind() == reflect.Pointer {
+		if src == nil {
+			field.SetZero()
+			return nil
+		}
+		field.Set(reflect.New(field.Type().Elem()))
+		field = field.Elem()
+	}
+
+	if src == nil {
+		return fmt.Errorf("converting column '%s': NULL to %s is unsupported", c.col, field.Type())
+	}
+
+	switch {
+	case field.CanInt():
+		var v sql.NullInt64
+		if err := v.Scan(src); err != nil {
+			return fmt.Errorf("converting column '%s': %w", c.col, err)
+		}
+		if field.OverflowInt(v.Int64) {
+			return fmt.Errorf("converting column '%s': value %d overflows %s", c.col, v.Int64, field.Type())
+		}
+		field.SetInt(v.Int64)
+
+	case field.CanUint():
+		var v sql.Null[uint64]
+		if err := v.Scan(src); err != nil {
+			return fmt.Errorf("converting column '%s': %w", c.col, err)
+		}
+		if field.OverflowUint(v.V) {
+			return fmt.Errorf("converting column '%s': value %d overflows %s", c.col, v.V, field.Type())
+		}
+		field.SetUint(v.V)
+
+	default:
+		var v sql.NullFloat64
+		if err := v.Scan(src); err != nil {
+			return fmt.Errorf("converting column '%s': %w", c.col, err)
+		}
+		if field.OverflowFloat(v.Float64) {
+			return fmt.Errorf("converting column '%s': value %g overflows %s", c.col, v.Float64, field.Type())
+		}
+		field.SetFloat(v.Float64)
+	}
+
+	return nil
+}
+
+// timedScanner is a [sql.Scanner] shim that accumulates the time spent by dest.
+type timedScanner struct {
+	dest    sql.Scanner
+	elapsed time.Duration
+	timed   bool
+}
+
+func (t *timedScanner) Scan(src any) error {
+	start := time.Now()
+	err := t.dest.Scan(src)
+	t.elapsed += time.Since(start)
+	t.timed = true
+	return err
+}
+
+// timeFormatScanner is a [sql.Scanner] shim that parses a text column into a
+// time.Time field, or a pointer to it, with the layout of [reflectutil.TimeFormatOption].
+// Columns already scanned as time.Time by the driver are set as is.
+type timeFormatScanner struct {
+	col    string
+	field  reflect.Value
+	layout string
+}
+
+func (f *timeFormatScanner) Scan(src any) error {
+	var text string
+	switch v := src.(type) {
+	case nil:
+		f.field.SetZero()
+		return nil
+	case time.Time:
+		f.set(v)
+		return nil
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		return fmt.Errorf("converting column '%s': timeformat field requires a text column, got %T", f.col, src)
+	}
+
+	t, err := time.Parse(f.layout, text)
+	if err != nil {
+		return fmt.Errorf("converting column '%s': %w", f.col, err)
+	}
+
+	f.set(t)
+	return nil
+}
+
+func (f *timeFormatScanner) set(t time.Time) {
+	if f.field.Kind() == reflect.Pointer {
+		f.field.Set(reflect.ValueOf(&t))
+	} else {
+		f.field.Set(reflect.ValueOf(t))
+	}
+}
+
+// jsonScanner is a [sql.Scanner] shim that decodes a JSON column into the field,
+// see [reflectutil.JSONOption].
+type jsonScanner struct {
+	col      string
+	field    reflect.Value
+	nullMode NullCollectionMode
+}
+
+func (j *jsonScanner) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		setNullCollection(j.field, j.nullMode)
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("converting column '%s': json field requires a text column, got %T", j.col, src)
+	}
+
+	// don't merge into the value of the previous row
+	j.field.SetZero()
+	if err := json.Unmarshal(data, j.field.Addr().Interface()); err != nil {
+		return fmt.Errorf("converting column '%s': %w", j.col, err)
+	}
+
+	// JSON null
+	if j.field.IsZero() {
+		setNullCollection(j.field, j.nullMode)
+	}
+
+	return nil
+}
+
+// setNullCollection sets field to its zero value, or to an empty slice or map
+// if mode is [NullCollectionEmpty].
+func setNullCollection(field reflect.Value, mode NullCollectionMode) {
+	if mode != NullCollectionEmpty {
+		field.SetZero()
+		return
+	}
+
+	switch field.Kind() {
+	case reflect.Slice:
+		field.Set(reflect.MakeSlice(field.Type(), 0, 0))
+	case reflect.Map:
+		field.Set(reflect.MakeMap(field.Type()))
+	default:
+		field.SetZero()
+	}
+}
+
+// Close closes [Scanner], preventing further enumeration, and returning the connection to the pool.
+// Close is idempotent and does not affect the result of [Scanner.Err].
+func (s *Scanner) Close() error {
+	if s.rows == nil {
+		return nil
+	}
+	if err := s.rows.Close(); err != nil {
+		return fmt.Errorf("sqlz/scan: closing rows: %w", err)
+	}
+	return nil
+}
+
+// Drain discards the remaining rows of a manual iteration, without scanning
+// them, and closes the rows, so the connection is released promptly, as some
+// drivers leave the cursor open on a partially read result.
+// It returns the error of the iteration, if any, see [Scanner.Err].
+func (s *Scanner) Drain() error {
+	if s.rows == nil {
+		return s.err
+	}
+
+	for s.NextRow() {
+	}
+
+	err := s.Err()
+	if errClose := s.Close(); errClose != nil && err == nil {
+		err = errClose
+	}
+	return err
+}
+
+// WithContext binds ctx to the manual iteration: once ctx is done, [Scanner.NextRow]
+// returns false, closes the rows and [Scanner.Err] returns the context error.
+// It returns the same [Scanner] for chaining.
+func (s *Scanner) WithContext(ctx context.Context) *Scanner {
+	s.ctx = ctx
+	return s
+}
+
+// NextRow prepares the next result row for reading with [Scanner.ScanRow].
 // It returns true on success, or false if there is no next result row or an error
 // happened while preparing it. [Scanner.Err] should be consulted to distinguish between
 // the two cases.
 //
 // Every call to [Scanner.ScanRow], even the first one, must be preceded by a NextRow.
 func (s *Scanner) NextRow() bool {
-	if s.rows == nil {
+	if s.rows == nil || s.err != nil {
 		return false
 	}
 	s.manualIterating = true
+
+	if s.ctx != nil && s.ctx.Err() != nil {
+		s.err = fmt.Errorf("sqlz/scan: iteration canceled: %w", s.ctx.Err())
+		s.rows.Close()
+		return false
+	}
+
 	return s.rows.Next()
 }
 
+// ErrConnLost wraps [driver.ErrBadConn] when the connection is lost while
+// iterating over rows, so retry logic can branch on it with [errors.Is].
+var ErrConnLost = errors.New("sqlz/scan: connection lost")
+
+// nextRowError wraps err, returned by rows.Err, adding [ErrConnLost] if the
+// connection was lost.
+func nextRowError(err error) error {
+	if errors.Is(err, driver.ErrBadConn) {
+		return fmt.Errorf("%w: preparing next row: %w", ErrConnLost, err)
+	}
+	return fmt.Errorf("sqlz/scan: preparing next row: %w", err)
+}
+
 // Err returns the error, if any, that was encountered while running the query
 // or during iteration.
 // Err may be called after an explicit or implicit [Scanner.Close].
@@ -350,7 +1767,7 @@ func (s *Scanner) Err() error {
 		return s.err
 	}
 	if err := s.rows.Err(); err != nil {
-		return fmt.Errorf("sqlz/scan: preparing next row: %w", err)
+		return nextRowError(err)
 	}
 	return nil
 }
@@ -1,16 +1,25 @@
 package sqlz
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"net"
+	"net/netip"
 	"reflect"
+	"slices"
+	"strconv"
 	"testing"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/rfberaldo/sqlz/internal/parser"
+	"github.com/rfberaldo/sqlz/internal/reflectutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -163,6 +172,85 @@ func TestScanner_Scan(t *testing.T) {
 	})
 }
 
+func TestScanner_Scan_null_time_pointer(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		ts, _ := time.Parse(time.DateTime, "2025-09-29 12:00:00")
+
+		type User struct {
+			Id        int
+			CreatedAt *time.Time
+			DeletedAt *time.Time
+		}
+
+		query := `
+		SELECT
+			1    AS id,
+			TIMESTAMP '2025-09-29 12:00:00' AS created_at,
+			NULL AS deleted_at`
+
+		t.Run("struct", func(t *testing.T) {
+			rows, err := conn.db.Query(query)
+			require.NoError(t, err)
+			var user User
+			err = newRowScanner(rows, nil).Scan(&user)
+			require.NoError(t, err)
+			assert.Equal(t, User{Id: 1, CreatedAt: &ts, DeletedAt: nil}, user)
+		})
+
+		t.Run("struct with previous value", func(t *testing.T) {
+			rows, err := conn.db.Query(query)
+			require.NoError(t, err)
+			user := User{DeletedAt: &ts}
+			err = newRowScanner(rows, nil).Scan(&user)
+			require.NoError(t, err)
+			assert.Nil(t, user.DeletedAt)
+		})
+
+		t.Run("slice", func(t *testing.T) {
+			rows, err := conn.db.Query(`
+			SELECT *
+			FROM (
+				SELECT TIMESTAMP '2025-09-29 12:00:00'
+				UNION ALL
+				SELECT NULL
+			) AS t (foo)`)
+			require.NoError(t, err)
+			var got []*time.Time
+			err = newScanner(rows, nil).Scan(&got)
+			require.NoError(t, err)
+			assert.Equal(t, []*time.Time{&ts, nil}, got)
+		})
+	})
+}
+
+func TestScanner_Scan_pointer_field_by_reference(t *testing.T) {
+	type User struct {
+		Id        int
+		DeletedAt *time.Time
+	}
+
+	// [sql.Rows.Scan] only sets a nil pointer on NULL if it receives **time.Time
+	var destTypes []reflect.Type
+	rows := &mockRows{
+		ColumnsFunc: func() ([]string, error) {
+			return []string{"id", "deleted_at"}, nil
+		},
+		NextFunc: func() bool { return destTypes == nil },
+		ScanFunc: func(dest ...any) error {
+			for _, d := range dest {
+				destTypes = append(destTypes, reflect.TypeOf(d))
+			}
+			return nil
+		},
+	}
+
+	var user User
+	err := newRowScanner(rows, nil).Scan(&user)
+	require.NoError(t, err)
+	assert.Equal(t, reflect.TypeFor[**time.Time](), destTypes[1])
+	assert.Nil(t, user.DeletedAt)
+}
+
 func TestScanner_Scan_slices(t *testing.T) {
 	runConn(t, func(t *testing.T, conn *Conn) {
 		testCases := []struct {
@@ -523,6 +611,40 @@ func TestScanner_Scan_struct_nested(t *testing.T) {
 	})
 }
 
+func TestScanner_Scan_struct_json_alias(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		query := `
+		SELECT
+			1 AS id,
+			('{"name":"Alice","age":42}'::jsonb)->>'name' AS data_name,
+			('{"name":"Alice","age":42}'::jsonb)->>'age' AS "data->>'age'"`
+
+		if conn.bind == parser.BindQuestion {
+			query = `
+			SELECT
+				1 AS id,
+				JSON_UNQUOTE(JSON_EXTRACT('{"name":"Alice","age":42}', '$.name')) AS data_name,
+				JSON_UNQUOTE(JSON_EXTRACT('{"name":"Alice","age":42}', '$.age')) AS ` + "`data->>'age'`"
+		}
+
+		type User struct {
+			Id   int
+			Name string `db:"data_name"`
+			Age  string `db:"data->>'age'"`
+		}
+
+		expect := User{Id: 1, Name: "Alice", Age: "42"}
+
+		rows, err := conn.db.Query(query)
+		require.NoError(t, err)
+		scanner := newRowScanner(rows, nil)
+		var user User
+		err = scanner.Scan(&user)
+		require.NoError(t, err)
+		assert.Equal(t, expect, user)
+	})
+}
+
 func TestScanner_Scan_struct_embed(t *testing.T) {
 	runConn(t, func(t *testing.T, conn *Conn) {
 		query := `
@@ -571,423 +693,3176 @@ func TestScanner_Scan_struct_embed(t *testing.T) {
 	})
 }
 
-func TestScanner_Scan_map(t *testing.T) {
-	runConn(t, func(t *testing.T, conn *Conn) {
-		query := `
-		SELECT
-			99         AS id,
-			'Alice'   AS name,
-			69420.42  AS salary`
+func TestScanner_Scan_struct_embed_precedence(t *testing.T) {
+	type Audit struct {
+		Id        int
+		CreatedBy string
+	}
 
-		expect := map[string]any{
-			"id":     int64(99),
-			"name":   "Alice",
-			"salary": "69420.42",
+	type Owner struct {
+		Id   int
+		Name string
+	}
+
+	newRows := func() *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"id", "created_by"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < 1
+			},
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*int) = 7
+				*dest[1].(*string) = "admin"
+				return nil
+			},
 		}
+	}
 
-		t.Run("allocated map", func(t *testing.T) {
-			rows, err := conn.db.Query(query)
-			require.NoError(t, err)
-			scanner := newRowScanner(rows, nil)
-			user := make(map[string]any)
-			err = scanner.Scan(&user)
-			require.NoError(t, err)
-			assert.EqualValues(t, expect, user)
-		})
+	t.Run("outer field wins", func(t *testing.T) {
+		type User struct {
+			Audit
+			Id int
+		}
 
-		t.Run("non allocated map", func(t *testing.T) {
-			rows, err := conn.db.Query(query)
-			require.NoError(t, err)
-			scanner := newRowScanner(rows, nil)
-			var user map[string]any
-			err = scanner.Scan(&user)
-			require.NoError(t, err)
-			assert.EqualValues(t, expect, user)
-		})
+		var user User
+		err := newRowScanner(newRows(), nil).Scan(&user)
+		require.NoError(t, err)
+		assert.Equal(t, User{Audit: Audit{CreatedBy: "admin"}, Id: 7}, user)
 	})
-}
 
-type mockRows struct {
-	CloseFunc   func() error
-	ColumnsFunc func() ([]string, error)
-	ErrFunc     func() error
-	NextFunc    func() bool
-	ScanFunc    func(dest ...any) error
-}
+	t.Run("ambiguous at equal depth", func(t *testing.T) {
+		type User struct {
+			Audit
+			*Owner
+		}
 
-func (m *mockRows) Close() error {
-	if m.CloseFunc == nil {
-		return nil
-	}
-	return m.CloseFunc()
-}
+		var user User
+		err := newRowScanner(newRows(), nil).Scan(&user)
+		assert.ErrorContains(t, err, "ambiguous column 'id'")
 
-func (m *mockRows) Columns() ([]string, error) {
-	if m.ColumnsFunc == nil {
-		return nil, nil
-	}
-	return m.ColumnsFunc()
+		// not ignored as a missing field
+		err = newRowScanner(newRows(), &config{ignoreMissingFields: true}).Scan(&user)
+		assert.ErrorContains(t, err, "ambiguous column 'id'")
+	})
 }
 
-func (m *mockRows) Err() error {
-	if m.ErrFunc == nil {
-		return nil
+func TestScanner_Scan_struct_embed_nilAllNullStructs(t *testing.T) {
+	type Profession struct {
+		ProfessionId   int
+		ProfessionName *string
 	}
-	return m.ErrFunc()
-}
 
-func (m *mockRows) Next() bool {
-	if m.NextFunc == nil {
-		return false
+	type User struct {
+		Id   int
+		Name string
+		*Profession
 	}
-	return m.NextFunc()
-}
 
-func (m *mockRows) Scan(dest ...any) error {
-	if m.ScanFunc == nil {
-		return nil
+	// set assigns v to dv, allocating pointers as needed, like the drivers do
+	var set func(dv reflect.Value, v any)
+	set = func(dv reflect.Value, v any) {
+		switch {
+		case v == nil:
+			dv.SetZero()
+		case dv.Type() == reflect.TypeOf(v):
+			dv.Set(reflect.ValueOf(v))
+		default:
+			ptr := reflect.New(dv.Type().Elem())
+			set(ptr.Elem(), v)
+			dv.Set(ptr)
+		}
 	}
-	return m.ScanFunc(dest...)
-}
 
-func TestScanner_Scan_validate_dest(t *testing.T) {
-	newRows := func() *mockRows {
-		count := 0
+	newRows := func(data [][]any) *mockRows {
+		i := -1
 		return &mockRows{
 			ColumnsFunc: func() ([]string, error) {
-				return []string{"user"}, nil
+				return []string{"id", "name", "profession_id", "profession_name"}, nil
 			},
 			NextFunc: func() bool {
-				if count > 0 {
-					return false
-				}
-				count++
-				return true
+				i++
+				return i < len(data)
 			},
 			ScanFunc: func(dest ...any) error {
-				for i := range dest {
-					dest[i] = nil
+				for j, v := range data[i] {
+					set(reflect.ValueOf(dest[j]).Elem(), v)
 				}
 				return nil
 			},
 		}
 	}
 
-	errAddressable := "destination must be addressable"
-
-	t.Run("no ref to string", func(t *testing.T) {
-		scanner := newRowScanner(newRows(), nil)
-		var m string
-		err := scanner.Scan(m)
-		require.Error(t, err)
-		assert.ErrorContains(t, err, errAddressable)
-	})
-
-	t.Run("no ref to pointer string", func(t *testing.T) {
-		scanner := newRowScanner(newRows(), nil)
-		var m *string
-		err := scanner.Scan(m)
-		require.Error(t, err)
-		assert.ErrorContains(t, err, errAddressable)
-	})
+	data := [][]any{
+		{1, "Alice", 1, "Dev"},
+		{2, "Rob", nil, nil},
+		{3, "John", 2, nil},
+	}
 
-	t.Run("ref to string", func(t *testing.T) {
-		scanner := newRowScanner(newRows(), nil)
-		var m string
-		err := scanner.Scan(&m)
+	t.Run("all null columns leave pointer nil", func(t *testing.T) {
+		cfg := &config{nilAllNullStructs: true}
+		var users []User
+		err := newScanner(newRows(data), cfg).Scan(&users)
 		require.NoError(t, err)
-	})
 
-	t.Run("ref to pointer string", func(t *testing.T) {
-		scanner := newRowScanner(newRows(), nil)
-		var m *string
-		err := scanner.Scan(&m)
-		require.NoError(t, err)
+		expect := []User{
+			{Id: 1, Name: "Alice", Profession: &Profession{1, ptrTo("Dev")}},
+			{Id: 2, Name: "Rob"},
+			{Id: 3, Name: "John", Profession: &Profession{2, nil}},
+		}
+		assert.Equal(t, expect, users)
 	})
 
-	t.Run("ref to map", func(t *testing.T) {
-		scanner := newRowScanner(newRows(), nil)
-		var m map[string]any
-		err := scanner.Scan(&m)
+	t.Run("disabled allocates struct", func(t *testing.T) {
+		data := [][]any{{2, "Rob", 0, nil}}
+		var users []User
+		err := newScanner(newRows(data), nil).Scan(&users)
 		require.NoError(t, err)
+		assert.Equal(t, []User{{Id: 2, Name: "Rob", Profession: &Profession{}}}, users)
 	})
 
-	t.Run("no ref to map", func(t *testing.T) {
-		scanner := newRowScanner(newRows(), nil)
-		var m map[string]any
-		err := scanner.Scan(m)
+	t.Run("partial null into non-nullable field", func(t *testing.T) {
+		data := [][]any{{3, "John", nil, "Dev"}}
+		cfg := &config{nilAllNullStructs: true}
+		var users []User
+		err := newScanner(newRows(data), cfg).Scan(&users)
 		require.Error(t, err)
-		assert.ErrorContains(t, err, errAddressable)
+		assert.ErrorContains(t, err, "converting NULL to int is unsupported: 'profession_id'")
 	})
+}
 
-	t.Run("no ref to slice", func(t *testing.T) {
-		scanner := newScanner(newRows(), nil)
-		var s []string
-		err := scanner.Scan(s)
-		require.Error(t, err)
-		assert.ErrorContains(t, err, errAddressable)
-	})
+type blob []byte
 
-	t.Run("ref to slice", func(t *testing.T) {
-		scanner := newScanner(newRows(), nil)
-		var s []string
-		err := scanner.Scan(&s)
+func TestScanner_Scan_bytes(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		data := `CAST('go' AS BINARY(2))`
+		if conn.bind == parser.BindDollar {
+			data = `'go'::bytea`
+		}
+		query := `SELECT 1 AS id, ` + data + ` AS data`
+
+		type File struct {
+			Id   int
+			Data blob
+		}
+
+		rows, err := conn.db.Query(query)
 		require.NoError(t, err)
-	})
+		var file File
+		err = newRowScanner(rows, nil).Scan(&file)
+		require.NoError(t, err)
+		assert.Equal(t, File{1, blob("go")}, file)
 
-	t.Run("ref to interface", func(t *testing.T) {
-		scanner := newRowScanner(newRows(), nil)
-		var m any
-		err := scanner.Scan(&m)
+		rows, err = conn.db.Query(query)
 		require.NoError(t, err)
-	})
+		var files map[int]*File
+		err = newScanner(rows, nil).Scan(&files)
+		require.NoError(t, err)
+		assert.Equal(t, blob("go"), files[1].Data)
 
-	t.Run("no ref to pointer struct", func(t *testing.T) {
-		scanner := newRowScanner(newRows(), nil)
-		type User struct{}
-		var user *User
-		err := scanner.Scan(user)
-		require.Error(t, err)
-		assert.ErrorContains(t, err, errAddressable)
-	})
+		rows, err = conn.db.Query(`SELECT ` + data)
+		require.NoError(t, err)
+		var b blob
+		err = newRowScanner(rows, nil).Scan(&b)
+		require.NoError(t, err)
+		assert.Equal(t, blob("go"), b)
 
-	t.Run("ref to pointer struct", func(t *testing.T) {
-		scanner := newRowScanner(newRows(), &config{ignoreMissingFields: true})
-		type User struct{}
-		var user *User
-		err := scanner.Scan(&user)
+		rows, err = conn.db.Query(`SELECT ` + data)
+		require.NoError(t, err)
+		var all [][]byte
+		err = newScanner(rows, nil).Scan(&all)
 		require.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte("go")}, all)
 	})
 }
 
-func TestScanner_resolveDestType(t *testing.T) {
-	t.Run("unsupported destination", func(t *testing.T) {
-		scanner := newScanner(&mockRows{}, nil)
-		err := scanner.resolveDestType(new([1]string))
-		require.Error(t, err)
-		assert.ErrorContains(t, err, "unsupported destination")
-	})
+func TestScanner_Scan_json_raw_message(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		query := `SELECT 1 AS id, CAST('{"a": 1}' AS JSON) AS data`
+		if conn.bind == parser.BindDollar {
+			query = `SELECT 1 AS id, '{"a": 1}'::json AS data`
+		}
 
-	t.Run("must be slice", func(t *testing.T) {
-		scanner := newScanner(&mockRows{}, nil)
-		err := scanner.resolveDestType(new(string))
-		require.Error(t, err)
-		assert.ErrorContains(t, err, "destination must be a slice")
-	})
+		type Doc struct {
+			Id   int
+			Data json.RawMessage
+		}
 
-	t.Run("primitive expects 1 column", func(t *testing.T) {
-		scanner := newScanner(&mockRows{
-			ColumnsFunc: func() ([]string, error) {
-				return []string{"col1", "col2"}, nil
-			},
-		}, nil)
-		scanner.queryRow = true
-		err := scanner.resolveDestType(new(string))
-		require.Error(t, err)
-		assert.ErrorContains(t, err, "query must return 1 column")
+		rows, err := conn.db.Query(query)
+		require.NoError(t, err)
+		var doc Doc
+		err = newRowScanner(rows, nil).Scan(&doc)
+		require.NoError(t, err)
+		assert.Equal(t, 1, doc.Id)
+		assert.JSONEq(t, `{"a": 1}`, string(doc.Data))
+
+		rows, err = conn.db.Query(query)
+		require.NoError(t, err)
+		var docs []Doc
+		err = newScanner(rows, nil).Scan(&docs)
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+		assert.JSONEq(t, `{"a": 1}`, string(docs[0].Data))
 	})
+}
 
-	t.Run("primitive slice expects 1 column", func(t *testing.T) {
-		scanner := newScanner(&mockRows{
+func TestScanner_Scan_json_option(t *testing.T) {
+	type Meta struct {
+		Tags  []string
+		Score int
+	}
+
+	type Doc struct {
+		Id    int
+		Meta  Meta           `db:"meta,json"`
+		Attrs map[string]any `db:"attrs,json"`
+	}
+
+	newRows := func(data [][]any) *mockRows {
+		row := -1
+		return &mockRows{
 			ColumnsFunc: func() ([]string, error) {
-				return []string{"col1", "col2"}, nil
+				return []string{"id", "meta", "attrs"}, nil
 			},
-		}, nil)
-		scanner.queryRow = true
-		err := scanner.resolveDestType(new([]string))
-		require.Error(t, err)
-		assert.ErrorContains(t, err, "query must return 1 column")
+			NextFunc: func() bool {
+				row++
+				return row < len(data)
+			},
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*int) = data[row][0].(int)
+				for i := 1; i < len(dest); i++ {
+					if err := dest[i].(sql.Scanner).Scan(data[row][i]); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}
+	}
+
+	t.Run("decodes string and bytes", func(t *testing.T) {
+		data := [][]any{
+			{1, `{"Tags": ["a", "b"], "Score": 5}`, []byte(`{"x": true}`)},
+			{2, []byte(`{"Score": 1}`), nil},
+		}
+
+		var docs []Doc
+		err := newScanner(newRows(data), nil).Scan(&docs)
+		require.NoError(t, err)
+		expect := []Doc{
+			{1, Meta{[]string{"a", "b"}, 5}, map[string]any{"x": true}},
+			{2, Meta{Score: 1}, nil},
+		}
+		assert.Equal(t, expect, docs)
+	})
+
+	t.Run("does not merge rows", func(t *testing.T) {
+		data := [][]any{
+			{1, `{"Tags": ["a"]}`, `{"x": 1}`},
+			{2, `{"Score": 2}`, `{"y": 2}`},
+		}
+
+		var ids []int
+		var last Doc
+		scanner := newScanner(newRows(data), nil)
+		for scanner.NextRow() {
+			require.NoError(t, scanner.ScanRow(&last))
+			ids = append(ids, last.Id)
+		}
+		require.NoError(t, scanner.Err())
+		assert.Equal(t, []int{1, 2}, ids)
+		assert.Equal(t, Doc{2, Meta{Score: 2}, map[string]any{"y": float64(2)}}, last)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		var docs []Doc
+		err := newScanner(newRows([][]any{{1, `{`, nil}}), nil).Scan(&docs)
+		assert.ErrorContains(t, err, "converting column 'meta'")
+	})
+
+	t.Run("non text column", func(t *testing.T) {
+		var docs []Doc
+		err := newScanner(newRows([][]any{{1, 42, nil}}), nil).Scan(&docs)
+		assert.ErrorContains(t, err, "json field requires a text column, got int")
+	})
+
+	t.Run("pointer field", func(t *testing.T) {
+		type PtrDoc struct {
+			Id    int
+			Meta  *Meta          `db:"meta,json"`
+			Attrs map[string]any `db:"attrs,json"`
+		}
+
+		data := [][]any{
+			{1, `{"Score": 2}`, nil},
+			{2, nil, nil},
+			{3, `null`, nil},
+		}
+
+		var docs []PtrDoc
+		err := newScanner(newRows(data), nil).Scan(&docs)
+		require.NoError(t, err)
+		assert.Equal(t, []PtrDoc{{1, &Meta{Score: 2}, nil}, {2, nil, nil}, {3, nil, nil}}, docs)
+
+		var doc PtrDoc
+		err = newRowScanner(newRows([][]any{{1, `{"Score":`, nil}}), nil).Scan(&doc)
+		assert.ErrorContains(t, err, "converting column 'meta': unexpected end of JSON input")
 	})
 }
 
-func TestScanner_resolveColumns(t *testing.T) {
-	t.Run("columns error", func(t *testing.T) {
-		scanner := newScanner(&mockRows{
+func TestScanner_Scan_timeFormat_mock(t *testing.T) {
+	type Person struct {
+		Id      int
+		Dob     time.Time  `db:"dob,timeformat=2006-01-02"`
+		Updated *time.Time `db:"updated,timeformat=02/01/2006 15:04"`
+	}
+
+	newRows := func(data [][]any) *mockRows {
+		row := -1
+		return &mockRows{
 			ColumnsFunc: func() ([]string, error) {
-				return nil, assert.AnError
+				return []string{"id", "dob", "updated"}, nil
 			},
-		}, nil)
-		err := scanner.resolveColumns()
-		require.Error(t, err)
-		assert.ErrorContains(t, err, "getting column names")
+			NextFunc: func() bool {
+				row++
+				return row < len(data)
+			},
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*int) = data[row][0].(int)
+				for i := 1; i < len(dest); i++ {
+					if err := dest[i].(sql.Scanner).Scan(data[row][i]); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}
+	}
+
+	dob := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+	updated := time.Date(2024, 3, 1, 13, 45, 0, 0, time.UTC)
+
+	t.Run("parses with the field layout", func(t *testing.T) {
+		data := [][]any{
+			{1, "1990-05-17", []byte("01/03/2024 13:45")},
+			{2, dob, nil},
+		}
+
+		var people []Person
+		err := newScanner(newRows(data), nil).Scan(&people)
+		require.NoError(t, err)
+		assert.Equal(t, []Person{{1, dob, &updated}, {2, dob, nil}}, people)
 	})
 
-	t.Run("no rows", func(t *testing.T) {
-		scanner := newScanner(&mockRows{
-			ColumnsFunc: func() ([]string, error) {
-				return []string{}, nil
-			},
-		}, nil)
-		err := scanner.resolveColumns()
-		require.Error(t, err)
-		assert.ErrorContains(t, err, "no columns")
+	t.Run("invalid layout", func(t *testing.T) {
+		var people []Person
+		err := newScanner(newRows([][]any{{1, "17/05/1990", nil}}), nil).Scan(&people)
+		assert.ErrorContains(t, err, "converting column 'dob'")
 	})
 
-	t.Run("duplicate columns", func(t *testing.T) {
-		scanner := newScanner(&mockRows{
-			ColumnsFunc: func() ([]string, error) {
-				return []string{"user", "user"}, nil
-			},
-		}, nil)
-		err := scanner.resolveColumns()
-		require.Error(t, err)
-		assert.ErrorContains(t, err, "duplicate column")
+	t.Run("non text column", func(t *testing.T) {
+		var people []Person
+		err := newScanner(newRows([][]any{{1, int64(1990), nil}}), nil).Scan(&people)
+		assert.ErrorContains(t, err, "timeformat field requires a text column, got int64")
 	})
-}
 
-func setupTestTable(t testing.TB, db *sql.DB) *TableHelper {
-	th := newTableHelper(t, db, parser.BindQuestion)
-	query := th.fmt(`
-		CREATE TABLE IF NOT EXISTS %s (
-			id int auto_increment NOT NULL,
-			name varchar(100) NULL,
-			age int NULL,
-			username varchar(100) NOT NULL,
-			created_at datetime NOT NULL,
-			PRIMARY KEY (id)
-		)`)
+	t.Run("non time field", func(t *testing.T) {
+		type Invalid struct {
+			Id  int
+			Dob string `db:"dob,timeformat=2006-01-02"`
+		}
 
-	_, err := db.Exec(query)
-	require.NoError(t, err)
+		var got []Invalid
+		err := newScanner(newRows([][]any{{1, "1990-05-17", nil}}), nil).Scan(&got)
+		assert.ErrorContains(t, err, "timeformat field must be a time.Time, got string: 'dob'")
+	})
 
-	for range 1000 {
-		_, err = db.Exec(
-			th.fmt("INSERT INTO %s (name, age, username, created_at) VALUES (?,?,?,?)"),
-			"Bob D", 42, "bob", time.Now(),
-		)
+	t.Run("nested struct pointer with nil all null structs", func(t *testing.T) {
+		type Profile struct {
+			Dob time.Time `db:"dob,timeformat=2006-01-02"`
+		}
+
+		type User struct {
+			Id      int
+			Profile *Profile
+		}
+
+		rows := &mockRows{
+			ColumnsFunc: func() ([]string, error) { return []string{"id", "profile_dob"}, nil },
+			NextFunc:    func() func() bool { row := -1; return func() bool { row++; return row < 1 } }(),
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*int) = 1
+				scanner, ok := dest[1].(sql.Scanner)
+				if !ok {
+					return fmt.Errorf("unsupported Scan, storing string into %T", dest[1])
+				}
+				return scanner.Scan("1990-05-17")
+			},
+		}
+
+		var user User
+		err := newRowScanner(rows, &config{nilAllNullStructs: true}).Scan(&user)
 		require.NoError(t, err)
+		assert.Equal(t, User{1, &Profile{dob}}, user)
+	})
+}
+
+func TestScanner_Scan_timeFormat(t *testing.T) {
+	type Person struct {
+		Dob     time.Time `db:"dob,timeformat=2006-01-02"`
+		Updated time.Time `db:"updated,timeformat=2006-01-02 15:04:05"`
 	}
 
-	return th
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, nil)
+
+		var person Person
+		err := db.QueryRow(ctx, `SELECT '1990-05-17' AS dob, '2024-03-01 13:45:00' AS updated`).Scan(&person)
+		require.NoError(t, err)
+		assert.Equal(t, Person{
+			time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 1, 13, 45, 0, 0, time.UTC),
+		}, person)
+	})
 }
 
-// BenchmarkScan_MapSlice-12    	    1256	    962938 ns/op	  537083 B/op	   13784 allocs/op
-func BenchmarkScan_MapSlice(b *testing.B) {
-	conn := mysqlConn
-	require.NoError(b, conn.err)
-	th := setupTestTable(b, conn.db)
+func TestScanner_Scan_sqlNull_mock(t *testing.T) {
+	type Level string
 
-	for b.Loop() {
-		var m []map[string]any
-		rows, err := conn.db.Query(th.fmt("SELECT * FROM %s"))
-		require.NoError(b, err)
-		scanner := newScanner(rows, nil)
-		err = scanner.Scan(&m)
-		require.NoError(b, err)
-		assert.Equal(b, 1000, len(m))
+	type Profile struct {
+		Nick sql.Null[string]
 	}
-}
-
-// BenchmarkScan_StructSlice-12    	    1144	   1023294 ns/op	  265537 B/op	    8709 allocs/op
-func BenchmarkScan_StructSlice(b *testing.B) {
-	conn := mysqlConn
-	require.NoError(b, conn.err)
-	th := setupTestTable(b, conn.db)
 
 	type User struct {
-		Id        int
-		Name      *string
-		Age       *int
-		Username  string
-		CreatedAt time.Time
+		Id      int
+		Level   sql.Null[Level]
+		Score   *sql.Null[int64]
+		Profile Profile
 	}
 
-	for b.Loop() {
-		var users []User
-		rows, err := conn.db.Query(th.fmt("SELECT * FROM %s"))
-		require.NoError(b, err)
-		scanner := newScanner(rows, nil)
-		err = scanner.Scan(&users)
-		require.NoError(b, err)
-		assert.Equal(b, 1000, len(users))
+	newRows := func(data [][]any) *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"id", "level", "score", "profile_nick"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < len(data)
+			},
+			// like database/sql, allocating pointers to scanners
+			ScanFunc: func(dest ...any) error {
+				for i, src := range data[row] {
+					switch d := dest[i].(type) {
+					case *int:
+						*d = src.(int)
+					case sql.Scanner:
+						if err := d.Scan(src); err != nil {
+							return err
+						}
+					default:
+						ptr := reflect.ValueOf(d).Elem()
+						if src == nil {
+							ptr.SetZero()
+							continue
+						}
+						ptr.Set(reflect.New(ptr.Type().Elem()))
+						if err := ptr.Interface().(sql.Scanner).Scan(src); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			},
+		}
 	}
-}
 
-// BenchmarkScan_StructSlice_manual-12    	    1017	   1216979 ns/op	  265769 B/op	    8710 allocs/op
-func BenchmarkScan_StructSlice_manual(b *testing.B) {
-	conn := mysqlConn
-	require.NoError(b, conn.err)
-	th := setupTestTable(b, conn.db)
-
-	type User struct {
-		Id        int
-		Name      *string
-		Age       *int
-		Username  string
-		CreatedAt time.Time
+	data := [][]any{
+		{1, "admin", int64(10), "ali"},
+		{2, nil, nil, nil},
 	}
 
-	for b.Loop() {
-		rows, err := conn.db.Query(th.fmt("SELECT * FROM %s"))
-		require.NoError(b, err)
-		scanner := newScanner(rows, nil)
-
-		var users []User
-		var user User
-		defer scanner.Close()
-		for scanner.NextRow() {
-			err = scanner.ScanRow(&user)
-			require.NoError(b, err)
-			users = append(users, user)
-		}
-		require.NoError(b, scanner.Err())
-		assert.Equal(b, 1000, len(users))
+	var users []User
+	err := newScanner(newRows(data), nil).Scan(&users)
+	require.NoError(t, err)
+	expect := []User{
+		{1, sql.Null[Level]{V: "admin", Valid: true}, &sql.Null[int64]{V: 10, Valid: true}, Profile{sql.Null[string]{V: "ali", Valid: true}}},
+		{2, sql.Null[Level]{}, nil, Profile{}},
 	}
-}
+	assert.Equal(t, expect, users)
 
-// BenchmarkScan_Primitivelice-12    	    3118	    367826 ns/op	   65298 B/op	    2033 allocs/op
-func BenchmarkScan_Primitivelice(b *testing.B) {
-	conn := mysqlConn
-	require.NoError(b, conn.err)
-	th := setupTestTable(b, conn.db)
+	plan, err := newScanner(newRows(data), nil).FieldPlan(&users)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, plan["level"])
+	assert.Equal(t, []int{3, 0}, plan["profile_nick"])
 
-	for b.Loop() {
-		var names []string
-		rows, err := conn.db.Query(th.fmt("SELECT name FROM %s"))
-		require.NoError(b, err)
-		scanner := newScanner(rows, nil)
-		err = scanner.Scan(&names)
-		require.NoError(b, err)
-		assert.Equal(b, 1000, len(names))
+	var levels []sql.Null[Level]
+	rows := newRows([][]any{{"admin"}, {nil}})
+	rows.ColumnsFunc = func() ([]string, error) { return []string{"level"}, nil }
+	err = newScanner(rows, nil).Scan(&levels)
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Null[Level]{{V: "admin", Valid: true}, {}}, levels)
+
+	type NullableUser struct {
+		Id      int
+		Level   sql.Null[Level]
+		Score   *sql.Null[int64]
+		Profile *Profile
 	}
+
+	var nullables []NullableUser
+	err = newScanner(newRows(data), &config{nilAllNullStructs: true}).Scan(&nullables)
+	require.NoError(t, err)
+	require.Len(t, nullables, 2)
+	assert.Equal(t, &Profile{sql.Null[string]{V: "ali", Valid: true}}, nullables[0].Profile)
+	assert.Nil(t, nullables[1].Profile)
 }
 
-// BenchmarkScan_Struct-12    	    9987	    113177 ns/op	    2354 B/op	      58 allocs/op
-func BenchmarkScan_Struct(b *testing.B) {
-	conn := mysqlConn
-	require.NoError(b, conn.err)
-	th := setupTestTable(b, conn.db)
+func TestScanner_Scan_sqlNull(t *testing.T) {
+	type Level string
 
 	type User struct {
-		Id        int
-		Name      *string
-		Age       *int
-		Username  string
-		CreatedAt time.Time
+		Id    int
+		Level sql.Null[Level]
+		Score *sql.Null[int64]
 	}
 
-	for b.Loop() {
-		var user User
-		rows, err := conn.db.Query(th.fmt("SELECT * FROM %s LIMIT 1"))
-		require.NoError(b, err)
-		scanner := newRowScanner(rows, nil)
-		err = scanner.Scan(&user)
-		require.NoError(b, err)
-	}
-}
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, nil)
+		th := newTableHelper(t, conn.db, conn.bind)
 
-// BenchmarkScan_Map-12    	   10000	    111461 ns/op	    1769 B/op	      40 allocs/op
-func BenchmarkScan_Map(b *testing.B) {
-	conn := mysqlConn
-	require.NoError(b, conn.err)
-	th := setupTestTable(b, conn.db)
+		_, err := db.Exec(ctx, th.fmt(`CREATE TABLE %s (id INT PRIMARY KEY, level VARCHAR(20), score BIGINT)`))
+		require.NoError(t, err)
+
+		_, err = db.Exec(ctx, th.fmt(`INSERT INTO %s (id, level, score) VALUES (1, 'admin', 10), (2, NULL, NULL)`))
+		require.NoError(t, err)
+
+		var users []User
+		err = db.Query(ctx, th.fmt(`SELECT id, level, score FROM %s ORDER BY id`)).Scan(&users)
+		require.NoError(t, err)
+		expect := []User{
+			{1, sql.Null[Level]{V: "admin", Valid: true}, &sql.Null[int64]{V: 10, Valid: true}},
+			{2, sql.Null[Level]{}, nil},
+		}
+		assert.Equal(t, expect, users)
+
+		_, err = db.Exec(ctx, th.fmt(`UPDATE %s SET level = :level WHERE id = :id`), users[0])
+		require.NoError(t, err)
+	})
+}
+
+func TestScanner_Scan_strictNumericRange_mock(t *testing.T) {
+	type Stat struct {
+		Count int32
+		Level *uint8
+		Ratio float32
+	}
+
+	newRows := func(data []any) *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"count", "level", "ratio"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < 1
+			},
+			ScanFunc: func(dest ...any) error {
+				for i, v := range data {
+					if err := dest[i].(sql.Scanner).Scan(v); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}
+	}
+
+	cfg := &config{strictNumericRange: true}
+
+	var stat Stat
+	err := newRowScanner(newRows([]any{int64(42), []byte("7"), 1.5}), cfg).Scan(&stat)
+	require.NoError(t, err)
+	assert.Equal(t, int32(42), stat.Count)
+	require.NotNil(t, stat.Level)
+	assert.Equal(t, uint8(7), *stat.Level)
+	assert.Equal(t, float32(1.5), stat.Ratio)
+
+	err = newRowScanner(newRows([]any{int64(42), nil, 1.5}), cfg).Scan(&stat)
+	require.NoError(t, err)
+	assert.Nil(t, stat.Level)
+
+	err = newRowScanner(newRows([]any{int64(9999999999), nil, 1.5}), cfg).Scan(&stat)
+	assert.ErrorContains(t, err, "converting column 'count': value 9999999999 overflows int32")
+
+	err = newRowScanner(newRows([]any{int64(1), int64(256), 1.5}), cfg).Scan(&stat)
+	assert.ErrorContains(t, err, "converting column 'level': value 256 overflows uint8")
+
+	err = newRowScanner(newRows([]any{int64(1), nil, 1e300}), cfg).Scan(&stat)
+	assert.ErrorContains(t, err, "converting column 'ratio': value 1e+300 overflows float32")
+
+	err = newRowScanner(newRows([]any{nil, nil, 1.5}), cfg).Scan(&stat)
+	assert.ErrorContains(t, err, "converting column 'count': NULL to int32 is unsupported")
+
+	err = newRowScanner(newRows([]any{"abc", nil, 1.5}), cfg).Scan(&stat)
+	assert.ErrorContains(t, err, "converting column 'count'")
+
+	t.Run("nested struct pointer with nil all null structs", func(t *testing.T) {
+		type Level struct {
+			Value int8
+		}
+
+		type Player struct {
+			Id    int
+			Level *Level
+		}
+
+		newRows := func(v any) *mockRows {
+			return &mockRows{
+				ColumnsFunc: func() ([]string, error) { return []string{"id", "level_value"}, nil },
+				NextFunc:    func() func() bool { row := -1; return func() bool { row++; return row < 1 } }(),
+				ScanFunc: func(dest ...any) error {
+					*dest[0].(*int) = 1
+					scanner, ok := dest[1].(sql.Scanner)
+					if !ok {
+						return fmt.Errorf("unsupported Scan, storing %T into %T", v, dest[1])
+					}
+					return scanner.Scan(v)
+				},
+			}
+		}
+
+		cfg := &config{strictNumericRange: true, nilAllNullStructs: true}
+		var player Player
+		err := newRowScanner(newRows(int64(7)), cfg).Scan(&player)
+		require.NoError(t, err)
+		assert.Equal(t, Player{1, &Level{7}}, player)
+
+		err = newRowScanner(newRows(int64(300)), cfg).Scan(&player)
+		assert.ErrorContains(t, err, "converting column 'level_value': value 300 overflows int8")
+	})
+}
+
+// priority is a sized number with its own [sql.Scanner], scanned from a name.
+type priority int8
+
+func (p *priority) Scan(src any) error {
+	switch src {
+	case "low":
+		*p = 1
+	case "high":
+		*p = 2
+	default:
+		return fmt.Errorf("invalid priority: %v", src)
+	}
+	return nil
+}
+
+func TestScanner_Scan_strictNumericRange_scanner(t *testing.T) {
+	type Task struct {
+		Id       int32
+		Priority priority
+	}
+
+	rows := &mockRows{
+		ColumnsFunc: func() ([]string, error) { return []string{"id", "priority"}, nil },
+		NextFunc:    func() func() bool { row := -1; return func() bool { row++; return row < 1 } }(),
+		ScanFunc: func(dest ...any) error {
+			for i, v := range []any{int64(1), "high"} {
+				if err := dest[i].(sql.Scanner).Scan(v); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	var task Task
+	err := newRowScanner(rows, &config{strictNumericRange: true}).Scan(&task)
+	require.NoError(t, err)
+	assert.Equal(t, Task{1, 2}, task)
+}
+
+func TestScanner_Scan_strictNumericRange(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, &Options{StrictNumericRange: true})
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := db.Exec(ctx, th.fmt(`CREATE TABLE %s (id BIGINT PRIMARY KEY)`))
+		require.NoError(t, err)
+
+		_, err = db.Exec(ctx, th.fmt(`INSERT INTO %s (id) VALUES (42), (9999999999)`))
+		require.NoError(t, err)
+
+		var small struct{ Id int32 }
+		err = db.QueryRow(ctx, th.fmt(`SELECT id FROM %s WHERE id = 42`)).Scan(&small)
+		require.NoError(t, err)
+		assert.Equal(t, int32(42), small.Id)
+
+		var rows []struct{ Id int32 }
+		err = db.Query(ctx, th.fmt(`SELECT id FROM %s ORDER BY id`)).Scan(&rows)
+		assert.ErrorContains(t, err, "converting column 'id': value 9999999999 overflows int32")
+	})
+}
+
+func TestScanner_Scan_json_slice_of_structs(t *testing.T) {
+	type Item struct {
+		Name      string
+		UnitPrice float64 `json:"unit_price"`
+	}
+
+	type Order struct {
+		Id    int
+		Items []Item `db:"items,json"`
+	}
+
+	newRows := func(data [][]any) *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"id", "items"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < len(data)
+			},
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*int) = data[row][0].(int)
+				return dest[1].(sql.Scanner).Scan(data[row][1])
+			},
+		}
+	}
+
+	data := [][]any{
+		{1, []byte(`[{"name": "apple", "unit_price": 1.5}, {"name": "pear", "unit_price": 2}]`)},
+		{2, nil},
+	}
+
+	var orders []Order
+	err := newScanner(newRows(data), nil).Scan(&orders)
+	require.NoError(t, err)
+	expect := []Order{
+		{1, []Item{{"apple", 1.5}, {"pear", 2}}},
+		{2, nil},
+	}
+	assert.Equal(t, expect, orders)
+
+	orders = nil
+	err = newScanner(newRows(data), &config{nullCollectionMode: NullCollectionEmpty}).Scan(&orders)
+	require.NoError(t, err)
+	assert.Equal(t, []Item{}, orders[1].Items)
+}
+
+func TestScanner_Scan_json_agg(t *testing.T) {
+	type Item struct {
+		Name string
+		Qty  int
+	}
+
+	type Order struct {
+		Id    int
+		Items []Item `db:"items,json"`
+	}
+
+	runConn(t, func(t *testing.T, conn *Conn) {
+		if conn.bind != parser.BindDollar {
+			t.Skip("json_agg is a Postgres function")
+		}
+
+		query := `
+		SELECT o.id, json_agg(json_build_object('name', i.name, 'qty', i.qty) ORDER BY i.name) AS items
+		FROM (VALUES (1), (2)) AS o (id)
+		JOIN (VALUES (1, 'apple', 3), (1, 'pear', 5), (2, 'fig', 1)) AS i (order_id, name, qty)
+			ON i.order_id = o.id
+		GROUP BY o.id
+		ORDER BY o.id`
+
+		rows, err := conn.db.Query(query)
+		require.NoError(t, err)
+		var orders []Order
+		err = newScanner(rows, nil).Scan(&orders)
+		require.NoError(t, err)
+
+		expect := []Order{
+			{1, []Item{{"apple", 3}, {"pear", 5}}},
+			{2, []Item{{"fig", 1}}},
+		}
+		assert.Equal(t, expect, orders)
+	})
+}
+
+func TestScanner_Scan_struct_ordinal_tag(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		query := `
+		SELECT COUNT(*), MAX(id)
+		FROM (SELECT 4 AS id UNION ALL SELECT 8) AS t`
+
+		type Stats struct {
+			Total int `db:"#0"`
+			MaxId int `db:"#1"`
+		}
+
+		rows, err := conn.db.Query(query)
+		require.NoError(t, err)
+		var stats Stats
+		err = newRowScanner(rows, nil).Scan(&stats)
+		require.NoError(t, err)
+		assert.Equal(t, Stats{Total: 2, MaxId: 8}, stats)
+	})
+}
+
+func TestScanner_Scan_struct_ordinal_tag_mock(t *testing.T) {
+	newRows := func() *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"name", "upper(name)"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < 1
+			},
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*string) = "alice"
+				*dest[1].(*string) = "ALICE"
+				return nil
+			},
+		}
+	}
+
+	t.Run("mixed with names", func(t *testing.T) {
+		type User struct {
+			Name  string
+			Upper string `db:"#1"`
+		}
+
+		var users []User
+		err := newScanner(newRows(), nil).Scan(&users)
+		require.NoError(t, err)
+		assert.Equal(t, []User{{"alice", "ALICE"}}, users)
+	})
+
+	t.Run("ordinal takes precedence", func(t *testing.T) {
+		type User struct {
+			Name  string
+			Lower string `db:"#0"`
+			Upper string `db:"#1"`
+			Extra string `db:"#2"` // no column in this position
+		}
+
+		var users []User
+		err := newScanner(newRows(), nil).Scan(&users)
+		require.NoError(t, err)
+		assert.Equal(t, []User{{Lower: "alice", Upper: "ALICE"}}, users)
+	})
+}
+
+func TestScanner_Scan_map(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		query := `
+		SELECT
+			99         AS id,
+			'Alice'   AS name,
+			69420.42  AS salary`
+
+		expect := map[string]any{
+			"id":     int64(99),
+			"name":   "Alice",
+			"salary": "69420.42",
+		}
+
+		t.Run("allocated map", func(t *testing.T) {
+			rows, err := conn.db.Query(query)
+			require.NoError(t, err)
+			scanner := newRowScanner(rows, nil)
+			user := make(map[string]any)
+			err = scanner.Scan(&user)
+			require.NoError(t, err)
+			assert.EqualValues(t, expect, user)
+		})
+
+		t.Run("non allocated map", func(t *testing.T) {
+			rows, err := conn.db.Query(query)
+			require.NoError(t, err)
+			scanner := newRowScanner(rows, nil)
+			var user map[string]any
+			err = scanner.Scan(&user)
+			require.NoError(t, err)
+			assert.EqualValues(t, expect, user)
+		})
+	})
+}
+
+func TestScanner_Scan_map_of_structs(t *testing.T) {
+	type User struct {
+		Name string
+	}
+
+	runConn(t, func(t *testing.T, conn *Conn) {
+		query := `
+			SELECT *
+			FROM (
+				SELECT 1, 'Alice'
+				UNION ALL
+				SELECT 2, 'Bob'
+			) AS t (id, name)`
+
+		rows, err := conn.db.Query(query)
+		require.NoError(t, err)
+		var users map[int]*User
+		err = newScanner(rows, nil).Scan(&users)
+		require.NoError(t, err)
+		assert.Equal(t, map[int]*User{1: {"Alice"}, 2: {"Bob"}}, users)
+	})
+}
+
+func TestScanner_Scan_map_of_values(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		query := `
+			SELECT *
+			FROM (
+				SELECT 1, 'Alice'
+				UNION ALL
+				SELECT 2, NULL
+			) AS t (id, name)`
+
+		rows, err := conn.db.Query(query)
+		require.NoError(t, err)
+		var names map[int]*string
+		err = newScanner(rows, nil).Scan(&names)
+		require.NoError(t, err)
+		alice := "Alice"
+		assert.Equal(t, map[int]*string{1: &alice, 2: nil}, names)
+	})
+}
+
+type mockRows struct {
+	CloseFunc       func() error
+	ColumnsFunc     func() ([]string, error)
+	ColumnTypesFunc func() ([]*sql.ColumnType, error)
+	ErrFunc         func() error
+	NextFunc        func() bool
+	ScanFunc        func(dest ...any) error
+}
+
+func (m *mockRows) Close() error {
+	if m.CloseFunc == nil {
+		return nil
+	}
+	return m.CloseFunc()
+}
+
+func (m *mockRows) Columns() ([]string, error) {
+	if m.ColumnsFunc == nil {
+		return nil, nil
+	}
+	return m.ColumnsFunc()
+}
+
+func (m *mockRows) ColumnTypes() ([]*sql.ColumnType, error) {
+	if m.ColumnTypesFunc == nil {
+		return nil, nil
+	}
+	return m.ColumnTypesFunc()
+}
+
+func (m *mockRows) Err() error {
+	if m.ErrFunc == nil {
+		return nil
+	}
+	return m.ErrFunc()
+}
+
+func (m *mockRows) Next() bool {
+	if m.NextFunc == nil {
+		return false
+	}
+	return m.NextFunc()
+}
+
+func (m *mockRows) Scan(dest ...any) error {
+	if m.ScanFunc == nil {
+		return nil
+	}
+	return m.ScanFunc(dest...)
+}
+
+func TestScanner_Scan_validate_dest(t *testing.T) {
+	newRows := func() *mockRows {
+		count := 0
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"user"}, nil
+			},
+			NextFunc: func() bool {
+				if count > 0 {
+					return false
+				}
+				count++
+				return true
+			},
+			ScanFunc: func(dest ...any) error {
+				for i := range dest {
+					dest[i] = nil
+				}
+				return nil
+			},
+		}
+	}
+
+	errAddressable := "destination must be addressable"
+
+	t.Run("no ref to string", func(t *testing.T) {
+		scanner := newRowScanner(newRows(), nil)
+		var m string
+		err := scanner.Scan(m)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, errAddressable)
+	})
+
+	t.Run("no ref to pointer string", func(t *testing.T) {
+		scanner := newRowScanner(newRows(), nil)
+		var m *string
+		err := scanner.Scan(m)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, errAddressable)
+	})
+
+	t.Run("ref to string", func(t *testing.T) {
+		scanner := newRowScanner(newRows(), nil)
+		var m string
+		err := scanner.Scan(&m)
+		require.NoError(t, err)
+	})
+
+	t.Run("ref to pointer string", func(t *testing.T) {
+		scanner := newRowScanner(newRows(), nil)
+		var m *string
+		err := scanner.Scan(&m)
+		require.NoError(t, err)
+	})
+
+	t.Run("ref to map", func(t *testing.T) {
+		scanner := newRowScanner(newRows(), nil)
+		var m map[string]any
+		err := scanner.Scan(&m)
+		require.NoError(t, err)
+	})
+
+	t.Run("no ref to map", func(t *testing.T) {
+		scanner := newRowScanner(newRows(), nil)
+		var m map[string]any
+		err := scanner.Scan(m)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, errAddressable)
+	})
+
+	t.Run("no ref to slice", func(t *testing.T) {
+		scanner := newScanner(newRows(), nil)
+		var s []string
+		err := scanner.Scan(s)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, errAddressable)
+	})
+
+	t.Run("ref to slice", func(t *testing.T) {
+		scanner := newScanner(newRows(), nil)
+		var s []string
+		err := scanner.Scan(&s)
+		require.NoError(t, err)
+	})
+
+	t.Run("ref to interface", func(t *testing.T) {
+		scanner := newRowScanner(newRows(), nil)
+		var m any
+		err := scanner.Scan(&m)
+		require.NoError(t, err)
+	})
+
+	t.Run("no ref to pointer struct", func(t *testing.T) {
+		scanner := newRowScanner(newRows(), nil)
+		type User struct{}
+		var user *User
+		err := scanner.Scan(user)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, errAddressable)
+	})
+
+	t.Run("ref to pointer struct", func(t *testing.T) {
+		scanner := newRowScanner(newRows(), &config{ignoreMissingFields: true})
+		type User struct{}
+		var user *User
+		err := scanner.Scan(&user)
+		require.NoError(t, err)
+	})
+}
+
+func TestScanner_RawValues(t *testing.T) {
+	data := [][]any{
+		{int64(1), "Alice", int64(10)},
+		{int64(2), "Rob", int64(20)},
+		{int64(3), "John", int64(30)},
+	}
+
+	newRows := func() *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"id", "name", "score"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < len(data)
+			},
+			ScanFunc: func(dest ...any) error {
+				for i := range dest {
+					*dest[i].(*any) = data[row][i]
+				}
+				return nil
+			},
+		}
+	}
+
+	t.Run("fold rows", func(t *testing.T) {
+		scanner := newScanner(newRows(), nil)
+		var names []string
+		var total int64
+		for scanner.NextRow() {
+			values, err := scanner.RawValues()
+			require.NoError(t, err)
+			require.Len(t, values, 3)
+			names = append(names, values[1].(string))
+			total += values[2].(int64)
+		}
+		require.NoError(t, scanner.Err())
+		assert.Equal(t, []string{"Alice", "Rob", "John"}, names)
+		assert.Equal(t, int64(60), total)
+	})
+
+	t.Run("mixed with struct rows", func(t *testing.T) {
+		type User struct {
+			Id    int64
+			Name  string
+			Score int64
+		}
+
+		// scanned once per row
+		rows, row := newRows(), -1
+		rows.ScanFunc = func(dest ...any) error {
+			row++
+			for i := range dest {
+				reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(data[row][i]))
+			}
+			return nil
+		}
+
+		scanner := newScanner(rows, nil)
+		require.True(t, scanner.NextRow())
+		var user User
+		require.NoError(t, scanner.ScanRow(&user))
+		assert.Equal(t, User{1, "Alice", 10}, user)
+
+		require.True(t, scanner.NextRow())
+		values, err := scanner.RawValues()
+		require.NoError(t, err)
+		assert.Equal(t, []any{int64(2), "Rob", int64(20)}, values)
+		assert.Equal(t, User{1, "Alice", 10}, user)
+
+		require.True(t, scanner.NextRow())
+		require.NoError(t, scanner.ScanRow(&user))
+		assert.Equal(t, User{3, "John", 30}, user)
+	})
+
+	t.Run("scan error", func(t *testing.T) {
+		rows := newRows()
+		rows.ScanFunc = func(dest ...any) error { return assert.AnError }
+		scanner := newScanner(rows, nil)
+		require.True(t, scanner.NextRow())
+		_, err := scanner.RawValues()
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("panics without manual iteration", func(t *testing.T) {
+		scanner := newScanner(newRows(), nil)
+		assert.Panics(t, func() { _, _ = scanner.RawValues() })
+	})
+}
+
+func TestScanner_ScanAuto(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := conn.db.Exec(th.fmt(`
+			CREATE TABLE IF NOT EXISTS %s (
+				id BIGINT NOT NULL,
+				name VARCHAR(255) NOT NULL,
+				salary DOUBLE PRECISION NOT NULL,
+				active BOOL NOT NULL,
+				nickname VARCHAR(255) NULL,
+				age BIGINT NULL
+			)`,
+		))
+		require.NoError(t, err)
+
+		_, err = conn.db.Exec(th.fmt(`
+			INSERT INTO %s (id, name, salary, active, nickname, age)
+			VALUES (?,?,?,?,?,?),(?,?,?,?,?,?)`),
+			1, "Alice", 4200.5, true, "ali", 18,
+			2, "Rob", 1337.25, false, nil, nil,
+		)
+		require.NoError(t, err)
+
+		rows, err := conn.db.Query(th.fmt("SELECT * FROM %s ORDER BY id"))
+		require.NoError(t, err)
+		got, err := newScanner(rows, nil).ScanAuto()
+		require.NoError(t, err)
+
+		// MySQL BOOL is an alias for TINYINT(1)
+		var isActive, notActive any = true, false
+		if conn.bind == parser.BindQuestion {
+			isActive, notActive = int8(1), int8(0)
+		}
+
+		expected := []map[string]any{
+			{
+				"id": int64(1), "name": "Alice", "salary": 4200.5, "active": isActive,
+				"nickname": "ali", "age": int64(18),
+			},
+			{
+				"id": int64(2), "name": "Rob", "salary": 1337.25, "active": notActive,
+				"nickname": nil, "age": nil,
+			},
+		}
+		assert.Equal(t, expected, got)
+	})
+}
+
+func TestScanner_ScanAuto_untyped(t *testing.T) {
+	data := [][]any{
+		{int64(1), []byte("Alice"), nil},
+		{int64(2), []byte("Rob"), true},
+	}
+
+	newRows := func() *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"id", "name", "active"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < len(data)
+			},
+			ScanFunc: func(dest ...any) error {
+				for i := range dest {
+					v := data[row][i]
+					*dest[i].(**any) = &v
+					if v == nil {
+						*dest[i].(**any) = nil
+					}
+				}
+				return nil
+			},
+		}
+	}
+
+	t.Run("values as is", func(t *testing.T) {
+		got, err := newScanner(newRows(), nil).ScanAuto()
+		require.NoError(t, err)
+		expected := []map[string]any{
+			{"id": int64(1), "name": "Alice", "active": nil},
+			{"id": int64(2), "name": "Rob", "active": true},
+		}
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("column types error", func(t *testing.T) {
+		rows := newRows()
+		rows.ColumnTypesFunc = func() ([]*sql.ColumnType, error) { return nil, assert.AnError }
+		_, err := newScanner(rows, nil).ScanAuto()
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("closes rows on columns error", func(t *testing.T) {
+		rows := newRows()
+		closed := false
+		rows.CloseFunc = func() error { closed = true; return nil }
+		_, err := newScanner(rows, &config{maxColumns: 2}).ScanAuto()
+		assert.ErrorContains(t, err, "too many columns")
+		assert.True(t, closed)
+	})
+
+	t.Run("queryRow expects one row", func(t *testing.T) {
+		_, err := newRowScanner(newRows(), nil).ScanAuto()
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "expected one row")
+	})
+
+	t.Run("panics with manual iteration", func(t *testing.T) {
+		scanner := newScanner(newRows(), nil)
+		scanner.NextRow()
+		assert.Panics(t, func() { _, _ = scanner.ScanAuto() })
+	})
+}
+
+func TestPairs(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		query := `
+		SELECT 'b' AS k, 2 AS v
+		UNION ALL SELECT 'a', 1
+		UNION ALL SELECT 'b', 3`
+
+		rows, err := conn.db.Query(query)
+		require.NoError(t, err)
+		got, err := Pairs[string, int](newScanner(rows, nil))
+		require.NoError(t, err)
+
+		expect := []Pair[string, int]{{"b", 2}, {"a", 1}, {"b", 3}}
+		assert.Equal(t, expect, got)
+	})
+}
+
+func TestPairs_mock(t *testing.T) {
+	newRows := func(columns []string, data [][]any) *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return columns, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < len(data)
+			},
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*string) = data[row][0].(string)
+				*dest[1].(*int) = data[row][1].(int)
+				return nil
+			},
+		}
+	}
+
+	columns := []string{"k", "v"}
+	data := [][]any{{"b", 2}, {"a", 1}, {"b", 3}}
+
+	t.Run("preserves order and duplicates", func(t *testing.T) {
+		got, err := Pairs[string, int](newScanner(newRows(columns, data), nil))
+		require.NoError(t, err)
+		expect := []Pair[string, int]{{"b", 2}, {"a", 1}, {"b", 3}}
+		assert.Equal(t, expect, got)
+	})
+
+	t.Run("must have 2 columns", func(t *testing.T) {
+		_, err := Pairs[string, int](newScanner(newRows([]string{"k"}, data), nil))
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "must return 2 columns")
+	})
+
+	t.Run("closes rows on columns error", func(t *testing.T) {
+		rows := newRows([]string{"k", "k"}, data)
+		closed := false
+		rows.CloseFunc = func() error { closed = true; return nil }
+		_, err := Pairs[string, int](newScanner(rows, nil))
+		assert.ErrorContains(t, err, "duplicate column name: 'k'")
+		assert.True(t, closed)
+	})
+
+	t.Run("queryRow expects one row", func(t *testing.T) {
+		_, err := Pairs[string, int](newRowScanner(newRows(columns, data), nil))
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "expected one row")
+
+		_, err = Pairs[string, int](newRowScanner(newRows(columns, nil), nil))
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("deferred error", func(t *testing.T) {
+		_, err := Pairs[string, int](&Scanner{err: errors.New("boom")})
+		assert.EqualError(t, err, "boom")
+	})
+}
+
+func TestIter(t *testing.T) {
+	type User struct {
+		Id   int
+		Name string
+	}
+
+	runConn(t, func(t *testing.T, conn *Conn) {
+		query := `
+		SELECT 1 AS id, 'Alice' AS name
+		UNION ALL SELECT 2, 'Bob'`
+
+		rows, err := conn.db.Query(query)
+		require.NoError(t, err)
+
+		var got []User
+		for user, err := range Iter[User](newScanner(rows, nil)) {
+			require.NoError(t, err)
+			got = append(got, user)
+		}
+		assert.Equal(t, []User{{1, "Alice"}, {2, "Bob"}}, got)
+	})
+}
+
+func TestIter_mock(t *testing.T) {
+	type User struct {
+		Id   int
+		Name string
+	}
+
+	newRows := func(data [][]any) (*mockRows, *bool) {
+		row := -1
+		closed := false
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"id", "name"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < len(data)
+			},
+			ScanFunc: func(dest ...any) error {
+				for i, v := range data[row] {
+					reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(v))
+				}
+				return nil
+			},
+			CloseFunc: func() error {
+				closed = true
+				return nil
+			},
+		}, &closed
+	}
+
+	data := [][]any{{1, "Alice"}, {2, "Bob"}, {3, "Carol"}}
+
+	t.Run("structs", func(t *testing.T) {
+		rows, closed := newRows(data)
+		var got []*User
+		for user, err := range Iter[*User](newScanner(rows, nil)) {
+			require.NoError(t, err)
+			got = append(got, user)
+		}
+		assert.Equal(t, []*User{{1, "Alice"}, {2, "Bob"}, {3, "Carol"}}, got)
+		assert.True(t, *closed)
+	})
+
+	t.Run("break closes rows", func(t *testing.T) {
+		rows, closed := newRows(data)
+		var got []User
+		for user, err := range Iter[User](newScanner(rows, nil)) {
+			require.NoError(t, err)
+			got = append(got, user)
+			break
+		}
+		assert.Equal(t, []User{{1, "Alice"}}, got)
+		assert.True(t, *closed)
+	})
+
+	t.Run("rows error on final iteration", func(t *testing.T) {
+		rows, closed := newRows(data[:1])
+		rows.ErrFunc = func() error { return errors.New("connection reset") }
+
+		var errs []error
+		for _, err := range Iter[User](newScanner(rows, nil)) {
+			errs = append(errs, err)
+		}
+		require.Len(t, errs, 2)
+		assert.NoError(t, errs[0])
+		assert.ErrorContains(t, errs[1], "preparing next row: connection reset")
+		assert.True(t, *closed)
+	})
+
+	t.Run("scan error", func(t *testing.T) {
+		rows, closed := newRows(data)
+		var errs []error
+		for _, err := range Iter[string](newScanner(rows, nil)) {
+			errs = append(errs, err)
+		}
+		require.Len(t, errs, 1)
+		assert.ErrorContains(t, errs[0], "query must return 1 column to scan into a primitive type, got 2")
+		assert.True(t, *closed)
+	})
+
+	t.Run("deferred error", func(t *testing.T) {
+		for _, err := range Iter[User](&Scanner{err: errors.New("boom")}) {
+			assert.EqualError(t, err, "boom")
+		}
+	})
+}
+
+func TestScanner_ScanFirst(t *testing.T) {
+	type Line struct {
+		N    int `db:",rownum"`
+		Name string
+		Qty  int
+	}
+
+	data := [][]any{{"header", 0}, {"apple", 3}, {"pear", 5}}
+
+	newRows := func(closed *bool) *mockRows {
+		row := -1
+		return &mockRows{
+			CloseFunc: func() error {
+				*closed = true
+				return nil
+			},
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"name", "qty"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < len(data)
+			},
+			ScanFunc: func(dest ...any) error {
+				switch d := dest[0].(type) {
+				case *string:
+					*d = data[row][0].(string)
+				case *any:
+					*d = data[row][0]
+				}
+				switch d := dest[1].(type) {
+				case *int:
+					*d = data[row][1].(int)
+				case *any:
+					*d = data[row][1]
+				}
+				return nil
+			},
+		}
+	}
+
+	t.Run("automatic rest", func(t *testing.T) {
+		var closed bool
+		var header map[string]any
+		rest, err := newScanner(newRows(&closed), nil).ScanFirst(&header)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "header", "qty": 0}, header)
+		assert.False(t, closed)
+
+		var lines []Line
+		err = rest.Scan(&lines)
+		require.NoError(t, err)
+		assert.Equal(t, []Line{{2, "apple", 3}, {3, "pear", 5}}, lines)
+		assert.True(t, closed)
+	})
+
+	t.Run("manual rest", func(t *testing.T) {
+		var closed bool
+		var header Line
+		rest, err := newScanner(newRows(&closed), nil).ScanFirst(&header)
+		require.NoError(t, err)
+		assert.Equal(t, Line{1, "header", 0}, header)
+
+		var names []string
+		for rest.NextRow() {
+			var line Line
+			require.NoError(t, rest.ScanRow(&line))
+			names = append(names, line.Name)
+		}
+		require.NoError(t, rest.Err())
+		assert.Equal(t, []string{"apple", "pear"}, names)
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		var closed bool
+		rows := newRows(&closed)
+		rows.NextFunc = func() bool { return false }
+		var header Line
+		_, err := newScanner(rows, nil).ScanFirst(&header)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.True(t, closed)
+	})
+
+	t.Run("deferred error", func(t *testing.T) {
+		_, err := (&Scanner{err: assert.AnError}).ScanFirst(new(Line))
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestScanner_ErrConnLost(t *testing.T) {
+	newRows := func(errRows error) *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"id"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < 2
+			},
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*int) = row + 1
+				return nil
+			},
+			ErrFunc: func() error { return errRows },
+		}
+	}
+
+	t.Run("manual iteration", func(t *testing.T) {
+		scanner := newScanner(newRows(driver.ErrBadConn), nil)
+		for scanner.NextRow() {
+			var id int
+			require.NoError(t, scanner.ScanRow(&id))
+		}
+
+		err := scanner.Err()
+		assert.ErrorIs(t, err, ErrConnLost)
+		assert.ErrorIs(t, err, driver.ErrBadConn)
+	})
+
+	t.Run("automatic iteration", func(t *testing.T) {
+		var ids []int
+		err := newScanner(newRows(driver.ErrBadConn), nil).Scan(&ids)
+		assert.ErrorIs(t, err, ErrConnLost)
+		assert.ErrorIs(t, err, driver.ErrBadConn)
+	})
+
+	t.Run("other errors are not wrapped", func(t *testing.T) {
+		scanner := newScanner(newRows(assert.AnError), nil)
+		for scanner.NextRow() {
+		}
+
+		err := scanner.Err()
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.NotErrorIs(t, err, ErrConnLost)
+	})
+}
+
+func TestScanner_WithContext(t *testing.T) {
+	newRows := func(closed *bool) *mockRows {
+		return &mockRows{
+			CloseFunc: func() error {
+				*closed = true
+				return nil
+			},
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"id"}, nil
+			},
+			NextFunc: func() bool { return true },
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*int) = 1
+				return nil
+			},
+		}
+	}
+
+	t.Run("cancel mid loop", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var closed bool
+		scanner := newScanner(newRows(&closed), nil).WithContext(ctx)
+
+		count := 0
+		for scanner.NextRow() {
+			var id int
+			require.NoError(t, scanner.ScanRow(&id))
+			count++
+			if count == 3 {
+				cancel()
+			}
+		}
+
+		assert.Equal(t, 3, count)
+		assert.True(t, closed)
+		assert.ErrorIs(t, scanner.Err(), context.Canceled)
+		assert.False(t, scanner.NextRow())
+	})
+
+	t.Run("already done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 0)
+		defer cancel()
+
+		var closed bool
+		scanner := newScanner(newRows(&closed), nil).WithContext(ctx)
+
+		assert.False(t, scanner.NextRow())
+		assert.True(t, closed)
+		assert.ErrorIs(t, scanner.Err(), context.DeadlineExceeded)
+	})
+}
+
+func TestScanner_Scan_duplicateColumnMode(t *testing.T) {
+	type User struct {
+		Id   int
+		Name string
+	}
+
+	newRows := func(columns []string, data []any) *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) { return columns, nil },
+			NextFunc: func() bool {
+				row++
+				return row < 1
+			},
+			ScanFunc: func(dest ...any) error {
+				for i, v := range data {
+					if v == nil {
+						reflect.ValueOf(dest[i]).Elem().SetZero()
+						continue
+					}
+					reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(v))
+				}
+				return nil
+			},
+		}
+	}
+
+	t.Run("columns mapped to the same field", func(t *testing.T) {
+		columns := []string{"id", "name", "full_name"}
+		data := []any{1, "Alice", "Alice Smith"}
+		columnMap := map[string]string{"full_name": "Name"}
+
+		var user User
+		err := newRowScanner(newRows(columns, data), nil).withColumnMap(columnMap).Scan(&user)
+		require.NoError(t, err)
+		assert.Equal(t, User{1, "Alice Smith"}, user)
+
+		cfg := &config{duplicateColumnMode: DuplicateColumnFirst}
+		err = newRowScanner(newRows(columns, data), cfg).withColumnMap(columnMap).Scan(&user)
+		require.NoError(t, err)
+		assert.Equal(t, User{1, "Alice"}, user)
+
+		cfg = &config{duplicateColumnMode: DuplicateColumnLast}
+		err = newRowScanner(newRows(columns, data), cfg).withColumnMap(columnMap).Scan(&user)
+		require.NoError(t, err)
+		assert.Equal(t, User{1, "Alice Smith"}, user)
+	})
+
+	t.Run("columns with the same name", func(t *testing.T) {
+		columns := []string{"id", "name", "id"}
+		data := []any{1, "Alice", 2}
+
+		var user User
+		err := newRowScanner(newRows(columns, data), nil).Scan(&user)
+		assert.ErrorContains(t, err, "duplicate column name: 'id'")
+
+		cfg := &config{duplicateColumnMode: DuplicateColumnFirst}
+		err = newRowScanner(newRows(columns, data), cfg).Scan(&user)
+		require.NoError(t, err)
+		assert.Equal(t, User{1, "Alice"}, user)
+
+		cfg = &config{duplicateColumnMode: DuplicateColumnLast}
+		err = newRowScanner(newRows(columns, data), cfg).Scan(&user)
+		require.NoError(t, err)
+		assert.Equal(t, User{2, "Alice"}, user)
+
+		var m map[string]any
+		cfg = &config{duplicateColumnMode: DuplicateColumnFirst}
+		err = newRowScanner(newRows(columns, data), cfg).Scan(&m)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"id": 1, "name": "Alice"}, m)
+
+		cfg = &config{duplicateColumnMode: DuplicateColumnLast}
+		err = newRowScanner(newRows(columns, data), cfg).Scan(&m)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"id": 2, "name": "Alice"}, m)
+	})
+
+	t.Run("nullable structs", func(t *testing.T) {
+		type Profile struct{ Nick string }
+		type Member struct {
+			Id      int
+			Profile *Profile
+		}
+
+		columns := []string{"id", "profile_nick", "nick"}
+		data := []any{1, nil, "ali"}
+		columnMap := map[string]string{"nick": "profile_nick"}
+
+		var member Member
+		cfg := &config{duplicateColumnMode: DuplicateColumnFirst, nilAllNullStructs: true}
+		err := newRowScanner(newRows(columns, data), cfg).withColumnMap(columnMap).Scan(&member)
+		require.NoError(t, err)
+		assert.Nil(t, member.Profile)
+	})
+}
+
+func TestScanner_Drain(t *testing.T) {
+	newRows := func(closed *bool, count *int) *mockRows {
+		row := -1
+		return &mockRows{
+			CloseFunc: func() error {
+				*closed = true
+				return nil
+			},
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"id"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				*count = row
+				return row < 5
+			},
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*int) = row + 1
+				return nil
+			},
+		}
+	}
+
+	t.Run("after partial iteration", func(t *testing.T) {
+		var closed bool
+		var next int
+		scanner := newScanner(newRows(&closed, &next), nil)
+
+		require.True(t, scanner.NextRow())
+		var id int
+		require.NoError(t, scanner.ScanRow(&id))
+		assert.Equal(t, 1, id)
+
+		require.NoError(t, scanner.Drain())
+		assert.True(t, closed)
+		assert.Equal(t, 5, next)
+		assert.False(t, scanner.NextRow())
+	})
+
+	t.Run("rows error", func(t *testing.T) {
+		var closed bool
+		var next int
+		rows := newRows(&closed, &next)
+		rows.ErrFunc = func() error { return errors.New("connection reset") }
+
+		err := newScanner(rows, nil).Drain()
+		assert.ErrorContains(t, err, "preparing next row: connection reset")
+		assert.True(t, closed)
+	})
+
+	t.Run("close error", func(t *testing.T) {
+		var closed bool
+		var next int
+		rows := newRows(&closed, &next)
+		rows.CloseFunc = func() error { return errors.New("boom") }
+
+		err := newScanner(rows, nil).Drain()
+		assert.ErrorContains(t, err, "closing rows: boom")
+	})
+
+	t.Run("deferred error", func(t *testing.T) {
+		err := (&Scanner{err: errors.New("boom")}).Drain()
+		assert.EqualError(t, err, "boom")
+	})
+}
+
+func TestScanner_Scan_rownum(t *testing.T) {
+	data := []string{"Alice", "Rob", "John"}
+
+	newRows := func() *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"name"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < len(data)
+			},
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*string) = data[row]
+				return nil
+			},
+		}
+	}
+
+	t.Run("slice of structs", func(t *testing.T) {
+		type User struct {
+			N    int `db:",rownum"`
+			Name string
+		}
+		var users []User
+		err := newScanner(newRows(), nil).Scan(&users)
+		require.NoError(t, err)
+		assert.Equal(t, []User{{1, "Alice"}, {2, "Rob"}, {3, "John"}}, users)
+	})
+
+	t.Run("slice of struct pointers", func(t *testing.T) {
+		type User struct {
+			Name string
+			N    uint64 `db:"ignored,rownum"`
+		}
+		var users []*User
+		err := newScanner(newRows(), nil).Scan(&users)
+		require.NoError(t, err)
+		assert.Equal(t, []*User{{"Alice", 1}, {"Rob", 2}, {"John", 3}}, users)
+	})
+
+	t.Run("manual iteration", func(t *testing.T) {
+		type User struct {
+			N    int `db:",rownum"`
+			Name string
+		}
+		scanner := newScanner(newRows(), nil)
+		var nums []int
+		for scanner.NextRow() {
+			var user User
+			require.NoError(t, scanner.ScanRow(&user))
+			nums = append(nums, user.N)
+		}
+		require.NoError(t, scanner.Err())
+		assert.Equal(t, []int{1, 2, 3}, nums)
+	})
+
+	t.Run("must be an integer", func(t *testing.T) {
+		type User struct {
+			N    string `db:",rownum"`
+			Name string
+		}
+		var users []User
+		err := newScanner(newRows(), nil).Scan(&users)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "rownum field must be an integer")
+	})
+}
+
+func TestScanner_RowsScanned(t *testing.T) {
+	newRows := func(count int) *mockRows {
+		row := 0
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"id"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row <= count
+			},
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*int) = row
+				return nil
+			},
+		}
+	}
+
+	t.Run("slice", func(t *testing.T) {
+		scanner := newScanner(newRows(5), nil)
+		var ids []int
+		err := scanner.Scan(&ids)
+		require.NoError(t, err)
+		assert.Equal(t, len(ids), scanner.RowsScanned())
+		assert.Equal(t, 5, scanner.RowsScanned())
+	})
+
+	t.Run("slice with previous elements", func(t *testing.T) {
+		scanner := newScanner(newRows(2), nil)
+		ids := []int{-1, -2, -3}
+		err := scanner.Scan(&ids)
+		require.NoError(t, err)
+		assert.Equal(t, 2, scanner.RowsScanned())
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		scanner := newScanner(newRows(0), nil)
+		var ids []int
+		err := scanner.Scan(&ids)
+		require.NoError(t, err)
+		assert.Equal(t, 0, scanner.RowsScanned())
+	})
+
+	t.Run("single row", func(t *testing.T) {
+		scanner := newRowScanner(newRows(1), nil)
+		var id int
+		err := scanner.Scan(&id)
+		require.NoError(t, err)
+		assert.Equal(t, 1, scanner.RowsScanned())
+	})
+
+	t.Run("channel is unsupported", func(t *testing.T) {
+		scanner := newScanner(newRows(3), nil)
+		ch := make(chan int, 3)
+		err := scanner.Scan(&ch)
+		var destErr *ErrUnsupportedDest
+		assert.ErrorAs(t, err, &destErr)
+		assert.Equal(t, 0, scanner.RowsScanned())
+	})
+}
+
+func TestScanner_Scan_keepFieldName(t *testing.T) {
+	type User struct {
+		UserName string
+		Email    string `db:"email"`
+	}
+
+	newRows := func() *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"UserName", "email"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < 1
+			},
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*string) = "alice"
+				*dest[1].(*string) = "alice@example.com"
+				return nil
+			},
+		}
+	}
+
+	var users []User
+	err := newScanner(newRows(), &config{fieldNameTransformer: KeepFieldName}).Scan(&users)
+	require.NoError(t, err)
+	assert.Equal(t, []User{{"alice", "alice@example.com"}}, users)
+
+	err = newScanner(newRows(), nil).Scan(&users)
+	assert.ErrorContains(t, err, "struct field not found: 'UserName'")
+}
+
+func TestScanner_Scan_csv(t *testing.T) {
+	type Post struct {
+		Id   int
+		Tags []string `db:"tags,csv"`
+	}
+
+	newRows := func(values ...any) *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"id", "tags"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < len(values)
+			},
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*int) = row + 1
+				return dest[1].(sql.Scanner).Scan(values[row])
+			},
+		}
+	}
+
+	t.Run("split values", func(t *testing.T) {
+		var posts []Post
+		err := newScanner(newRows("a,b,c", []byte("go"), "", nil), nil).Scan(&posts)
+		require.NoError(t, err)
+
+		expect := []Post{
+			{1, []string{"a", "b", "c"}},
+			{2, []string{"go"}},
+			{3, []string{}},
+			{4, nil},
+		}
+		assert.Equal(t, expect, posts)
+	})
+
+	t.Run("custom delimiter", func(t *testing.T) {
+		var posts []Post
+		err := newScanner(newRows("a;b,c"), &config{csvDelimiter: ";"}).Scan(&posts)
+		require.NoError(t, err)
+		assert.Equal(t, []Post{{1, []string{"a", "b,c"}}}, posts)
+	})
+
+	t.Run("non-string column", func(t *testing.T) {
+		var posts []Post
+		err := newScanner(newRows(int64(1)), nil).Scan(&posts)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "csv field requires a string column, got int64")
+	})
+
+	t.Run("field must be a string slice", func(t *testing.T) {
+		type Invalid struct {
+			Id   int
+			Tags []int `db:"tags,csv"`
+		}
+		var rows []Invalid
+		err := newScanner(newRows("1,2"), nil).Scan(&rows)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "csv field must be a string slice, got []int: 'tags'")
+	})
+}
+
+func TestScanner_Scan_fieldConverters(t *testing.T) {
+	intToString := func(v any) (any, error) {
+		switch v := v.(type) {
+		case int64:
+			return strconv.FormatInt(v, 10), nil
+		case []byte:
+			return string(v), nil
+		case nil:
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unexpected type %T", v)
+	}
+
+	cfg := &config{fieldConverters: map[reflect.Type]func(any) (any, error){
+		reflect.TypeFor[string](): intToString,
+	}}
+
+	type User struct {
+		Id   int
+		Code string
+	}
+
+	newRows := func(values ...any) *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"id", "code"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < len(values)
+			},
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*int) = row + 1
+				return dest[1].(sql.Scanner).Scan(values[row])
+			},
+		}
+	}
+
+	t.Run("int to string", func(t *testing.T) {
+		var users []User
+		err := newScanner(newRows(int64(42), []byte("007"), nil), cfg).Scan(&users)
+		require.NoError(t, err)
+		assert.Equal(t, []User{{1, "42"}, {2, "007"}, {3, ""}}, users)
+	})
+
+	t.Run("converter error", func(t *testing.T) {
+		var users []User
+		err := newScanner(newRows(4.2), cfg).Scan(&users)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "converting column 'code': unexpected type float64")
+	})
+
+	t.Run("not assignable", func(t *testing.T) {
+		cfg := &config{fieldConverters: map[reflect.Type]func(any) (any, error){
+			reflect.TypeFor[string](): func(v any) (any, error) { return v, nil },
+		}}
+		var users []User
+		err := newScanner(newRows(int64(42)), cfg).Scan(&users)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "int64 is not assignable to string")
+	})
+}
+
+func TestScanner_Scan_fieldConverters_numeric_column(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		cfg := &config{fieldConverters: map[reflect.Type]func(any) (any, error){
+			reflect.TypeFor[string](): func(v any) (any, error) {
+				return fmt.Sprint(v), nil
+			},
+		}}
+
+		type User struct {
+			Id   int
+			Code string
+		}
+
+		rows, err := conn.db.Query("SELECT 1 AS id, 42 AS code")
+		require.NoError(t, err)
+		var user User
+		err = newRowScanner(rows, cfg).Scan(&user)
+		require.NoError(t, err)
+		assert.Equal(t, User{1, "42"}, user)
+	})
+}
+
+func TestScanner_resolveDestType(t *testing.T) {
+	t.Run("unsupported destination", func(t *testing.T) {
+		scanner := newScanner(&mockRows{}, nil)
+		err := scanner.resolveDestType(new([1]string))
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "unsupported destination")
+	})
+
+	t.Run("unsupported destination type is extractable", func(t *testing.T) {
+		var destErr *ErrUnsupportedDest
+
+		err := newScanner(&mockRows{}, nil).resolveDestType(new([1]string))
+		require.ErrorAs(t, err, &destErr)
+		assert.Equal(t, reflect.TypeFor[*[1]string](), destErr.Type)
+		assert.EqualError(t, err, "sqlz/scan: unsupported destination type: *[1]string")
+
+		err = newScanner(&mockRows{}, nil).resolveDestType(new(chan int))
+		require.ErrorAs(t, err, &destErr)
+		assert.Equal(t, reflect.TypeFor[*chan int](), destErr.Type)
+	})
+
+	t.Run("must be slice", func(t *testing.T) {
+		scanner := newScanner(&mockRows{}, nil)
+		err := scanner.resolveDestType(new(string))
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "destination must be a slice")
+	})
+
+	t.Run("primitive expects 1 column", func(t *testing.T) {
+		scanner := newScanner(&mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"col1", "col2"}, nil
+			},
+		}, nil)
+		scanner.queryRow = true
+		err := scanner.resolveDestType(new(string))
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "query must return 1 column")
+	})
+
+	t.Run("primitive slice expects 1 column", func(t *testing.T) {
+		scanner := newScanner(&mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"col1", "col2"}, nil
+			},
+		}, nil)
+		scanner.queryRow = true
+		err := scanner.resolveDestType(new([]string))
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "query must return 1 column")
+	})
+}
+
+func TestScanner_scanOne_type_not_handled(t *testing.T) {
+	rows := &mockRows{
+		ColumnsFunc: func() ([]string, error) {
+			return []string{"id"}, nil
+		},
+	}
+
+	scanner := newScanner(rows, nil)
+	scanner.destType = reflectutil.Slice // never resolved by TypeOfAny
+
+	var ids []int
+	assert.NotPanics(t, func() {
+		err := scanner.scanOne(&ids)
+		require.Error(t, err)
+		assert.EqualError(t, err, "sqlz/scan: destination type not handled: []int (resolved as Slice)")
+	})
+}
+
+func TestScanner_resolveColumns(t *testing.T) {
+	t.Run("columns error", func(t *testing.T) {
+		scanner := newScanner(&mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return nil, assert.AnError
+			},
+		}, nil)
+		err := scanner.resolveColumns()
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "getting column names")
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		scanner := newScanner(&mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{}, nil
+			},
+		}, nil)
+		err := scanner.resolveColumns()
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "no columns")
+	})
+
+	t.Run("duplicate columns", func(t *testing.T) {
+		scanner := newScanner(&mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"user", "user"}, nil
+			},
+		}, nil)
+		err := scanner.resolveColumns()
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "duplicate column")
+	})
+
+	t.Run("duplicate columns after stripping table prefix", func(t *testing.T) {
+		scanner := newScanner(&mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"user.id", "profile.id"}, nil
+			},
+		}, &config{stripColumnTablePrefix: true})
+		err := scanner.resolveColumns()
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "duplicate column name: 'id'")
+	})
+
+	t.Run("more columns than the limit", func(t *testing.T) {
+		scanner := newScanner(&mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"id", "name", "email"}, nil
+			},
+		}, &config{maxColumns: 2})
+		err := scanner.resolveColumns()
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "too many columns in result set: got 3, limit is 2")
+	})
+
+	t.Run("columns within the limit", func(t *testing.T) {
+		scanner := newScanner(&mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"id", "name"}, nil
+			},
+		}, &config{maxColumns: 2})
+		assert.NoError(t, scanner.resolveColumns())
+	})
+}
+
+func TestScanner_Scan_maxColumns(t *testing.T) {
+	var closed, scanned bool
+	rows := &mockRows{
+		ColumnsFunc: func() ([]string, error) {
+			return []string{"id", "name", "email"}, nil
+		},
+		NextFunc: func() bool { return true },
+		ScanFunc: func(dest ...any) error {
+			scanned = true
+			return nil
+		},
+		CloseFunc: func() error {
+			closed = true
+			return nil
+		},
+	}
+
+	var users []map[string]any
+	err := newScanner(rows, &config{maxColumns: 2}).Scan(&users)
+	assert.ErrorContains(t, err, "too many columns")
+	assert.False(t, scanned, "rows must not be scanned")
+	assert.True(t, closed, "rows must be closed")
+	assert.Empty(t, users)
+}
+
+func TestScanner_Scan_stripColumnTablePrefix(t *testing.T) {
+	type User struct {
+		Id   int
+		Name string
+	}
+
+	newRows := func(columns []string) *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return columns, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < 1
+			},
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*int) = 1
+				*dest[1].(*string) = "Alice"
+				return nil
+			},
+		}
+	}
+
+	cfg := &config{stripColumnTablePrefix: true}
+	columnSets := [][]string{
+		{"id", "name"},
+		{"users.id", "users.name"},
+		{"public.users.id", "name"},
+	}
+
+	for _, columns := range columnSets {
+		var users []User
+		err := newScanner(newRows(columns), cfg).Scan(&users)
+		require.NoError(t, err, columns)
+		assert.Equal(t, []User{{1, "Alice"}}, users, columns)
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		var users []User
+		err := newScanner(newRows([]string{"users.id", "users.name"}), nil).Scan(&users)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "struct field not found: 'users.id'")
+	})
+}
+
+func setupTestTable(t testing.TB, db *sql.DB) *TableHelper {
+	th := newTableHelper(t, db, parser.BindQuestion)
+	query := th.fmt(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id int auto_increment NOT NULL,
+			name varchar(100) NULL,
+			age int NULL,
+			username varchar(100) NOT NULL,
+			created_at datetime NOT NULL,
+			PRIMARY KEY (id)
+		)`)
+
+	_, err := db.Exec(query)
+	require.NoError(t, err)
+
+	for range 1000 {
+		_, err = db.Exec(
+			th.fmt("INSERT INTO %s (name, age, username, created_at) VALUES (?,?,?,?)"),
+			"Bob D", 42, "bob", time.Now(),
+		)
+		require.NoError(t, err)
+	}
+
+	return th
+}
+
+// BenchmarkScan_MapSlice-12    	    1256	    962938 ns/op	  537083 B/op	   13784 allocs/op
+func BenchmarkScan_MapSlice(b *testing.B) {
+	conn := mysqlConn
+	require.NoError(b, conn.err)
+	th := setupTestTable(b, conn.db)
+
+	for b.Loop() {
+		var m []map[string]any
+		rows, err := conn.db.Query(th.fmt("SELECT * FROM %s"))
+		require.NoError(b, err)
+		scanner := newScanner(rows, nil)
+		err = scanner.Scan(&m)
+		require.NoError(b, err)
+		assert.Equal(b, 1000, len(m))
+	}
+}
+
+// BenchmarkScan_StructSlice-12    	    1144	   1023294 ns/op	  265537 B/op	    8709 allocs/op
+func BenchmarkScan_StructSlice(b *testing.B) {
+	conn := mysqlConn
+	require.NoError(b, conn.err)
+	th := setupTestTable(b, conn.db)
+
+	type User struct {
+		Id        int
+		Name      *string
+		Age       *int
+		Username  string
+		CreatedAt time.Time
+	}
+
+	for b.Loop() {
+		var users []User
+		rows, err := conn.db.Query(th.fmt("SELECT * FROM %s"))
+		require.NoError(b, err)
+		scanner := newScanner(rows, nil)
+		err = scanner.Scan(&users)
+		require.NoError(b, err)
+		assert.Equal(b, 1000, len(users))
+	}
+}
+
+// BenchmarkScan_StructSlice_manual-12    	    1017	   1216979 ns/op	  265769 B/op	    8710 allocs/op
+func BenchmarkScan_StructSlice_manual(b *testing.B) {
+	conn := mysqlConn
+	require.NoError(b, conn.err)
+	th := setupTestTable(b, conn.db)
+
+	type User struct {
+		Id        int
+		Name      *string
+		Age       *int
+		Username  string
+		CreatedAt time.Time
+	}
+
+	for b.Loop() {
+		rows, err := conn.db.Query(th.fmt("SELECT * FROM %s"))
+		require.NoError(b, err)
+		scanner := newScanner(rows, nil)
+
+		var users []User
+		var user User
+		defer scanner.Close()
+		for scanner.NextRow() {
+			err = scanner.ScanRow(&user)
+			require.NoError(b, err)
+			users = append(users, user)
+		}
+		require.NoError(b, scanner.Err())
+		assert.Equal(b, 1000, len(users))
+	}
+}
+
+// BenchmarkScan_Primitivelice-12    	    3118	    367826 ns/op	   65298 B/op	    2033 allocs/op
+func BenchmarkScan_Primitivelice(b *testing.B) {
+	conn := mysqlConn
+	require.NoError(b, conn.err)
+	th := setupTestTable(b, conn.db)
+
+	for b.Loop() {
+		var names []string
+		rows, err := conn.db.Query(th.fmt("SELECT name FROM %s"))
+		require.NoError(b, err)
+		scanner := newScanner(rows, nil)
+		err = scanner.Scan(&names)
+		require.NoError(b, err)
+		assert.Equal(b, 1000, len(names))
+	}
+}
+
+// BenchmarkScan_Struct-12    	    9987	    113177 ns/op	    2354 B/op	      58 allocs/op
+func BenchmarkScan_Struct(b *testing.B) {
+	conn := mysqlConn
+	require.NoError(b, conn.err)
+	th := setupTestTable(b, conn.db)
+
+	type User struct {
+		Id        int
+		Name      *string
+		Age       *int
+		Username  string
+		CreatedAt time.Time
+	}
+
+	for b.Loop() {
+		var user User
+		rows, err := conn.db.Query(th.fmt("SELECT * FROM %s LIMIT 1"))
+		require.NoError(b, err)
+		scanner := newRowScanner(rows, nil)
+		err = scanner.Scan(&user)
+		require.NoError(b, err)
+	}
+}
+
+// BenchmarkScan_Map-12    	   10000	    111461 ns/op	    1769 B/op	      40 allocs/op
+func BenchmarkScan_Map(b *testing.B) {
+	conn := mysqlConn
+	require.NoError(b, conn.err)
+	th := setupTestTable(b, conn.db)
+
+	for b.Loop() {
+		m := make(map[string]any)
+		rows, err := conn.db.Query(th.fmt("SELECT * FROM %s LIMIT 1"))
+		require.NoError(b, err)
+		scanner := newRowScanner(rows, nil)
+		err = scanner.Scan(&m)
+		require.NoError(b, err)
+	}
+}
+
+func TestScanner_Scan_columnMap(t *testing.T) {
+	type User struct {
+		Id       int
+		FullName string `db:"name"`
+		Email    string
+	}
+
+	newRows := func() *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"id", "n", "mail"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < 1
+			},
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*int) = 1
+				*dest[1].(*string) = "Alice"
+				*dest[2].(*string) = "alice@example.com"
+				return nil
+			},
+		}
+	}
+
+	t.Run("by key and field name", func(t *testing.T) {
+		var users []User
+		columnMap := map[string]string{"n": "name", "mail": "Email"}
+		err := newScanner(newRows(), nil).withColumnMap(columnMap).Scan(&users)
+		require.NoError(t, err)
+		assert.Equal(t, []User{{1, "Alice", "alice@example.com"}}, users)
+	})
+
+	t.Run("without map", func(t *testing.T) {
+		var users []User
+		err := newScanner(newRows(), nil).Scan(&users)
+		assert.ErrorContains(t, err, "struct field not found: 'n'")
+	})
+}
+
+// slowBlob is a [sql.Scanner] simulating an expensive decoder.
+type slowBlob []byte
+
+func (b *slowBlob) Scan(src any) error {
+	time.Sleep(5 * time.Millisecond)
+	*b = slices.Clone(src.([]byte))
+	return nil
+}
+
+func TestScanner_ColumnTimings(t *testing.T) {
+	type File struct {
+		Id   int
+		Name sql.NullString
+		Data slowBlob
+		Meta map[string]any `db:"meta,json"`
+	}
+
+	newRows := func() *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"id", "name", "data", "meta"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < 2
+			},
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*int) = row + 1
+				values := []any{"a.bin", []byte{0xca, 0xfe}, `{"size": 2}`}
+				for i, v := range values {
+					if err := dest[i+1].(sql.Scanner).Scan(v); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}
+	}
 
-	for b.Loop() {
-		m := make(map[string]any)
-		rows, err := conn.db.Query(th.fmt("SELECT * FROM %s LIMIT 1"))
-		require.NoError(b, err)
-		scanner := newRowScanner(rows, nil)
-		err = scanner.Scan(&m)
-		require.NoError(b, err)
+	t.Run("records scanner columns", func(t *testing.T) {
+		scanner := newScanner(newRows(), &config{collectColumnTimings: true})
+		var files []File
+		err := scanner.Scan(&files)
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+		assert.Equal(t, slowBlob{0xca, 0xfe}, files[1].Data)
+
+		timings := scanner.ColumnTimings()
+		assert.ElementsMatch(t, []string{"name", "data", "meta"}, slices.Collect(maps.Keys(timings)))
+		assert.GreaterOrEqual(t, timings["data"], 10*time.Millisecond, "accumulated across rows")
+		assert.Less(t, timings["name"], timings["data"])
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		scanner := newScanner(newRows(), nil)
+		var files []File
+		err := scanner.Scan(&files)
+		require.NoError(t, err)
+		assert.Nil(t, scanner.ColumnTimings())
+	})
+}
+
+func TestScanner_Scan_nullCollectionMode(t *testing.T) {
+	type Post struct {
+		Id    int
+		Tags  []string       `db:"tags,csv"`
+		Refs  []string       `db:"refs,json"`
+		Attrs map[string]int `db:"attrs,json"`
+	}
+
+	newRows := func(data [][]any) *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"id", "tags", "refs", "attrs"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < len(data)
+			},
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*int) = row + 1
+				for i, v := range data[row] {
+					if err := dest[i+1].(sql.Scanner).Scan(v); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}
+	}
+
+	data := [][]any{
+		{nil, nil, nil},
+		{"", "null", "null"},
+		{"a", `["b"]`, `{"c": 1}`},
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		var posts []Post
+		err := newScanner(newRows(data), nil).Scan(&posts)
+		require.NoError(t, err)
+
+		expect := []Post{
+			{1, nil, nil, nil},
+			{2, []string{}, nil, nil},
+			{3, []string{"a"}, []string{"b"}, map[string]int{"c": 1}},
+		}
+		assert.Equal(t, expect, posts)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var posts []Post
+		err := newScanner(newRows(data), &config{nullCollectionMode: NullCollectionEmpty}).Scan(&posts)
+		require.NoError(t, err)
+
+		expect := []Post{
+			{1, []string{}, []string{}, map[string]int{}},
+			{2, []string{}, []string{}, map[string]int{}},
+			{3, []string{"a"}, []string{"b"}, map[string]int{"c": 1}},
+		}
+		assert.Equal(t, expect, posts)
+		assert.NotNil(t, posts[0].Tags)
+		assert.NotNil(t, posts[0].Refs)
+		assert.NotNil(t, posts[0].Attrs)
+	})
+}
+
+func TestScanner_Scan_ip(t *testing.T) {
+	newRows := func(columns []string, data [][]any) *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return columns, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < len(data)
+			},
+			ScanFunc: func(dest ...any) error {
+				for i, v := range data[row] {
+					if err := dest[i].(sql.Scanner).Scan(v); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		type Host struct {
+			Addr    netip.Addr
+			Network netip.Prefix
+			Legacy  net.IP
+			Gateway *netip.Addr
+		}
+
+		data := [][]any{
+			{"192.168.0.1", []byte("10.0.0.0/8"), "::1", "192.168.0.254"},
+			{nil, nil, nil, nil},
+		}
+		rows := newRows([]string{"addr", "network", "legacy", "gateway"}, data)
+
+		var hosts []Host
+		err := newScanner(rows, nil).Scan(&hosts)
+		require.NoError(t, err)
+
+		gateway := netip.MustParseAddr("192.168.0.254")
+		expect := []Host{
+			{
+				netip.MustParseAddr("192.168.0.1"),
+				netip.MustParsePrefix("10.0.0.0/8"),
+				net.ParseIP("::1"),
+				&gateway,
+			},
+			{},
+		}
+		assert.Equal(t, expect, hosts)
+	})
+
+	t.Run("primitive", func(t *testing.T) {
+		rows := newRows([]string{"addr"}, [][]any{{"2001:db8::1"}})
+
+		var addr netip.Addr
+		err := newRowScanner(rows, nil).Scan(&addr)
+		require.NoError(t, err)
+		assert.Equal(t, netip.MustParseAddr("2001:db8::1"), addr)
+	})
+
+	t.Run("slice", func(t *testing.T) {
+		rows := newRows([]string{"addr"}, [][]any{{"10.0.0.1"}, {"10.0.0.2"}})
+
+		var ips []net.IP
+		err := newScanner(rows, nil).Scan(&ips)
+		require.NoError(t, err)
+		assert.Equal(t, []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2")}, ips)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		rows := newRows([]string{"addr"}, [][]any{{"not an ip"}})
+
+		var addr netip.Addr
+		err := newRowScanner(rows, nil).Scan(&addr)
+		assert.ErrorContains(t, err, "converting column 'addr'")
+
+		rows = newRows([]string{"addr"}, [][]any{{int64(1)}})
+		err = newRowScanner(rows, nil).Scan(&addr)
+		assert.ErrorContains(t, err, "IP field requires a text column, got int64")
+	})
+}
+
+type hookedPerson struct {
+	First    string
+	Last     string
+	FullName string `db:"-"`
+}
+
+func (p *hookedPerson) AfterScan() error {
+	if p.First == "" {
+		return errors.New("first name is required")
+	}
+	p.FullName = p.First + " " + p.Last
+	return nil
+}
+
+func TestScanner_Scan_afterScan(t *testing.T) {
+	newRows := func(data [][2]string) *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"first", "last"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < len(data)
+			},
+			ScanFunc: func(dest ...any) error {
+				*dest[0].(*string) = data[row][0]
+				*dest[1].(*string) = data[row][1]
+				return nil
+			},
+		}
+	}
+
+	t.Run("slice", func(t *testing.T) {
+		rows := newRows([][2]string{{"Alice", "Smith"}, {"Bob", "Jones"}})
+
+		var people []*hookedPerson
+		err := newScanner(rows, nil).Scan(&people)
+		require.NoError(t, err)
+		require.Len(t, people, 2)
+		assert.Equal(t, "Alice Smith", people[0].FullName)
+		assert.Equal(t, "Bob Jones", people[1].FullName)
+	})
+
+	t.Run("single", func(t *testing.T) {
+		rows := newRows([][2]string{{"Alice", "Smith"}})
+
+		var person hookedPerson
+		err := newRowScanner(rows, nil).Scan(&person)
+		require.NoError(t, err)
+		assert.Equal(t, hookedPerson{"Alice", "Smith", "Alice Smith"}, person)
+	})
+
+	t.Run("manual iteration", func(t *testing.T) {
+		rows := newRows([][2]string{{"Alice", "Smith"}})
+
+		s := newScanner(rows, nil)
+		var person hookedPerson
+		for s.NextRow() {
+			require.NoError(t, s.ScanRow(&person))
+		}
+		require.NoError(t, s.Err())
+		assert.Equal(t, "Alice Smith", person.FullName)
+	})
+
+	t.Run("error stops scanning", func(t *testing.T) {
+		rows := newRows([][2]string{{"Alice", "Smith"}, {"", "Doe"}, {"Bob", "Jones"}})
+
+		var people []hookedPerson
+		err := newScanner(rows, nil).Scan(&people)
+		assert.ErrorContains(t, err, "after scan of row 2: first name is required")
+	})
+}
+
+func TestScanner_Scan_map_of_structs_mock(t *testing.T) {
+	type User struct {
+		Name  string
+		Email string
+	}
+
+	newRows := func(columns []string, data [][]any) *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return columns, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < len(data)
+			},
+			ScanFunc: func(dest ...any) error {
+				for i, v := range data[row] {
+					reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(v))
+				}
+				return nil
+			},
+		}
+	}
+
+	data := [][]any{
+		{1, "Alice", "alice@example.com"},
+		{2, "Bob", "bob@example.com"},
+	}
+
+	t.Run("pointer values", func(t *testing.T) {
+		rows := newRows([]string{"id", "name", "email"}, data)
+
+		var users map[int]*User
+		err := newScanner(rows, nil).Scan(&users)
+		require.NoError(t, err)
+
+		expect := map[int]*User{
+			1: {"Alice", "alice@example.com"},
+			2: {"Bob", "bob@example.com"},
+		}
+		assert.Equal(t, expect, users)
+	})
+
+	t.Run("struct values", func(t *testing.T) {
+		rows := newRows([]string{"id", "name", "email"}, data)
+
+		users := map[int]User{3: {"Carol", "carol@example.com"}}
+		err := newScanner(rows, nil).Scan(&users)
+		require.NoError(t, err)
+
+		expect := map[int]User{
+			1: {"Alice", "alice@example.com"},
+			2: {"Bob", "bob@example.com"},
+			3: {"Carol", "carol@example.com"},
+		}
+		assert.Equal(t, expect, users)
+	})
+
+	t.Run("key mapped by struct", func(t *testing.T) {
+		type Account struct {
+			Id   int
+			Name string
+		}
+		rows := newRows([]string{"id", "name"}, [][]any{{1, "Alice"}})
+
+		var accounts map[int]*Account
+		err := newScanner(rows, nil).Scan(&accounts)
+		require.NoError(t, err)
+		assert.Equal(t, map[int]*Account{1: {Name: "Alice"}}, accounts)
+	})
+
+	t.Run("after scan hook", func(t *testing.T) {
+		rows := newRows([]string{"id", "first", "last"}, [][]any{{"a", "Alice", "Smith"}})
+
+		var people map[string]*hookedPerson
+		err := newScanner(rows, nil).Scan(&people)
+		require.NoError(t, err)
+		assert.Equal(t, "Alice Smith", people["a"].FullName)
+	})
+
+	t.Run("single column", func(t *testing.T) {
+		rows := newRows([]string{"id"}, [][]any{{1}})
+
+		var users map[int]*User
+		err := newScanner(rows, nil).Scan(&users)
+		assert.ErrorContains(t, err, "query must return at least 2 columns to scan into a map of structs, got 1")
+	})
+}
+
+func TestScanner_Scan_map_of_values_mock(t *testing.T) {
+	newRows := func(columns []string, data [][]any) *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return columns, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < len(data)
+			},
+			ScanFunc: func(dest ...any) error {
+				for i, v := range data[row] {
+					reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(v))
+				}
+				return nil
+			},
+		}
+	}
+
+	t.Run("int keys", func(t *testing.T) {
+		rows := newRows([]string{"id", "name"}, [][]any{{1, "Alice"}, {2, "Bob"}})
+
+		names := map[int]string{3: "Carol"}
+		err := newScanner(rows, nil).Scan(&names)
+		require.NoError(t, err)
+		assert.Equal(t, map[int]string{1: "Alice", 2: "Bob", 3: "Carol"}, names)
+	})
+
+	t.Run("string keys", func(t *testing.T) {
+		rows := newRows([]string{"code", "total"}, [][]any{{"br", 10.5}, {"pt", 2.0}, {"br", 1.5}})
+
+		var totals map[string]float64
+		err := newScanner(rows, nil).Scan(&totals)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]float64{"br": 1.5, "pt": 2.0}, totals)
+	})
+
+	t.Run("scanner values", func(t *testing.T) {
+		u, err := ParseUUID(testUUID)
+		require.NoError(t, err)
+		rows := newRows([]string{"name", "id"}, [][]any{{"alice", u}})
+
+		var ids map[string]UUID
+		err = newScanner(rows, nil).Scan(&ids)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]UUID{"alice": u}, ids)
+	})
+
+	t.Run("row map is kept", func(t *testing.T) {
+		rows := newRows([]string{"id", "name"}, [][]any{{1, "Alice"}})
+
+		var row map[string]any
+		err := newRowScanner(rows, nil).Scan(&row)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"id": 1, "name": "Alice"}, row)
+	})
+
+	t.Run("wrong column count", func(t *testing.T) {
+		var names map[int]string
+		err := newScanner(newRows([]string{"id"}, [][]any{{1}}), nil).Scan(&names)
+		assert.ErrorContains(t, err, "query must return 2 columns to scan into a map of values, got 1")
+
+		err = newScanner(newRows([]string{"id", "name", "email"}, nil), nil).Scan(&names)
+		assert.ErrorContains(t, err, "query must return 2 columns to scan into a map of values, got 3")
+	})
+}
+
+func TestScanner_ScanColumn(t *testing.T) {
+	data := [][]any{
+		{1, "Alice", "alice@example.com"},
+		{2, "Bob", "bob@example.com"},
+	}
+
+	newRows := func(data [][]any) *mockRows {
+		row := -1
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) {
+				return []string{"id", "name", "email"}, nil
+			},
+			NextFunc: func() bool {
+				row++
+				return row < len(data)
+			},
+			ScanFunc: func(dest ...any) error {
+				for i, v := range data[row] {
+					reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(v))
+				}
+				return nil
+			},
+		}
+	}
+
+	t.Run("slice", func(t *testing.T) {
+		emails := []string{"carol@example.com"}
+		err := newScanner(newRows(data), nil).ScanColumn(&emails, "email")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"carol@example.com", "alice@example.com", "bob@example.com"}, emails)
+	})
+
+	t.Run("query row", func(t *testing.T) {
+		var name string
+		err := newRowScanner(newRows(data[:1]), nil).ScanColumn(&name, "name")
+		require.NoError(t, err)
+		assert.Equal(t, "Alice", name)
+
+		err = newRowScanner(newRows(nil), nil).ScanColumn(&name, "name")
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		err = newRowScanner(newRows(data), nil).ScanColumn(&name, "name")
+		assert.ErrorContains(t, err, "expected one row, got more")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var emails []string
+		err := newScanner(newRows(data), nil).ScanColumn(&emails, "phone")
+		assert.ErrorContains(t, err, "column not found: 'phone'")
+
+		var email string
+		err = newScanner(newRows(data), nil).ScanColumn(&email, "email")
+		assert.ErrorContains(t, err, "destination must be a slice to scan multiple rows")
+
+		err = newScanner(newRows(data), nil).ScanColumn(emails, "email")
+		assert.ErrorContains(t, err, "destination must be a non-nil pointer")
+	})
+}
+
+func TestScanner_FieldPlan(t *testing.T) {
+	type Base struct {
+		Id        int
+		CreatedAt string
+	}
+
+	type Address struct {
+		City    string
+		Country string
+	}
+
+	type User struct {
+		Base
+		Name    string
+		Address *Address
+		Second  string `db:"#4"`
+	}
+
+	newRows := func(columns ...string) *mockRows {
+		return &mockRows{
+			ColumnsFunc: func() ([]string, error) { return columns, nil },
+			NextFunc:    func() bool { panic("rows must not be consumed") },
+		}
 	}
+
+	t.Run("nested and embedded", func(t *testing.T) {
+		rows := newRows("id", "created_at", "name", "address_city", "extra_col")
+		scanner := newScanner(rows, &config{ignoreMissingFields: true})
+
+		plan, err := scanner.FieldPlan(&[]User{})
+		require.NoError(t, err)
+		expect := map[string][]int{
+			"id":           {0, 0},
+			"created_at":   {0, 1},
+			"name":         {1},
+			"address_city": {2, 0},
+			"extra_col":    {3},
+		}
+		assert.Equal(t, expect, plan)
+		assert.Nil(t, scanner.fieldIndexByKey)
+	})
+
+	t.Run("missing field", func(t *testing.T) {
+		_, err := newScanner(newRows("id", "phone"), nil).FieldPlan(&User{})
+		assert.ErrorContains(t, err, "struct field not found: 'phone'")
+
+		plan, err := newScanner(newRows("id", "phone"), &config{ignoreMissingFields: true}).FieldPlan(&User{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]int{"id": {0, 0}}, plan)
+	})
+
+	t.Run("column map", func(t *testing.T) {
+		scanner := newScanner(newRows("n"), nil).withColumnMap(map[string]string{"n": "Name"})
+		plan, err := scanner.FieldPlan(&User{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]int{"n": {1}}, plan)
+	})
+
+	t.Run("columns mapped to the same field", func(t *testing.T) {
+		columnMap := map[string]string{"full_name": "Name"}
+		plan, err := newScanner(newRows("name", "full_name"), nil).withColumnMap(columnMap).FieldPlan(&User{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]int{"full_name": {1}}, plan)
+
+		cfg := &config{duplicateColumnMode: DuplicateColumnFirst}
+		scanner := newScanner(newRows("name", "full_name"), cfg).withColumnMap(columnMap)
+		plan, err = scanner.FieldPlan(&User{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]int{"name": {1}}, plan)
+		assert.Nil(t, scanner.ignoredByCol)
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		_, err := newScanner(newRows("id"), nil).FieldPlan(&[]int{})
+		assert.ErrorContains(t, err, "field plan destination must be a struct, got *[]int")
+
+		_, err = newScanner(newRows("id"), nil).FieldPlan(nil)
+		assert.ErrorContains(t, err, "field plan destination must be a struct, got <nil>")
+	})
+}
+
+func TestScanner_ScanColumn_database(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		rows, err := conn.db.Query(`SELECT 1 AS id, 'alice@example.com' AS email`)
+		require.NoError(t, err)
+		var emails []string
+		err = newScanner(rows, nil).ScanColumn(&emails, "email")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alice@example.com"}, emails)
+	})
 }
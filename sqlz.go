@@ -6,9 +6,14 @@ import (
 	"cmp"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
 
 	"github.com/rfberaldo/sqlz/internal/parser"
+	"github.com/rfberaldo/sqlz/internal/reflectutil"
 )
 
 const (
@@ -16,8 +21,39 @@ const (
 	BindColon    = parser.BindColon    // Syntax: ':param'
 	BindDollar   = parser.BindDollar   // Syntax: '$1'
 	BindQuestion = parser.BindQuestion // Syntax: '?'
+	BindNamedAt  = parser.BindNamedAt  // Syntax: '@name', args are bound as [sql.NamedArg]
 )
 
+// NullCollectionMode defines how NULL columns are scanned into slice and map
+// fields tagged with the "csv" or "json" option, see [Options.NullCollectionMode].
+type NullCollectionMode uint8
+
+const (
+	NullCollectionNil   NullCollectionMode = iota // NULL is scanned as a nil slice or map
+	NullCollectionEmpty                           // NULL is scanned as an empty, non-nil slice or map
+)
+
+// DuplicateColumnMode defines how result columns with the same name, or mapped
+// to the same struct field, are scanned, see [Options.DuplicateColumnMode].
+type DuplicateColumnMode uint8
+
+const (
+	DuplicateColumnError DuplicateColumnMode = iota // columns with the same name return an error
+	DuplicateColumnFirst                            // the first of the duplicate columns is scanned
+	DuplicateColumnLast                             // the last of the duplicate columns is scanned
+)
+
+// Tracer starts a span per database operation, see [Options.Tracer].
+// It's meant to be implemented by a thin adapter over a tracing library,
+// like OpenTelemetry, without sqlz depending on it.
+type Tracer interface {
+	// StartSpan starts a span named name, like "sqlz.Query", with the executed
+	// query as attribute, e.g. OpenTelemetry's "db.query.text". It returns the context
+	// carrying the span, which is passed to the driver, and the func ending it
+	// with the error of the operation, if any.
+	StartSpan(ctx context.Context, name, query string) (context.Context, func(err error))
+}
+
 // Options are optional configs for sqlz.
 type Options struct {
 	// Bind is the placeholder the database driver uses, this should be blank for most users.
@@ -28,8 +64,9 @@ type Options struct {
 	// Default is "db".
 	StructTag string
 
-	// FieldNameTransformer transforms a struct field name when the struct tag is not found.
-	// Default is [ToSnakeCase].
+	// FieldNameTransformer transforms a struct field name when the struct tag is not found,
+	// use [KeepFieldName] to map field names verbatim.
+	// Default, or if nil, is [ToSnakeCase].
 	FieldNameTransformer func(string) string
 
 	// IgnoreMissingFields causes the scanner to ignore missing struct fields
@@ -42,16 +79,306 @@ type Options struct {
 	// Note that each statement may be prepared on each connection in the pool.
 	// Default is 16.
 	StatementCacheCapacity int
+
+	// NormalizeQueries collapses runs of whitespace outside string literals and comments
+	// before the query is parsed and cached, which increases the statement
+	// cache hit rate for queries that only differ in formatting.
+	// Default is false.
+	NormalizeQueries bool
+
+	// NormalizeTimesToUTC converts every [time.Time] argument to UTC before
+	// binding, this prevents accidentally storing local times.
+	// Default is false.
+	NormalizeTimesToUTC bool
+
+	// RequireWhereOnMutations causes UPDATE and DELETE queries without a WHERE
+	// clause to return an error rather than being executed, also after a leading
+	// WITH clause; a WHERE in comments or subqueries doesn't count,
+	// use [AllowNoWhere] to override it for a single query.
+	// Default is false.
+	RequireWhereOnMutations bool
+
+	// FieldConverters registers conversions by struct field type, they receive
+	// the column value from the driver and return a value assignable to the field,
+	// which is useful when the column type doesn't match the field type.
+	// Note that []byte values may be reused by the driver after the call.
+	// Default is nil.
+	FieldConverters map[reflect.Type]func(any) (any, error)
+
+	// NilAllNullStructs leaves nested or embedded struct pointer fields nil
+	// when all of their columns are NULL, e.g. from a LEFT JOIN without a match,
+	// rather than allocating a struct of zero values.
+	// Default is false.
+	NilAllNullStructs bool
+
+	// StripColumnTablePrefix removes a leading table qualifier from column
+	// names before mapping, e.g. "user.id" is mapped as "id", for drivers which
+	// report qualified column names.
+	// Default is false.
+	StripColumnTablePrefix bool
+
+	// CSVDelimiter is the separator used to split string columns into
+	// []string fields tagged with the "csv" option, as in `db:"tags,csv"`.
+	// Default is ",".
+	CSVDelimiter string
+
+	// ReadWriteRouter selects the pool for read queries, e.g. a read replica,
+	// based on the query or context. Returning nil uses the primary pool.
+	// It's used by [DB.Query], [DB.QueryRow], [DB.SelectAppend], [DB.SelectGroup],
+	// [DB.Exists], [DB.GetRow] and [DB.GetByPK]. The primary pool is always used by
+	// [DB.Exec], [DB.ExecInsert], including queries with a RETURNING clause,
+	// [DB.ExecMany], [DB.InsertValues], [DB.Update], [DB.UpdatePartial],
+	// [DB.ReplaceAll], [DB.PrepareNamedStmt], [BatchInserter] and transactions.
+	// Default is nil.
+	ReadWriteRouter func(ctx context.Context, query string) *sql.DB
+
+	// MaxColumns rejects result sets with more columns than the limit before
+	// scanning, which guards against resource exhaustion from untrusted queries,
+	// e.g. "SELECT *" on wide tables. Zero means no limit.
+	// Default is 0.
+	MaxColumns int
+
+	// CollectColumnTimings records the time spent scanning each column, which
+	// helps finding expensive decoders, see [Scanner.ColumnTimings].
+	// Default is false.
+	CollectColumnTimings bool
+
+	// NullCollectionMode defines whether NULL columns, including JSON null,
+	// are scanned as nil or empty into slice and map fields tagged with
+	// the "csv" or "json" option.
+	// Default is [NullCollectionNil].
+	NullCollectionMode NullCollectionMode
+
+	// Tracer starts a span for every query and exec, including the ones of
+	// transactions, named "sqlz.Query", "sqlz.QueryRow" or "sqlz.Exec".
+	// Spans of queries end when the query is executed, not including scanning.
+	// Default is nil.
+	Tracer Tracer
+
+	// TraceCaller sets the "file:line" of the code calling sqlz in the context
+	// passed to [Tracer.StartSpan], retrieved with [CallerFromContext], which
+	// helps finding the code that issued a query. It walks the stack per operation.
+	// Default is false.
+	TraceCaller bool
+
+	// VerifyArgCount checks that the number of placeholders of a compiled named
+	// query matches the number of args before execution, returning an error
+	// rather than a confusing driver error, which helps catching parser edge cases
+	// during development and tests.
+	// Default is false.
+	VerifyArgCount bool
+
+	// ZeroTimeAsNull binds zero [time.Time] values of named queries as NULL,
+	// as they often mean "unset". Pointers are bound as is, including
+	// pointers to zero times, as they were explicitly set.
+	// Default is false.
+	ZeroTimeAsNull bool
+
+	// FastParse parses named queries with a simpler parser, which assumes
+	// there are no literals nor escaped colons and doesn't collapse whitespace,
+	// speeding up hot paths with simple queries, e.g. "SELECT * FROM user WHERE id = :id".
+	// Queries with a quote char or an escaped colon ("::") fall back to the
+	// default parser. Batch inserts always use the default parser.
+	// Default is false.
+	FastParse bool
+
+	// BindMissingAsNull binds the parameters of named queries without a matching
+	// struct field or map key as NULL, rather than returning an error,
+	// which is useful to share queries between sparse structs.
+	// Ambiguous fields still return an error.
+	// Default is false.
+	BindMissingAsNull bool
+
+	// StripTrailingSemicolon removes a single trailing semicolon of queries
+	// before they are parsed and prepared, as some drivers reject it in
+	// prepared statements. Semicolons inside string literals are kept.
+	// Default is false.
+	StripTrailingSemicolon bool
+
+	// AcquireTimeout bounds the wait for a free connection of the pool, apart from
+	// the query execution, which is still only bounded by the caller context.
+	// It relies on [sql.DB.Conn] honoring the context while waiting for a connection,
+	// e.g. when the pool is saturated by [sql.DB.SetMaxOpenConns].
+	// It applies to [DB] methods, but not to transactions; as cached statements can't
+	// run on a specific connection, queries don't use the statement cache when set.
+	// Default is zero, which doesn't bound the wait.
+	AcquireTimeout time.Duration
+
+	// StrictNumericRange checks that values scanned into sized numeric struct fields,
+	// like int32, uint16 or float32, fit in them, returning an error naming the
+	// column and the field type, rather than the driver conversion error.
+	// Default is false.
+	StrictNumericRange bool
+
+	// MaxBatchParams is the maximum number of parameters of a single statement;
+	// named batch inserts exceeding it are split in chunks of rows, executed
+	// sequentially within a transaction, unless already running in one.
+	// Default is zero, which uses the limit of the driver: 32766 for SQLite,
+	// 2100 for SQL Server and 65535 otherwise; a negative value disables it.
+	MaxBatchParams int
+
+	// DuplicateColumnMode defines how duplicate columns are scanned: columns with
+	// the same name, e.g. "id" of joined tables, or distinct columns mapped to the
+	// same struct field, e.g. by [WithColumnMap], the others are ignored.
+	// Default is [DuplicateColumnError], which returns an error for columns with
+	// the same name, while the last of the columns mapped to the same field is scanned.
+	DuplicateColumnMode DuplicateColumnMode
+}
+
+// allowNoWhere is the marker returned by [AllowNoWhere].
+type allowNoWhere struct{}
+
+// AllowNoWhere returns an argument marker that allows a single UPDATE or DELETE
+// query without a WHERE clause when [Options.RequireWhereOnMutations] is set.
+// It may be passed in any position of args and is never sent to the database:
+//
+//	db.Exec(ctx, "DELETE FROM user", sqlz.AllowNoWhere())
+func AllowNoWhere() any { return allowNoWhere{} }
+
+// columnMap is the marker returned by [WithColumnMap].
+type columnMap map[string]string
+
+// WithColumnMap returns an argument marker mapping result column names to struct
+// field keys or names, for scanning the results of a single query whose columns
+// don't match the struct. It takes precedence over the normal mapping, may be
+// passed in any position of args and is never sent to the database:
+//
+//	db.Query(ctx, "SELECT COUNT(*) AS cnt FROM user", sqlz.WithColumnMap(map[string]string{"cnt": "Total"}))
+func WithColumnMap(m map[string]string) any { return columnMap(m) }
+
+// structTagOverride is the marker returned by [WithStructTag].
+type structTagOverride string
+
+// WithStructTag returns an argument marker overriding [Options.StructTag] for
+// a single query, both for binding named args and for scanning, which helps
+// reusing structs tagged for other purposes. It may be passed in any position
+// of args and is never sent to the database:
+//
+//	db.Query(ctx, "SELECT * FROM user WHERE id = :id", user, sqlz.WithStructTag("json"))
+func WithStructTag(tag string) any { return structTagOverride(tag) }
+
+// Compile transforms a named query into a native query for bind, returning
+// its positional args, just like the query methods do before execution.
+// The arg must be a struct, map, or a slice of them for batch inserts.
+func Compile(bind parser.Bind, query string, arg any) (string, []any, error) {
+	return processNamed(query, arg, &config{bind: bind})
+}
+
+// CompileVerbose is like [Compile], but also returns the 0-based index in args
+// of the first placeholder of each named parameter, which helps correlating
+// positional args back to their names. For batch inserts, indexes refer to the first row.
+func CompileVerbose(bind parser.Bind, query string, arg any) (string, []any, map[string]int, error) {
+	n := &namedQuery{config: applyDefaults(&config{bind: bind})}
+	if err := n.process(query, arg); err != nil {
+		return "", nil, nil, err
+	}
+
+	return n.query, n.args, n.indexByIdent, nil
+}
+
+// In expands the placeholders of query for bind matching a slice in args to
+// the length of that slice, inside an "IN (...)" clause, and flattens args accordingly,
+// just like the query methods do with native queries. It's the equivalent of sqlx.In:
+//
+//	query, args, err := sqlz.In(sqlz.BindQuestion, "SELECT * FROM user WHERE id IN (?)", []int{4, 8})
+//	// query: "SELECT * FROM user WHERE id IN (?,?)"
+//	// args: []any{4, 8}
+func In(bind parser.Bind, query string, args ...any) (string, []any, error) {
+	return parser.ParseInClause(bind, query, args)
+}
+
+// Named is like [Compile], but maps struct fields with tag instead of the default "db",
+// an empty tag keeps the default. It's the equivalent of sqlx.Named, with an explicit bind.
+func Named(bind parser.Bind, tag, query string, arg any) (string, []any, error) {
+	return processNamed(query, arg, &config{bind: bind, structTag: tag})
+}
+
+// Interpolate returns query with its placeholders replaced by args rendered as
+// SQL literals, supporting strings, numbers, booleans, NULL, time and bytes.
+// It's useful for logging a copyable query while debugging:
+//
+//	sqlz.Interpolate(sqlz.BindDollar, "SELECT * FROM user WHERE name = $1", []any{"Alice"})
+//	// Output: "SELECT * FROM user WHERE name = 'Alice'"
+//
+// WARNING: the output is for display only and NOT SAFE for execution,
+// always execute the original query with args instead.
+func Interpolate(bind parser.Bind, query string, args []any) (string, error) {
+	return parser.Interpolate(bind, query, args)
+}
+
+// Keyset returns baseQuery paginated by afterCol, and its args, fetching up to
+// limit rows with afterCol greater than afterVal, the last value of the previous page;
+// if afterVal is nil, it fetches the first page:
+//
+//	query, args := sqlz.Keyset("SELECT * FROM user", "id", lastId, 50, sqlz.BindDollar)
+//	// query: "SELECT * FROM user WHERE id > $1 ORDER BY id LIMIT $2"
+//	// args: []any{lastId, 50}
+//
+// If baseQuery already has a WHERE clause its predicate is parenthesized and the
+// condition is appended with AND, before any GROUP BY, HAVING or WINDOW clause;
+// its own args must come first, as placeholders are numbered after them.
+// baseQuery must not have an ORDER BY or LIMIT clause. [BindAt], [BindNamedAt] and [BindColon]
+// use "OFFSET 0 ROWS FETCH NEXT n ROWS ONLY" instead of LIMIT.
+// The column is used as is, it must not come from user input.
+func Keyset(baseQuery string, afterCol string, afterVal any, limit int, bind parser.Bind) (string, []any) {
+	return parser.Keyset(bind, baseQuery, afterCol, afterVal, limit)
+}
+
+// defaultOptions holds the options set by [SetDefaultOptions].
+var defaultOptions atomic.Pointer[Options]
+
+// SetDefaultOptions sets the options used by [New] and [Connect] when no options
+// are provided, it's meant to be called once, during initialization.
+// Passing nil restores the built-in defaults.
+func SetDefaultOptions(opts *Options) {
+	if opts == nil {
+		defaultOptions.Store(nil)
+		return
+	}
+
+	// copy to avoid changes after the call
+	o := *opts
+	defaultOptions.Store(&o)
+}
+
+// RegisterBind sets the placeholder bind of driverName, used by [New] and
+// [Connect] when [Options.Bind] is not set, which is useful for drivers unknown
+// to sqlz, e.g. "clickhouse". It's meant to be called during initialization,
+// before any [New] call; registering the same driver twice overwrites the previous bind.
+//
+//	sqlz.RegisterBind("clickhouse", sqlz.BindQuestion)
+func RegisterBind(driverName string, bind parser.Bind) {
+	if bind < BindAt || bind > BindNamedAt {
+		panic(fmt.Sprintf("sqlz: unknown bind: %d", bind))
+	}
+
+	bindMu.Lock()
+	defer bindMu.Unlock()
+	bindByDriverName[driverName] = bind
+}
+
+// bindOf returns the bind registered for driverName, or [parser.BindUnknown].
+func bindOf(driverName string) parser.Bind {
+	bindMu.RLock()
+	defer bindMu.RUnlock()
+	return bindByDriverName[driverName]
 }
 
 // New returns a [DB] instance using an existing [sql.DB].
-// The opts parameter can be nil for defaults.
+// The opts parameter can be nil for defaults, see [SetDefaultOptions].
 //
 // Example:
 //
 //	pool, err := sql.Open("sqlite3", ":memory:")
 //	db := sqlz.New("sqlite3", pool, nil)
 func New(driverName string, db *sql.DB, opts *Options) *DB {
+	if opts == nil {
+		if d := defaultOptions.Load(); d != nil {
+			o := *d
+			opts = &o
+		}
+	}
+
 	if opts != nil && opts.StatementCacheCapacity == 0 {
 		opts.StatementCacheCapacity = -1
 	}
@@ -60,18 +387,66 @@ func New(driverName string, db *sql.DB, opts *Options) *DB {
 		opts = &Options{}
 	}
 
-	bind := cmp.Or(opts.Bind, bindByDriverName[driverName])
+	bind := cmp.Or(opts.Bind, bindOf(driverName))
 	if bind == parser.BindUnknown {
-		panic(fmt.Sprintf("sqlz: unable to find bind for '%s', set with Options.Bind", driverName))
+		panic(fmt.Sprintf("sqlz: unable to find bind for '%s', set with Options.Bind or RegisterBind", driverName))
 	}
 
-	return &DB{db, newBase(&config{
-		bind:                 bind,
-		structTag:            opts.StructTag,
-		fieldNameTransformer: opts.FieldNameTransformer,
-		ignoreMissingFields:  opts.IgnoreMissingFields,
-		stmtCacheCapacity:    opts.StatementCacheCapacity,
-	})}
+	cfg := opts.toConfig(bind)
+	cfg.maxBatchParams = cmp.Or(cfg.maxBatchParams, defaultMaxBatchParams(driverName, bind))
+	cfg.firstInsertId = firstInsertIdDrivers[driverName]
+
+	return &DB{db, newBase(cfg)}
+}
+
+// toConfig maps opts to the internal config.
+func (opts *Options) toConfig(bind parser.Bind) *config {
+	return &config{
+		bind:                    bind,
+		structTag:               opts.StructTag,
+		fieldNameTransformer:    opts.FieldNameTransformer,
+		ignoreMissingFields:     opts.IgnoreMissingFields,
+		stmtCacheCapacity:       opts.StatementCacheCapacity,
+		normalizeQueries:        opts.NormalizeQueries,
+		normalizeTimesToUTC:     opts.NormalizeTimesToUTC,
+		requireWhereOnMutations: opts.RequireWhereOnMutations,
+		fieldConverters:         opts.FieldConverters,
+		nilAllNullStructs:       opts.NilAllNullStructs,
+		stripColumnTablePrefix:  opts.StripColumnTablePrefix,
+		csvDelimiter:            opts.CSVDelimiter,
+		readWriteRouter:         opts.ReadWriteRouter,
+		maxColumns:              opts.MaxColumns,
+		collectColumnTimings:    opts.CollectColumnTimings,
+		nullCollectionMode:      opts.NullCollectionMode,
+		tracer:                  opts.Tracer,
+		traceCaller:             opts.TraceCaller,
+		verifyArgCount:          opts.VerifyArgCount,
+		zeroTimeAsNull:          opts.ZeroTimeAsNull,
+		fastParse:               opts.FastParse,
+		bindMissingAsNull:       opts.BindMissingAsNull,
+		stripTrailingSemicolon:  opts.StripTrailingSemicolon,
+		acquireTimeout:          opts.AcquireTimeout,
+		strictNumericRange:      opts.StrictNumericRange,
+		maxBatchParams:          opts.MaxBatchParams,
+		duplicateColumnMode:     opts.DuplicateColumnMode,
+	}
+}
+
+// NewScanner returns a [Scanner] over rows, which is useful for scanning from
+// sources other than [sql.Rows], e.g. mocks or custom cursors, with the same
+// features as [DB.Query]. The rows are closed after [Scanner.Scan].
+// The opts parameter can be nil for defaults, see [SetDefaultOptions],
+// only the scanning options apply.
+func NewScanner(rows Rows, opts *Options) *Scanner {
+	if opts == nil {
+		opts = defaultOptions.Load()
+	}
+
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	return newScanner(rows, opts.toConfig(parser.BindUnknown))
 }
 
 // Connect opens a database specified by its database driver name and a
@@ -118,9 +493,27 @@ type DB struct {
 // Pool return the underlying [sql.DB].
 func (db *DB) Pool() *sql.DB { return db.pool }
 
-// ClearStmtCache clears the prepared statement cache.
+// readPool returns the pool for a read query, see [Options.ReadWriteRouter].
+func (db *DB) readPool(ctx context.Context, query string) querier {
+	if db.base.readWriteRouter == nil {
+		return db.acquiring(db.pool)
+	}
+
+	if pool := db.base.readWriteRouter(ctx, query); pool != nil {
+		return db.acquiring(pool)
+	}
+
+	return db.acquiring(db.pool)
+}
+
+// writePool returns the pool for a write query.
+func (db *DB) writePool() querier { return db.acquiring(db.pool) }
+
+// ClearStmtCache clears the prepared statement cache, closing its statements.
 // This is useful when the database schema has changed and cached statements
-// may no longer be valid.
+// may no longer be valid, or to bound memory of long-running processes.
+// It's the only cache held by db, named queries and struct mappings are
+// resolved on every call. Subsequent queries fill the cache again.
 func (db *DB) ClearStmtCache() {
 	db.base.clearStmtCache()
 }
@@ -155,6 +548,67 @@ func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
 	return &Tx{tx, newBase(db.base.config)}, nil
 }
 
+// InTx is like [DB.WithTx] with default options, but returns the result of fn,
+// or the zero value of T on error. It avoids capturing variables in closures:
+//
+//	id, err := sqlz.InTx(ctx, db, func(tx *sqlz.Tx) (int64, error) {
+//		re, err := tx.Exec(ctx, "INSERT INTO user (name) VALUES (?)", "Alice")
+//		if err != nil {
+//			return 0, err
+//		}
+//		return re.LastInsertId()
+//	})
+func InTx[T any](ctx context.Context, db *DB, fn func(tx *Tx) (T, error)) (T, error) {
+	var result T
+	err := db.WithTx(ctx, nil, func(tx *Tx) (err error) {
+		result, err = fn(tx)
+		return err
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return result, nil
+}
+
+// WithTx runs fn inside a transaction started with [DB.BeginTx], committing if fn
+// succeeds, or rolling back if it returns an error or panics, in which case the
+// panic is propagated after rolling back. If the rollback fails as well, its
+// error is joined to the one of fn:
+//
+//	err := db.WithTx(ctx, nil, func(tx *sqlz.Tx) error {
+//		if _, err := tx.Exec(ctx, "DELETE FROM user_permission WHERE user_id = :id", user); err != nil {
+//			return err
+//		}
+//		_, err := tx.Exec(ctx, "DELETE FROM user WHERE id = :id", user)
+//		return err
+//	})
+func (db *DB) WithTx(ctx context.Context, opts *sql.TxOptions, fn func(tx *Tx) error) (err error) {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			// already rolled back, e.g. by a canceled context
+			if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+				err = errors.Join(err, fmt.Errorf("sqlz: rolling back: %w", rbErr))
+			}
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 // Query executes a query that can return multiple rows. Any errors are deferred
 // until [Scanner.Err] or [Scanner.Scan] is called.
 //
@@ -164,7 +618,7 @@ func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
 // Named queries works for all drivers, allowing the use of struct field names or
 // map keys as placeholders (e.g. :id, :name), rather than having to refer to parameters positionally.
 func (db *DB) Query(ctx context.Context, query string, args ...any) *Scanner {
-	return db.base.query(ctx, db.pool, query, args...)
+	return db.base.query(ctx, db.readPool(ctx, query), query, args...)
 }
 
 // QueryRow executes a query that is expected to return at most one row.
@@ -177,7 +631,50 @@ func (db *DB) Query(ctx context.Context, query string, args ...any) *Scanner {
 // Named queries works for all drivers, allowing the use of struct field names or
 // map keys as placeholders (e.g. :id, :name), rather than having to refer to parameters positionally.
 func (db *DB) QueryRow(ctx context.Context, query string, args ...any) *Scanner {
-	return db.base.queryRow(ctx, db.pool, query, args...)
+	return db.base.queryRow(ctx, db.readPool(ctx, query), query, args...)
+}
+
+// SelectAppend executes a query that can return multiple rows, and appends them
+// to dest, which must be a pointer to a slice. Existing elements are kept,
+// which is useful to gather pages into a single slice across calls.
+//
+// The args are for any placeholder parameters in the query,
+// the default placeholder depends on the driver.
+func (db *DB) SelectAppend(ctx context.Context, dest any, query string, args ...any) error {
+	return db.base.selectAppend(ctx, db.readPool(ctx, query), dest, query, args...)
+}
+
+// SelectGroup executes a query that can return multiple rows, and groups them
+// by the value of keyCol, each row is scanned as a map, keeping the query order
+// within each group. It's useful for one-to-many fetches from a single joined query.
+//
+// The args are for any placeholder parameters in the query,
+// the default placeholder depends on the driver.
+func (db *DB) SelectGroup(ctx context.Context, query, keyCol string, args ...any) (map[any][]map[string]any, error) {
+	return db.base.selectGroup(ctx, db.readPool(ctx, query), query, keyCol, args...)
+}
+
+// Exists executes a query that checks the existence of rows, like "SELECT EXISTS(...)",
+// and returns its result, normalizing driver differences, e.g. Postgres returns
+// a bool and MySQL returns 0 or 1. A query selecting no rows returns false.
+//
+// The args are for any placeholder parameters in the query,
+// the default placeholder depends on the driver.
+func (db *DB) Exists(ctx context.Context, query string, args ...any) (bool, error) {
+	return db.base.exists(ctx, db.readPool(ctx, query), query, args...)
+}
+
+// GetRow executes a query that is expected to return one row, and scans its
+// columns into dests positionally, like [sql.Row.Scan], which is handy to read
+// a few scalars at once. If the query selects no rows, it returns [sql.ErrNoRows].
+//
+//	var count int
+//	var total float64
+//	err := db.GetRow(ctx, "SELECT COUNT(*), SUM(total) FROM orders WHERE id IN (?)", []any{ids}, &count, &total)
+//
+// The args are for any placeholder parameters in the query, including named ones.
+func (db *DB) GetRow(ctx context.Context, query string, args []any, dests ...any) error {
+	return db.base.getRow(ctx, db.readPool(ctx, query), query, args, dests)
 }
 
 // Exec executes a query without returning any rows.
@@ -188,7 +685,96 @@ func (db *DB) QueryRow(ctx context.Context, query string, args ...any) *Scanner
 // Named queries works for all drivers, allowing the use of struct field names or
 // map keys as placeholders (e.g. :id, :name), rather than having to refer to parameters positionally.
 func (db *DB) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
-	return db.base.exec(ctx, db.pool, query, args...)
+	return db.base.exec(ctx, db.writePool(), query, args...)
+}
+
+// PrepareNamedStmt compiles the named query to the native bind and prepares it,
+// returning the statement and the name of each placeholder, in order, so args
+// can be bound by name without sqlz, e.g. to control the statement lifetime:
+//
+//	stmt, idents, err := db.PrepareNamedStmt(ctx, "INSERT INTO user (id, name) VALUES (:id, :name)")
+//	// idents: []string{"id", "name"}
+//	_, err = stmt.ExecContext(ctx, 1, "Alice")
+//
+// A name is repeated if it's used more than once. Slices are not expanded
+// into "IN" clauses, as the number of placeholders is fixed.
+// The caller must close the statement when it's no longer needed.
+func (db *DB) PrepareNamedStmt(ctx context.Context, query string) (*sql.Stmt, []string, error) {
+	return db.base.prepareNamed(ctx, db.pool, query)
+}
+
+// BindNamed compiles the named query with arg, a struct, a map or a slice of
+// them, just like [DB.Exec] would, without executing it, returning the query
+// in the native bind and its args, e.g. for logging or asserting the expansion
+// of "IN" clauses and batch inserts in tests:
+//
+//	query, args, err := db.BindNamed("SELECT * FROM user WHERE id IN (:ids)", map[string]any{"ids": []int{1, 2}})
+//	// query: "SELECT * FROM user WHERE id IN (?,?)", args: []any{1, 2}
+func (db *DB) BindNamed(query string, arg any) (string, []any, error) {
+	if !reflectutil.TypeOfAny(arg).IsNamed() {
+		return "", nil, fmt.Errorf("sqlz: named arg must be a struct, a map or a slice of them, got %T", arg)
+	}
+	return db.base.resolveQuery(query, []any{arg})
+}
+
+// StructRows converts rows, a slice of structs or pointers to structs, into a
+// 2D slice of values in the order of columns, e.g. to feed COPY or custom bulk
+// loaders. Columns are mapped to fields like scanning does, using the struct
+// tag and field name transformer of db, with nested fields joined by "_":
+//
+//	values, err := db.StructRows(users, []string{"id", "name", "address_city"})
+//	// values: [][]any{{1, "Alice", "Wonderland"}, {2, "Rob", nil}}
+//
+// Nil pointers result in nil values, [database/sql/driver.Valuer] fields are kept as is.
+func (db *DB) StructRows(rows any, columns []string) ([][]any, error) {
+	return reflectutil.StructRows(rows, columns, db.base.structTag, "_", db.base.fieldNameTransformer)
+}
+
+// ExecInsert executes an insert query and appends the ids generated for the
+// inserted rows to dest, including batch inserts:
+//
+//	var ids []int64
+//	err := db.ExecInsert(ctx, &ids, "INSERT INTO user (name) VALUES (:name) RETURNING id", users)
+//
+// With [BindDollar], e.g. PostgreSQL, the query must have a RETURNING clause,
+// whose rows are scanned into dest, which can be any slice.
+// With MySQL, the RETURNING clause is removed, if any, and the ids are computed
+// from [sql.Result.LastInsertId], which is the id of the first inserted row,
+// assuming consecutive ids within a statement, so they're computed per chunk of
+// a batch insert split by [Options.MaxBatchParams]; dest must be a *[]int64.
+// Other drivers, like SQLite, report the id of the last inserted row, so a
+// RETURNING clause is scanned if there's one, otherwise [sql.Result.LastInsertId]
+// is only used for single row inserts.
+// It returns an error if the driver cannot report the ids, rather than a wrong or zero id.
+func (db *DB) ExecInsert(ctx context.Context, dest any, query string, args ...any) error {
+	return db.base.execInsert(ctx, db.writePool(), dest, query, args...)
+}
+
+// ExecMany executes query once per arg set of argsList, returning the result
+// of each execution, which is useful for statements that can't be batched into
+// one, like UPDATEs of different rows. An arg set of type []any is used as the
+// args list of a native query, any other is a single arg, like a struct or map
+// of a named query:
+//
+//	results, err := db.ExecMany(ctx, "UPDATE user SET name = :name WHERE id = :id", []any{alice, bob})
+//
+// It stops at the first error, returning the results of the previous executions.
+// Executions are independent, use [Tx.ExecMany] to run them atomically.
+func (db *DB) ExecMany(ctx context.Context, query string, argsList []any) ([]sql.Result, error) {
+	return db.base.execMany(ctx, db.writePool(), query, argsList)
+}
+
+// InsertValues executes a native INSERT query whose VALUES row has a single
+// placeholder, repeating the row once per element of values, a slice, which is
+// useful to insert single column rows without wrapping them in structs or maps:
+//
+//	// INSERT INTO tag (name) VALUES (?),(?),(?)
+//	result, err := db.InsertValues(ctx, "INSERT INTO tag (name) VALUES (?)", []string{"a", "b", "c"})
+//
+// Unlike named batch inserts, it's not split by [Options.MaxBatchParams].
+// [BindNamedAt] and [BindColon] are not supported.
+func (db *DB) InsertValues(ctx context.Context, query string, values any) (sql.Result, error) {
+	return db.base.insertValues(ctx, db.writePool(), query, values)
 }
 
 // Tx is an in-progress database transaction, representing a single connection.
@@ -223,6 +809,36 @@ func (tx *Tx) Rollback() error {
 	return tx.conn.Rollback()
 }
 
+// Savepoint creates a savepoint called name in the transaction, marking a point
+// it can be rolled back to with [Tx.RollbackTo] without aborting it entirely.
+// Savepoints are not parameterizable, so name must consist of ASCII letters,
+// digits and underscores, not starting with a digit.
+func (tx *Tx) Savepoint(ctx context.Context, name string) error {
+	return tx.execSavepoint(ctx, "SAVEPOINT", name)
+}
+
+// RollbackTo undoes the changes made after the savepoint called name was created,
+// which is kept, so it can be rolled back to again. The transaction stays active.
+func (tx *Tx) RollbackTo(ctx context.Context, name string) error {
+	return tx.execSavepoint(ctx, "ROLLBACK TO SAVEPOINT", name)
+}
+
+// ReleaseSavepoint destroys the savepoint called name, keeping the changes
+// made after it was created.
+func (tx *Tx) ReleaseSavepoint(ctx context.Context, name string) error {
+	return tx.execSavepoint(ctx, "RELEASE SAVEPOINT", name)
+}
+
+func (tx *Tx) execSavepoint(ctx context.Context, stmt, name string) error {
+	if !isPlainIdent(name) {
+		return fmt.Errorf("sqlz: invalid savepoint name: '%s'", name)
+	}
+	if _, err := tx.conn.ExecContext(ctx, stmt+" "+name); err != nil {
+		return fmt.Errorf("sqlz: executing %s %s: %w", stmt, name, err)
+	}
+	return nil
+}
+
 // Query executes a query that can return multiple rows. Any errors are deferred
 // until [Scanner.Err] or [Scanner.Scan] is called.
 //
@@ -248,6 +864,42 @@ func (tx *Tx) QueryRow(ctx context.Context, query string, args ...any) *Scanner
 	return tx.base.queryRow(ctx, tx.conn, query, args...)
 }
 
+// SelectAppend executes a query that can return multiple rows, and appends them
+// to dest, which must be a pointer to a slice. Existing elements are kept,
+// which is useful to gather pages into a single slice across calls.
+//
+// The args are for any placeholder parameters in the query,
+// the default placeholder depends on the driver.
+func (tx *Tx) SelectAppend(ctx context.Context, dest any, query string, args ...any) error {
+	return tx.base.selectAppend(ctx, tx.conn, dest, query, args...)
+}
+
+// SelectGroup executes a query that can return multiple rows, and groups them
+// by the value of keyCol, each row is scanned as a map, keeping the query order
+// within each group. It's useful for one-to-many fetches from a single joined query.
+//
+// The args are for any placeholder parameters in the query,
+// the default placeholder depends on the driver.
+func (tx *Tx) SelectGroup(ctx context.Context, query, keyCol string, args ...any) (map[any][]map[string]any, error) {
+	return tx.base.selectGroup(ctx, tx.conn, query, keyCol, args...)
+}
+
+// Exists executes a query that checks the existence of rows, like "SELECT EXISTS(...)",
+// and returns its result, normalizing driver differences, e.g. Postgres returns
+// a bool and MySQL returns 0 or 1. A query selecting no rows returns false.
+//
+// The args are for any placeholder parameters in the query,
+// the default placeholder depends on the driver.
+func (tx *Tx) Exists(ctx context.Context, query string, args ...any) (bool, error) {
+	return tx.base.exists(ctx, tx.conn, query, args...)
+}
+
+// GetRow executes a query that is expected to return one row, and scans its
+// columns into dests positionally. See [DB.GetRow] for details.
+func (tx *Tx) GetRow(ctx context.Context, query string, args []any, dests ...any) error {
+	return tx.base.getRow(ctx, tx.conn, query, args, dests)
+}
+
 // Exec executes a query without returning any rows.
 //
 // The args are for any placeholder parameters in the query,
@@ -258,3 +910,28 @@ func (tx *Tx) QueryRow(ctx context.Context, query string, args ...any) *Scanner
 func (tx *Tx) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
 	return tx.base.exec(ctx, tx.conn, query, args...)
 }
+
+// PrepareNamedStmt compiles the named query to the native bind and prepares it
+// in the transaction, returning the statement and the name of each placeholder,
+// in order. See [DB.PrepareNamedStmt] for details.
+func (tx *Tx) PrepareNamedStmt(ctx context.Context, query string) (*sql.Stmt, []string, error) {
+	return tx.base.prepareNamed(ctx, tx.conn, query)
+}
+
+// ExecMany executes query once per arg set of argsList in the transaction,
+// returning the result of each execution. See [DB.ExecMany] for details.
+func (tx *Tx) ExecMany(ctx context.Context, query string, argsList []any) ([]sql.Result, error) {
+	return tx.base.execMany(ctx, tx.conn, query, argsList)
+}
+
+// InsertValues executes a native INSERT query in the transaction, repeating its
+// single placeholder VALUES row once per element of values. See [DB.InsertValues] for details.
+func (tx *Tx) InsertValues(ctx context.Context, query string, values any) (sql.Result, error) {
+	return tx.base.insertValues(ctx, tx.conn, query, values)
+}
+
+// ExecInsert executes an insert query in the transaction and appends the ids
+// generated for the inserted rows to dest. See [DB.ExecInsert] for details.
+func (tx *Tx) ExecInsert(ctx context.Context, dest any, query string, args ...any) error {
+	return tx.base.execInsert(ctx, tx.conn, dest, query, args...)
+}
@@ -3,7 +3,11 @@ package sqlz
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -34,6 +38,611 @@ func TestNew_panic(t *testing.T) {
 	New("wrongdriver", &sql.DB{}, nil)
 }
 
+func TestRegisterBind(t *testing.T) {
+	t.Cleanup(func() {
+		bindMu.Lock()
+		delete(bindByDriverName, "customdriver")
+		bindMu.Unlock()
+	})
+
+	RegisterBind("customdriver", BindQuestion)
+	db := New("customdriver", &sql.DB{}, nil)
+	assert.Equal(t, parser.BindQuestion, db.base.bind)
+
+	RegisterBind("customdriver", BindDollar)
+	db = New("customdriver", &sql.DB{}, nil)
+	assert.Equal(t, parser.BindDollar, db.base.bind)
+
+	RegisterBind("customdriver", BindNamedAt)
+	db = New("customdriver", &sql.DB{}, nil)
+	assert.Equal(t, parser.BindNamedAt, db.base.bind)
+
+	db = New("customdriver", &sql.DB{}, &Options{Bind: BindAt})
+	assert.Equal(t, parser.BindAt, db.base.bind)
+
+	assert.PanicsWithValue(t, "sqlz: unknown bind: 0", func() {
+		RegisterBind("customdriver", parser.BindUnknown)
+	})
+}
+
+func TestSetDefaultOptions(t *testing.T) {
+	t.Cleanup(func() { SetDefaultOptions(nil) })
+
+	opts := &Options{StructTag: "json", IgnoreMissingFields: true}
+	SetDefaultOptions(opts)
+	opts.StructTag = "changed"
+
+	db := New("sqlite3", &sql.DB{}, nil)
+	assert.Equal(t, "json", db.base.structTag)
+	assert.True(t, db.base.ignoreMissingFields)
+	assert.Nil(t, db.base.stmtCache)
+
+	db = New("sqlite3", &sql.DB{}, &Options{})
+	assert.Equal(t, defaultStructTag, db.base.structTag)
+	assert.False(t, db.base.ignoreMissingFields)
+
+	SetDefaultOptions(nil)
+	db = New("sqlite3", &sql.DB{}, nil)
+	assert.Equal(t, defaultStructTag, db.base.structTag)
+	assert.NotNil(t, db.base.stmtCache)
+}
+
+func TestCompile(t *testing.T) {
+	arg := map[string]any{"id": 1, "name": "Alice"}
+	query, args, err := Compile(BindDollar, "SELECT * FROM user WHERE id = :id AND name = :name", arg)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM user WHERE id = $1 AND name = $2", query)
+	assert.Equal(t, []any{1, "Alice"}, args)
+}
+
+func TestIn(t *testing.T) {
+	query, args, err := In(BindDollar, "SELECT * FROM user WHERE id IN ($1) AND status = $2", []int{4, 8}, "active")
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM user WHERE id IN ($1,$2) AND status = $3", query)
+	assert.Equal(t, []any{4, 8, "active"}, args)
+
+	query, args, err = In(BindQuestion, "SELECT * FROM user WHERE id = ?", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM user WHERE id = ?", query)
+	assert.Equal(t, []any{1}, args)
+
+	_, _, err = In(BindQuestion, "SELECT * FROM user WHERE id = ?", []int{1, 2})
+	assert.ErrorContains(t, err, "slices are only spread inside 'IN (...)'")
+}
+
+func TestNamed(t *testing.T) {
+	arg := struct {
+		Id   int      `json:"user_id"`
+		Tags []string `json:"tags"`
+	}{1, []string{"a", "b"}}
+
+	query, args, err := Named(BindQuestion, "json", "SELECT * FROM user WHERE id = :user_id AND tag IN (:tags)", arg)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM user WHERE id = ? AND tag IN (?,?)", query)
+	assert.Equal(t, []any{1, "a", "b"}, args)
+
+	query, _, err = Named(BindDollar, "", "SELECT * FROM user WHERE id = :id", arg)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM user WHERE id = $1", query)
+}
+
+func TestCompileVerbose(t *testing.T) {
+	t.Run("multiple params", func(t *testing.T) {
+		arg := struct {
+			Name   string
+			Ids    []int
+			Status string
+		}{"Alice", []int{4, 8, 16}, "active"}
+
+		query, args, indexes, err := CompileVerbose(
+			BindQuestion,
+			"SELECT * FROM user WHERE name = :name AND id IN (:ids) AND status = :status OR nickname = :name",
+			arg,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM user WHERE name = ? AND id IN (?,?,?) AND status = ? OR nickname = ?", query)
+		assert.Equal(t, []any{"Alice", 4, 8, 16, "active", "Alice"}, args)
+		assert.Equal(t, map[string]int{"name": 0, "ids": 1, "status": 4}, indexes)
+	})
+
+	t.Run("batch insert", func(t *testing.T) {
+		arg := []map[string]any{{"id": 1, "name": "Alice"}, {"id": 2, "name": "Rob"}}
+		_, args, indexes, err := CompileVerbose(BindDollar, "INSERT INTO user (id, name) VALUES (:id, :name)", arg)
+		require.NoError(t, err)
+		assert.Equal(t, []any{1, "Alice", 2, "Rob"}, args)
+		assert.Equal(t, map[string]int{"id": 0, "name": 1}, indexes)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, _, _, err := CompileVerbose(BindQuestion, "SELECT :id", 42)
+		assert.ErrorContains(t, err, "unsupported argument type")
+	})
+}
+
+func TestInterpolate(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	got, err := Interpolate(BindQuestion,
+		"UPDATE user SET name = ?, active = ?, deleted_at = ?, updated_at = ? WHERE id = ?",
+		[]any{"it's", false, nil, ts, 42},
+	)
+	require.NoError(t, err)
+	expect := "UPDATE user SET name = 'it''s', active = 0, deleted_at = NULL, updated_at = '2025-01-02 03:04:05' WHERE id = 42"
+	assert.Equal(t, expect, got)
+}
+
+func TestKeyset(t *testing.T) {
+	query, args := Keyset("SELECT * FROM user WHERE active = ?", "id", 42, 10, BindQuestion)
+	assert.Equal(t, "SELECT * FROM user WHERE (active = ?) AND id > ? ORDER BY id LIMIT ?", query)
+	assert.Equal(t, []any{42, 10}, args)
+}
+
+func TestKeyset_pagination(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, nil)
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := db.Exec(ctx, th.fmt(`CREATE TABLE %s (id INT PRIMARY KEY, active BOOLEAN NOT NULL)`))
+		require.NoError(t, err)
+
+		for id := 1; id <= 7; id++ {
+			_, err = db.Exec(ctx, th.fmt(`INSERT INTO %s (id, active) VALUES (?, ?)`), id, id != 4)
+			require.NoError(t, err)
+		}
+
+		baseQuery := th.fmt(`SELECT id FROM %s WHERE active = ?`)
+
+		var pages [][]int
+		var afterVal any
+		for {
+			query, args := Keyset(baseQuery, "id", afterVal, 2, conn.bind)
+			var ids []int
+			err := db.Query(ctx, query, append([]any{true}, args...)...).Scan(&ids)
+			require.NoError(t, err)
+			if len(ids) == 0 {
+				break
+			}
+			pages = append(pages, ids)
+			afterVal = ids[len(ids)-1]
+		}
+
+		assert.Equal(t, [][]int{{1, 2}, {3, 5}, {6, 7}}, pages)
+	})
+}
+
+func TestDB_Exists(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, nil)
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := db.Exec(ctx, th.fmt(`CREATE TABLE %s (id INT PRIMARY KEY, name VARCHAR(100))`))
+		require.NoError(t, err)
+
+		_, err = db.Exec(ctx, th.fmt(`INSERT INTO %s (id, name) VALUES (1, 'Alice')`))
+		require.NoError(t, err)
+
+		query := th.fmt(`SELECT EXISTS(SELECT 1 FROM %s WHERE name = ?)`)
+
+		exists, err := db.Exists(ctx, query, "Alice")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		exists, err = db.Exists(ctx, query, "Bob")
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		exists, err = db.Exists(ctx, th.fmt(`SELECT EXISTS(SELECT 1 FROM %s WHERE id = :id)`), map[string]any{"id": 1})
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		tx, err := db.Begin(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		exists, err = tx.Exists(ctx, query, "Alice")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+}
+
+func TestDB_Exists_no_rows(t *testing.T) {
+	pool := sql.OpenDB(&countingConnector{})
+	t.Cleanup(func() { pool.Close() })
+	db := New("mock", pool, &Options{Bind: BindQuestion})
+
+	exists, err := db.Exists(ctx, "SELECT 1 FROM user WHERE id = ?", 1)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = db.Exists(ctx, " ")
+	assert.ErrorContains(t, err, "query cannot be blank")
+}
+
+func TestDB_PrepareNamedStmt(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, nil)
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := db.Exec(ctx, th.fmt(`CREATE TABLE %s (id INT PRIMARY KEY, name VARCHAR(100))`))
+		require.NoError(t, err)
+
+		stmt, idents, err := db.PrepareNamedStmt(ctx, th.fmt(`INSERT INTO %s (name, id) VALUES (:name, :id)`))
+		require.NoError(t, err)
+		defer stmt.Close()
+		assert.Equal(t, []string{"name", "id"}, idents)
+
+		for _, row := range []map[string]any{{"id": 1, "name": "Alice"}, {"id": 2, "name": "Bob"}} {
+			args := make([]any, len(idents))
+			for i, ident := range idents {
+				args[i] = row[ident]
+			}
+			_, err = stmt.ExecContext(ctx, args...)
+			require.NoError(t, err)
+		}
+
+		var names []string
+		err = db.Query(ctx, th.fmt(`SELECT name FROM %s ORDER BY id`)).Scan(&names)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Alice", "Bob"}, names)
+
+		tx, err := db.Begin(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		stmt, idents, err = tx.PrepareNamedStmt(ctx, th.fmt(`SELECT name FROM %s WHERE id = :id`))
+		require.NoError(t, err)
+		defer stmt.Close()
+		assert.Equal(t, []string{"id"}, idents)
+
+		var name string
+		err = stmt.QueryRowContext(ctx, 2).Scan(&name)
+		require.NoError(t, err)
+		assert.Equal(t, "Bob", name)
+	})
+}
+
+func TestDB_PrepareNamedStmt_mock(t *testing.T) {
+	connector := &countingConnector{}
+	pool := sql.OpenDB(connector)
+	t.Cleanup(func() { pool.Close() })
+	db := New("mock", pool, &Options{Bind: BindDollar})
+
+	stmt, idents, err := db.PrepareNamedStmt(ctx, "UPDATE user SET name = :name WHERE id = :id OR parent_id = :id")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "id", "id"}, idents)
+	assert.Equal(t, int32(1), connector.prepares.Load())
+
+	_, err = stmt.ExecContext(ctx, "Alice", 1, 1)
+	require.NoError(t, err)
+	require.NoError(t, stmt.Close())
+
+	_, _, err = db.PrepareNamedStmt(ctx, " ")
+	assert.ErrorContains(t, err, "query cannot be blank")
+}
+
+func TestDB_BindNamed(t *testing.T) {
+	db := New("mock", sql.OpenDB(&countingConnector{}), &Options{Bind: BindDollar, StructTag: "json"})
+
+	type User struct {
+		Id   int    `json:"user_id"`
+		Name string `json:"name"`
+	}
+
+	query, args, err := db.BindNamed("UPDATE user SET name = :name WHERE id = :user_id", User{1, "Alice"})
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE user SET name = $1 WHERE id = $2", query)
+	assert.Equal(t, []any{"Alice", 1}, args)
+
+	query, args, err = db.BindNamed("SELECT * FROM user WHERE id IN (:ids)", map[string]any{"ids": []int{1, 2}})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM user WHERE id IN ($1,$2)", query)
+	assert.Equal(t, []any{1, 2}, args)
+
+	query, args, err = db.BindNamed("INSERT INTO user (user_id, name) VALUES (:user_id, :name)", []User{{1, "Alice"}, {2, "Bob"}})
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO user (user_id, name) VALUES ($1, $2),($3, $4)", query)
+	assert.Equal(t, []any{1, "Alice", 2, "Bob"}, args)
+
+	_, _, err = db.BindNamed("SELECT * FROM user WHERE id = $1", 1)
+	assert.ErrorContains(t, err, "named arg must be a struct, a map or a slice of them, got int")
+
+	_, _, err = db.BindNamed("SELECT * FROM user WHERE id = :id", map[string]any{})
+	assert.ErrorContains(t, err, "could not find 'id'")
+}
+
+func TestDB_StructRows(t *testing.T) {
+	type Address struct {
+		City string
+	}
+
+	type User struct {
+		UserId  int
+		Name    string `json:"full_name"`
+		Address *Address
+	}
+
+	users := []User{
+		{1, "Alice", &Address{"Wonderland"}},
+		{2, "Rob", nil},
+	}
+
+	db := New("mock", sql.OpenDB(&countingConnector{}), &Options{Bind: BindQuestion, StructTag: "json"})
+	values, err := db.StructRows(users, []string{"user_id", "full_name", "address_city"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{1, "Alice", "Wonderland"}, {2, "Rob", nil}}, values)
+
+	_, err = db.StructRows(users, []string{"address.city"})
+	assert.ErrorContains(t, err, "field not found: 'address.city'")
+
+	db = New("mock", sql.OpenDB(&countingConnector{}), &Options{Bind: BindQuestion, FieldNameTransformer: strings.ToUpper})
+	values, err = db.StructRows(users, []string{"USERID", "ADDRESS_CITY"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{1, "Wonderland"}, {2, nil}}, values)
+}
+
+func TestDB_GetRow(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, nil)
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := db.Exec(ctx, th.fmt(`CREATE TABLE %s (id INT PRIMARY KEY, name VARCHAR(100), score INT)`))
+		require.NoError(t, err)
+
+		_, err = db.Exec(ctx, th.fmt(`INSERT INTO %s (id, name, score) VALUES (1, 'Alice', 10), (2, 'Bob', 20)`))
+		require.NoError(t, err)
+
+		var (
+			id    int
+			name  string
+			score int
+		)
+		err = db.GetRow(ctx, th.fmt(`SELECT id, name, score FROM %s WHERE id = ?`), []any{2}, &id, &name, &score)
+		require.NoError(t, err)
+		assert.Equal(t, 2, id)
+		assert.Equal(t, "Bob", name)
+		assert.Equal(t, 20, score)
+
+		var count, total int
+		query := th.fmt(`SELECT COUNT(*), SUM(score) FROM %s WHERE id IN (:ids)`)
+		err = db.GetRow(ctx, query, []any{map[string]any{"ids": []int{1, 2}}}, &count, &total)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, 30, total)
+
+		err = db.GetRow(ctx, th.fmt(`SELECT id, name FROM %s WHERE id = ?`), []any{3}, &id, &name)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		err = db.GetRow(ctx, th.fmt(`SELECT id FROM %s`), nil, &id)
+		assert.ErrorContains(t, err, "expected one row, got more")
+
+		tx, err := db.Begin(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		err = tx.GetRow(ctx, th.fmt(`SELECT name FROM %s WHERE id = ?`), []any{1}, &name)
+		require.NoError(t, err)
+		assert.Equal(t, "Alice", name)
+	})
+}
+
+func TestDB_GetRow_no_rows(t *testing.T) {
+	pool := sql.OpenDB(&countingConnector{})
+	t.Cleanup(func() { pool.Close() })
+	db := New("mock", pool, &Options{Bind: BindQuestion})
+
+	var a, b, c int
+	err := db.GetRow(ctx, "SELECT a, b, c FROM t WHERE id = ?", []any{1}, &a, &b, &c)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.True(t, IsNotFound(err))
+
+	err = db.GetRow(ctx, " ", nil, &a)
+	assert.ErrorContains(t, err, "query cannot be blank")
+}
+
+func TestDB_ExecMany(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, nil)
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := db.Exec(ctx, th.fmt(`CREATE TABLE %s (id INT PRIMARY KEY, name VARCHAR(100))`))
+		require.NoError(t, err)
+
+		_, err = db.Exec(ctx, th.fmt(`INSERT INTO %s (id, name) VALUES (1, 'a'), (2, 'b'), (3, 'c')`))
+		require.NoError(t, err)
+
+		results, err := db.ExecMany(ctx, th.fmt(`UPDATE %s SET name = :name WHERE id = :id`), []any{
+			map[string]any{"id": 1, "name": "Alice"},
+			map[string]any{"id": 3, "name": "Carol"},
+			map[string]any{"id": 4, "name": "Dave"},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		for i, expected := range []int64{1, 1, 0} {
+			affected, err := results[i].RowsAffected()
+			require.NoError(t, err)
+			assert.Equal(t, expected, affected)
+		}
+
+		tx, err := db.Begin(ctx)
+		require.NoError(t, err)
+		results, err = tx.ExecMany(ctx, th.fmt(`UPDATE %s SET name = ? WHERE id = ?`), []any{
+			[]any{"Bob", 2},
+		})
+		require.NoError(t, err)
+		assert.Len(t, results, 1)
+		require.NoError(t, tx.Commit())
+
+		var names []string
+		err = db.Query(ctx, th.fmt(`SELECT name FROM %s ORDER BY id`)).Scan(&names)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Alice", "Bob", "Carol"}, names)
+	})
+}
+
+func TestDB_ExecMany_mock(t *testing.T) {
+	pool := sql.OpenDB(&countingConnector{})
+	t.Cleanup(func() { pool.Close() })
+	db := New("mock", pool, &Options{Bind: BindDollar})
+
+	query := "UPDATE user SET name = :name WHERE id = :id"
+	results, err := db.ExecMany(ctx, query, []any{
+		map[string]any{"id": 1, "name": "Alice"},
+		map[string]any{"id": 2, "name": "Bob"},
+	})
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+
+	results, err = db.ExecMany(ctx, query, []any{
+		map[string]any{"id": 1, "name": "Alice"},
+		map[string]any{"id": 2},
+	})
+	assert.ErrorContains(t, err, "executing arg set 1")
+	assert.Len(t, results, 1)
+
+	results, err = db.ExecMany(ctx, query, nil)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestDB_ExecInsert(t *testing.T) {
+	type User struct {
+		Name string
+	}
+
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, nil)
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		create := `CREATE TABLE %s (id INT PRIMARY KEY AUTO_INCREMENT, name VARCHAR(100))`
+		if conn.bind == parser.BindDollar {
+			create = `CREATE TABLE %s (id SERIAL PRIMARY KEY, name VARCHAR(100))`
+		}
+		_, err := db.Exec(ctx, th.fmt(create))
+		require.NoError(t, err)
+
+		query := th.fmt(`INSERT INTO %s (name) VALUES (:name) RETURNING id`)
+
+		var ids []int64
+		err = db.ExecInsert(ctx, &ids, query, User{"Alice"})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1}, ids)
+
+		err = db.ExecInsert(ctx, &ids, query, []User{{"Bob"}, {"Carol"}, {"Dave"}})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 2, 3, 4}, ids)
+
+		tx, err := db.Begin(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		ids = nil
+		err = tx.ExecInsert(ctx, &ids, query, User{"Eve"})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{5}, ids)
+	})
+}
+
+func TestDB_ExecInsert_mock(t *testing.T) {
+	pool := sql.OpenDB(&countingConnector{})
+	t.Cleanup(func() { pool.Close() })
+
+	t.Run("last insert id unsupported", func(t *testing.T) {
+		db := New("mock", pool, &Options{Bind: BindQuestion})
+
+		var ids []int64
+		err := db.ExecInsert(ctx, &ids, "INSERT INTO user (name) VALUES (?)", "Alice")
+		assert.ErrorContains(t, err, "driver cannot return the last insert id")
+		assert.Empty(t, ids)
+
+		var names []string
+		err = db.ExecInsert(ctx, &names, "INSERT INTO user (name) VALUES (?)", "Alice")
+		assert.ErrorContains(t, err, "destination must be *[]int64")
+	})
+
+	t.Run("returning required", func(t *testing.T) {
+		db := New("mock", pool, &Options{Bind: BindDollar})
+
+		var ids []int64
+		err := db.ExecInsert(ctx, &ids, "INSERT INTO user (name) VALUES ($1)", "Alice")
+		assert.ErrorContains(t, err, "must have a RETURNING clause")
+
+		err = db.ExecInsert(ctx, &ids, "INSERT INTO user (name) VALUES ($1) RETURNING id", "Alice")
+		require.NoError(t, err)
+	})
+
+	t.Run("multiple rows", func(t *testing.T) {
+		newDB := func(driverName string, result driver.Result) *DB {
+			pool := sql.OpenDB(&countingConnector{execResult: result})
+			t.Cleanup(func() { pool.Close() })
+			return New(driverName, pool, nil)
+		}
+		query := "INSERT INTO user (name) VALUES (?),(?),(?)"
+
+		// MySQL reports the first id
+		var ids []int64
+		err := newDB("mysql", driverResult{10, 3}).ExecInsert(ctx, &ids, query, "a", "b", "c")
+		require.NoError(t, err)
+		assert.Equal(t, []int64{10, 11, 12}, ids)
+
+		// SQLite reports the last id
+		ids = nil
+		err = newDB("sqlite3", driverResult{12, 3}).ExecInsert(ctx, &ids, query, "a", "b", "c")
+		assert.ErrorContains(t, err, "may not report the id of the first of 3 inserted rows, add a RETURNING clause")
+		assert.Empty(t, ids)
+
+		err = newDB("sqlite3", driverResult{12, 1}).ExecInsert(ctx, &ids, "INSERT INTO user (name) VALUES (?)", "a")
+		require.NoError(t, err)
+		assert.Equal(t, []int64{12}, ids)
+
+		// RETURNING is scanned, the mock returns no rows
+		ids = nil
+		connector := &countingConnector{execResult: driverResult{12, 3}}
+		pool := sql.OpenDB(connector)
+		t.Cleanup(func() { pool.Close() })
+		err = New("sqlite3", pool, nil).ExecInsert(ctx, &ids, query+" RETURNING id", "a", "b", "c")
+		require.NoError(t, err)
+		assert.Empty(t, ids)
+		assert.Zero(t, connector.execs.Load())
+	})
+
+	t.Run("chunked batch", func(t *testing.T) {
+		type User struct{ Name string }
+		users := []User{{"a"}, {"b"}, {"c"}}
+		query := "INSERT INTO user (name) VALUES (:name)"
+
+		newDB := func(driverName string) *DB {
+			connector := &countingConnector{
+				transactional: true,
+				execResults:   []driver.Result{driverResult{10, 2}, driverResult{20, 1}},
+			}
+			pool := sql.OpenDB(connector)
+			t.Cleanup(func() { pool.Close() })
+			return New(driverName, pool, &Options{MaxBatchParams: 2})
+		}
+
+		// ids are computed per chunk, as other sessions may insert between them
+		var ids []int64
+		err := newDB("mysql").ExecInsert(ctx, &ids, query, users)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{10, 11, 20}, ids)
+
+		// the last insert id keeps the meaning of the driver
+		result, err := newDB("mysql").Exec(ctx, query, users)
+		require.NoError(t, err)
+		id, err := result.LastInsertId()
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), id)
+
+		result, err = newDB("sqlite3").Exec(ctx, query, users)
+		require.NoError(t, err)
+		id, err = result.LastInsertId()
+		require.NoError(t, err)
+		assert.Equal(t, int64(20), id)
+
+		err = newDB("sqlite3").ExecInsert(ctx, &ids, query, users)
+		assert.ErrorContains(t, err, "may not report the id of the first of 2 inserted rows")
+	})
+}
+
 func TestConnect_wrong_driver(t *testing.T) {
 	_, err := Connect("wrongdriver", ":memory:")
 	assert.Error(t, err)
@@ -253,6 +862,202 @@ func TestTx_context_cancellation(t *testing.T) {
 	})
 }
 
+func TestInTx(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, nil)
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := db.Exec(ctx, th.fmt(`CREATE TABLE IF NOT EXISTS %s (id INT PRIMARY KEY, name VARCHAR(255))`))
+		require.NoError(t, err)
+
+		insert := th.fmt(`INSERT INTO %s (id, name) VALUES (:id, :name)`)
+		count := func(t *testing.T) int {
+			var count int
+			err := db.QueryRow(ctx, th.fmt("SELECT count(1) FROM %s")).Scan(&count)
+			require.NoError(t, err)
+			return count
+		}
+
+		t.Run("returns value and commits", func(t *testing.T) {
+			name, err := InTx(ctx, db, func(tx *Tx) (string, error) {
+				if _, err := tx.Exec(ctx, insert, map[string]any{"id": 1, "name": "Alice"}); err != nil {
+					return "", err
+				}
+				var name string
+				err := tx.QueryRow(ctx, th.fmt("SELECT name FROM %s WHERE id = 1")).Scan(&name)
+				return name, err
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "Alice", name)
+			assert.Equal(t, 1, count(t))
+		})
+
+		t.Run("rolls back on error", func(t *testing.T) {
+			got, err := InTx(ctx, db, func(tx *Tx) (int, error) {
+				if _, err := tx.Exec(ctx, insert, map[string]any{"id": 2, "name": "Rob"}); err != nil {
+					return 0, err
+				}
+				return 2, assert.AnError
+			})
+			require.ErrorIs(t, err, assert.AnError)
+			assert.Zero(t, got)
+			assert.Equal(t, 1, count(t))
+		})
+
+		t.Run("rolls back on panic", func(t *testing.T) {
+			assert.PanicsWithValue(t, "boom", func() {
+				InTx(ctx, db, func(tx *Tx) (int, error) {
+					if _, err := tx.Exec(ctx, insert, map[string]any{"id": 3, "name": "John"}); err != nil {
+						return 0, err
+					}
+					panic("boom")
+				})
+			})
+			assert.Equal(t, 1, count(t))
+		})
+	})
+}
+
+func TestInTx_begin_error(t *testing.T) {
+	pool := sql.OpenDB(&countingConnector{})
+	t.Cleanup(func() { pool.Close() })
+	db := New("mock", pool, &Options{Bind: BindQuestion})
+
+	called := false
+	got, err := InTx(ctx, db, func(tx *Tx) (int, error) {
+		called = true
+		return 1, nil
+	})
+	require.ErrorIs(t, err, errors.ErrUnsupported)
+	assert.Zero(t, got)
+	assert.False(t, called)
+}
+
+func TestInTx_rollback_error(t *testing.T) {
+	rollbackErr := errors.New("connection lost")
+	connector := &countingConnector{transactional: true, rollbackErr: rollbackErr}
+	pool := sql.OpenDB(connector)
+	t.Cleanup(func() { pool.Close() })
+	db := New("mock", pool, &Options{Bind: BindQuestion})
+
+	got, err := InTx(ctx, db, func(tx *Tx) (int, error) {
+		return 1, assert.AnError
+	})
+	require.ErrorIs(t, err, assert.AnError)
+	require.ErrorIs(t, err, rollbackErr)
+	assert.Zero(t, got)
+	assert.Equal(t, int32(1), connector.rollbacks.Load())
+}
+
+func TestDB_WithTx(t *testing.T) {
+	newDB := func(t *testing.T, connector *countingConnector) *DB {
+		pool := sql.OpenDB(connector)
+		t.Cleanup(func() { pool.Close() })
+		return New("mock", pool, &Options{Bind: BindQuestion})
+	}
+
+	t.Run("commits", func(t *testing.T) {
+		connector := &countingConnector{transactional: true}
+		err := newDB(t, connector).WithTx(ctx, nil, func(tx *Tx) error {
+			_, err := tx.Exec(ctx, "DELETE FROM user WHERE id = ?", 1)
+			return err
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), connector.execs.Load())
+		assert.Equal(t, int32(1), connector.commits.Load())
+		assert.Zero(t, connector.rollbacks.Load())
+	})
+
+	t.Run("rolls back on error", func(t *testing.T) {
+		connector := &countingConnector{transactional: true}
+		err := newDB(t, connector).WithTx(ctx, nil, func(tx *Tx) error {
+			return assert.AnError
+		})
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Zero(t, connector.commits.Load())
+		assert.Equal(t, int32(1), connector.rollbacks.Load())
+	})
+
+	t.Run("joins rollback error", func(t *testing.T) {
+		rollbackErr := errors.New("connection lost")
+		connector := &countingConnector{transactional: true, rollbackErr: rollbackErr}
+		err := newDB(t, connector).WithTx(ctx, nil, func(tx *Tx) error {
+			return assert.AnError
+		})
+		require.ErrorIs(t, err, assert.AnError)
+		require.ErrorIs(t, err, rollbackErr)
+		assert.ErrorContains(t, err, "rolling back: connection lost")
+	})
+
+	t.Run("rolls back on panic", func(t *testing.T) {
+		connector := &countingConnector{transactional: true}
+		db := newDB(t, connector)
+		assert.PanicsWithValue(t, "boom", func() {
+			db.WithTx(ctx, nil, func(tx *Tx) error { panic("boom") })
+		})
+		assert.Zero(t, connector.commits.Load())
+		assert.Equal(t, int32(1), connector.rollbacks.Load())
+	})
+
+	t.Run("begin error", func(t *testing.T) {
+		called := false
+		err := newDB(t, &countingConnector{}).WithTx(ctx, nil, func(tx *Tx) error {
+			called = true
+			return nil
+		})
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+		assert.False(t, called)
+	})
+}
+
+func TestTx_savepoint_mock(t *testing.T) {
+	connector := &countingConnector{transactional: true}
+	pool := sql.OpenDB(connector)
+	t.Cleanup(func() { pool.Close() })
+	db := New("mock", pool, &Options{Bind: BindQuestion})
+
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	require.NoError(t, tx.Savepoint(ctx, "sp_1"))
+	require.NoError(t, tx.RollbackTo(ctx, "sp_1"))
+	require.NoError(t, tx.ReleaseSavepoint(ctx, "sp_1"))
+	assert.Equal(t, int32(3), connector.execs.Load())
+
+	err = tx.Savepoint(ctx, "sp; DROP TABLE user")
+	assert.ErrorContains(t, err, "invalid savepoint name: 'sp; DROP TABLE user'")
+	assert.Equal(t, int32(3), connector.execs.Load())
+}
+
+func TestTx_savepoint(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, nil)
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := db.Exec(ctx, th.fmt(`CREATE TABLE %s (id INT PRIMARY KEY)`))
+		require.NoError(t, err)
+
+		tx, err := db.Begin(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		_, err = tx.Exec(ctx, th.fmt(`INSERT INTO %s (id) VALUES (1)`))
+		require.NoError(t, err)
+		require.NoError(t, tx.Savepoint(ctx, "before_two"))
+
+		_, err = tx.Exec(ctx, th.fmt(`INSERT INTO %s (id) VALUES (2)`))
+		require.NoError(t, err)
+		require.NoError(t, tx.RollbackTo(ctx, "before_two"))
+		require.NoError(t, tx.ReleaseSavepoint(ctx, "before_two"))
+		require.NoError(t, tx.Commit())
+
+		var ids []int
+		require.NoError(t, db.Query(ctx, th.fmt(`SELECT id FROM %s`)).Scan(&ids))
+		assert.Equal(t, []int{1}, ids)
+	})
+}
+
 func TestTx_commit_rollback(t *testing.T) {
 	runConn(t, func(t *testing.T, conn *Conn) {
 		db := New(conn.driverName, conn.db, nil)
@@ -398,6 +1203,82 @@ func TestDB_custom_structTag(t *testing.T) {
 	})
 }
 
+func TestDB_SelectAppend(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, nil)
+		query := rebind(conn.bind, "SELECT ? AS page")
+
+		var pages []int
+		err := db.SelectAppend(ctx, &pages, query, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1}, pages)
+
+		err = db.SelectAppend(ctx, &pages, query, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, pages)
+
+		tx, err := db.Begin(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		err = tx.SelectAppend(ctx, &pages, query, 3)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, pages)
+	})
+}
+
+func TestDB_SelectAppend_validate_dest(t *testing.T) {
+	db := New("sqlite3", &sql.DB{}, nil)
+
+	var pages []int
+	err := db.SelectAppend(ctx, pages, "SELECT 1")
+	assert.ErrorContains(t, err, "pointer to a slice")
+
+	var page int
+	err = db.SelectAppend(ctx, &page, "SELECT 1")
+	assert.ErrorContains(t, err, "pointer to a slice")
+}
+
+func TestDB_SelectGroup(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, nil)
+		query := `
+		SELECT p.id AS parent_id, c.name
+		FROM (SELECT 1 AS id UNION ALL SELECT 2 UNION ALL SELECT 3) p
+		JOIN (
+			SELECT 1 AS parent_id, 'a' AS name
+			UNION ALL SELECT 2, 'b'
+			UNION ALL SELECT 1, 'c'
+		) c ON c.parent_id = p.id
+		ORDER BY c.name`
+
+		groups, err := db.SelectGroup(ctx, query, "parent_id")
+		require.NoError(t, err)
+
+		expect := map[any][]map[string]any{
+			int64(1): {
+				{"parent_id": int64(1), "name": "a"},
+				{"parent_id": int64(1), "name": "c"},
+			},
+			int64(2): {
+				{"parent_id": int64(2), "name": "b"},
+			},
+		}
+		assert.Equal(t, expect, groups)
+
+		_, err = db.SelectGroup(ctx, query, "unknown")
+		assert.ErrorContains(t, err, "key column not found: 'unknown'")
+
+		tx, err := db.Begin(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		groups, err = tx.SelectGroup(ctx, rebind(conn.bind, "SELECT 1 AS id WHERE 1 = ?"), "id", 0)
+		require.NoError(t, err)
+		assert.Empty(t, groups)
+	})
+}
+
 func TestDB_Pool(t *testing.T) {
 	runConn(t, func(t *testing.T, conn *Conn) {
 		db := New(conn.driverName, conn.db, nil)
@@ -411,6 +1292,49 @@ func TestDB_Pool(t *testing.T) {
 	})
 }
 
+func TestDB_stmtCache_prepare_calls(t *testing.T) {
+	newDB := func(capacity int) (*DB, *countingConnector) {
+		connector := &countingConnector{}
+		pool := sql.OpenDB(connector)
+		pool.SetMaxOpenConns(1)
+		t.Cleanup(func() { pool.Close() })
+		return New("mock", pool, &Options{Bind: BindQuestion, StatementCacheCapacity: capacity}), connector
+	}
+
+	t.Run("cache hits do not prepare again", func(t *testing.T) {
+		db, connector := newDB(2)
+		for range 5 {
+			_, err := db.Exec(ctx, "UPDATE user SET active = ? WHERE id = 1", true)
+			require.NoError(t, err)
+			var ids []int
+			err = db.Query(ctx, "SELECT id FROM user WHERE id = ?", 1).Scan(&ids)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(2), connector.prepares.Load())
+		assert.Equal(t, int32(0), connector.closes.Load())
+	})
+
+	t.Run("eviction closes the least recently used", func(t *testing.T) {
+		db, connector := newDB(2)
+		for _, id := range []int{1, 2, 3} {
+			_, err := db.Exec(ctx, fmt.Sprintf("DELETE FROM user_%d WHERE id = ?", id), id)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(3), connector.prepares.Load())
+		assert.Equal(t, int32(1), connector.closes.Load())
+		assert.Equal(t, 2, db.base.stmtCache.Len())
+	})
+
+	t.Run("disabled cache prepares every call", func(t *testing.T) {
+		db, connector := newDB(0)
+		for range 5 {
+			_, err := db.Exec(ctx, "UPDATE user SET active = ? WHERE id = 1", true)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(5), connector.prepares.Load())
+	})
+}
+
 func TestDB_ClearStmtCache(t *testing.T) {
 	runConn(t, func(t *testing.T, conn *Conn) {
 		db := New(conn.driverName, conn.db, nil)
@@ -424,3 +1348,285 @@ func TestDB_ClearStmtCache(t *testing.T) {
 		assert.Equal(t, db.base.stmtCache.Len(), 0)
 	})
 }
+
+func TestDB_ClearStmtCache_mock(t *testing.T) {
+	connector := &countingConnector{}
+	pool := sql.OpenDB(connector)
+	t.Cleanup(func() { pool.Close() })
+	db := New("mock", pool, &Options{Bind: BindQuestion, StatementCacheCapacity: 8})
+
+	for range 2 {
+		_, err := db.Exec(ctx, "UPDATE user SET name = :name WHERE id = :id", map[string]any{"id": 1, "name": "Alice"})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, db.base.stmtCache.Len())
+	assert.Equal(t, int32(1), connector.prepares.Load())
+
+	db.ClearStmtCache()
+	assert.Equal(t, 0, db.base.stmtCache.Len())
+	assert.Equal(t, int32(1), connector.closes.Load())
+
+	_, err := db.Exec(ctx, "UPDATE user SET name = :name WHERE id = :id", map[string]any{"id": 1, "name": "Bob"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, db.base.stmtCache.Len())
+	assert.Equal(t, int32(2), connector.prepares.Load())
+
+	// no-op if caching is disabled
+	New("mock", pool, &Options{Bind: BindQuestion}).ClearStmtCache()
+}
+
+func TestDB_stmtCache_badConn(t *testing.T) {
+	connector := &countingConnector{}
+	pool := sql.OpenDB(connector)
+	t.Cleanup(func() { pool.Close() })
+	db := New("mock", pool, &Options{Bind: BindQuestion, StatementCacheCapacity: 8})
+
+	const query = "UPDATE user SET name = ? WHERE id = ?"
+	_, err := db.Exec(ctx, query, "Alice", 1)
+	require.NoError(t, err)
+	cached, err := db.base.loadOrPrepare(ctx, pool, query)
+	require.NoError(t, err)
+
+	// outlasts the retries of database/sql, then the cached stmt is re-prepared
+	connector.badConns.Store(3)
+	_, err = db.Exec(ctx, query, "Bob", 1)
+	require.NoError(t, err)
+
+	stmt, err := db.base.loadOrPrepare(ctx, pool, query)
+	require.NoError(t, err)
+	assert.NotSame(t, cached, stmt)
+	assert.Equal(t, 1, db.base.stmtCache.Len())
+
+	connector.badConns.Store(3)
+	var ids []int
+	err = db.Query(ctx, "SELECT id FROM user WHERE id = ?", 1).Scan(&ids)
+	require.NoError(t, err)
+
+	// retries only once
+	connector.badConns.Store(6)
+	_, err = db.Exec(ctx, query, "Carol", 1)
+	assert.ErrorIs(t, err, driver.ErrBadConn)
+	connector.badConns.Store(0)
+}
+
+func TestDB_ReadWriteRouter(t *testing.T) {
+	newPool := func() (*sql.DB, *countingConnector) {
+		connector := &countingConnector{}
+		pool := sql.OpenDB(connector)
+		t.Cleanup(func() { pool.Close() })
+		return pool, connector
+	}
+
+	primary, primaryConnector := newPool()
+	replica, replicaConnector := newPool()
+
+	db := New("mock", primary, &Options{
+		Bind:                   BindQuestion,
+		StatementCacheCapacity: 8,
+		ReadWriteRouter: func(ctx context.Context, query string) *sql.DB {
+			if strings.Contains(query, "FOR UPDATE") {
+				return nil
+			}
+			return replica
+		},
+	})
+
+	t.Run("reads hit the replica", func(t *testing.T) {
+		var ids []int
+		require.NoError(t, db.Query(ctx, "SELECT id FROM user WHERE id = ?", 1).Scan(&ids))
+		require.NoError(t, db.SelectAppend(ctx, &ids, "SELECT id FROM user"))
+		_, err := db.SelectGroup(ctx, "SELECT id FROM user", "id")
+		require.NoError(t, err)
+		err = db.QueryRow(ctx, "SELECT id FROM user WHERE id = ?", 1).Scan(&ids)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		assert.Equal(t, int32(3), replicaConnector.prepares.Load())
+		assert.Zero(t, primaryConnector.prepares.Load())
+	})
+
+	t.Run("writes hit the primary", func(t *testing.T) {
+		_, err := db.Exec(ctx, "UPDATE user SET active = ? WHERE id = 1", true)
+		require.NoError(t, err)
+
+		var ids []int
+		require.NoError(t, db.Query(ctx, "SELECT id FROM user WHERE id = ? FOR UPDATE", 1).Scan(&ids))
+
+		assert.Equal(t, int32(2), primaryConnector.prepares.Load())
+		assert.Equal(t, int32(3), replicaConnector.prepares.Load())
+	})
+
+	t.Run("statements are cached per pool", func(t *testing.T) {
+		query := "SELECT id FROM user WHERE id = ?"
+		_, err := db.Exec(ctx, query, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), primaryConnector.prepares.Load())
+
+		var ids []int
+		require.NoError(t, db.Query(ctx, query, 1).Scan(&ids))
+		assert.Equal(t, int32(3), replicaConnector.prepares.Load())
+	})
+}
+
+// fakeSpan is a span recorded by [fakeTracer].
+type fakeSpan struct {
+	name   string
+	query  string
+	caller string
+	ended  bool
+	err    error
+}
+
+// fakeTracer is a [Tracer] recording its spans.
+type fakeTracer struct {
+	spans []*fakeSpan
+}
+
+func (f *fakeTracer) StartSpan(ctx context.Context, name, query string) (context.Context, func(error)) {
+	caller, _ := CallerFromContext(ctx)
+	span := &fakeSpan{name: name, query: query, caller: caller}
+	f.spans = append(f.spans, span)
+	return ctx, func(err error) {
+		span.ended = true
+		span.err = err
+	}
+}
+
+func TestDB_Tracer(t *testing.T) {
+	pool := sql.OpenDB(&countingConnector{})
+	t.Cleanup(func() { pool.Close() })
+
+	tracer := &fakeTracer{}
+	db := New("mock", pool, &Options{Bind: BindDollar, Tracer: tracer})
+
+	t.Run("span per operation", func(t *testing.T) {
+		tracer.spans = nil
+
+		var ids []int
+		require.NoError(t, db.Query(ctx, "SELECT id FROM user WHERE id = :id", map[string]any{"id": 1}).Scan(&ids))
+		err := db.QueryRow(ctx, "SELECT id FROM user").Scan(&ids)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+		_, err = db.Exec(ctx, "UPDATE user SET active = ? WHERE id = 1", true)
+		require.NoError(t, err)
+
+		expect := []*fakeSpan{
+			{name: "sqlz.Query", query: "SELECT id FROM user WHERE id = $1", ended: true},
+			{name: "sqlz.QueryRow", query: "SELECT id FROM user", ended: true},
+			{name: "sqlz.Exec", query: "UPDATE user SET active = ? WHERE id = 1", ended: true},
+		}
+		assert.Equal(t, expect, tracer.spans)
+	})
+
+	t.Run("ended with error", func(t *testing.T) {
+		tracer.spans = nil
+
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := db.Exec(canceled, "DELETE FROM user WHERE id = 1")
+		require.ErrorIs(t, err, context.Canceled)
+
+		require.Len(t, tracer.spans, 1)
+		assert.True(t, tracer.spans[0].ended)
+		assert.ErrorIs(t, tracer.spans[0].err, context.Canceled)
+	})
+
+	t.Run("no span if query is not executed", func(t *testing.T) {
+		tracer.spans = nil
+
+		_, err := db.Exec(ctx, " ")
+		require.Error(t, err)
+		assert.Empty(t, tracer.spans)
+	})
+}
+
+// sliceRows is a custom [Rows] cursor over in-memory values.
+type sliceRows struct {
+	columns []string
+	data    [][]any
+	pos     int
+	closed  bool
+}
+
+func (r *sliceRows) Close() error               { r.closed = true; return nil }
+func (r *sliceRows) Columns() ([]string, error) { return r.columns, nil }
+func (r *sliceRows) Err() error                 { return nil }
+func (r *sliceRows) Next() bool                 { r.pos++; return r.pos <= len(r.data) }
+func (r *sliceRows) Scan(dest ...any) error {
+	for i, v := range r.data[r.pos-1] {
+		switch d := dest[i].(type) {
+		case *any:
+			*d = v
+		case sql.Scanner:
+			if err := d.Scan(v); err != nil {
+				return err
+			}
+		default:
+			dv := reflect.ValueOf(d).Elem()
+			if dv.Kind() == reflect.Pointer {
+				dv.Set(reflect.New(dv.Type().Elem()))
+				dv = dv.Elem()
+			}
+			dv.Set(reflect.ValueOf(v))
+		}
+	}
+	return nil
+}
+
+func TestNewScanner(t *testing.T) {
+	newRows := func() *sliceRows {
+		return &sliceRows{
+			columns: []string{"user_id", "user_name"},
+			data:    [][]any{{1, "Alice"}, {2, "Rob"}},
+		}
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		type User struct {
+			UserId   int
+			UserName string
+		}
+
+		rows := newRows()
+		var users []User
+		err := NewScanner(rows, nil).Scan(&users)
+		require.NoError(t, err)
+		assert.Equal(t, []User{{1, "Alice"}, {2, "Rob"}}, users)
+		assert.True(t, rows.closed)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		type User struct {
+			Id   int    `json:"user_id"`
+			Name string `json:"user_name"`
+		}
+
+		var users []User
+		err := NewScanner(newRows(), &Options{StructTag: "json", MaxColumns: 2}).Scan(&users)
+		require.NoError(t, err)
+		assert.Equal(t, []User{{1, "Alice"}, {2, "Rob"}}, users)
+
+		err = NewScanner(newRows(), &Options{MaxColumns: 1}).Scan(&users)
+		assert.ErrorContains(t, err, "too many columns")
+	})
+
+	t.Run("manual iteration", func(t *testing.T) {
+		scanner := NewScanner(newRows(), nil)
+		var names []string
+		for scanner.NextRow() {
+			var m map[string]any
+			require.NoError(t, scanner.ScanRow(&m))
+			names = append(names, m["user_name"].(string))
+		}
+		require.NoError(t, scanner.Err())
+		assert.Equal(t, []string{"Alice", "Rob"}, names)
+	})
+
+	t.Run("auto without column types", func(t *testing.T) {
+		result, err := NewScanner(newRows(), nil).ScanAuto()
+		require.NoError(t, err)
+		assert.Equal(t, []map[string]any{
+			{"user_id": 1, "user_name": "Alice"},
+			{"user_id": 2, "user_name": "Rob"},
+		}, result)
+	})
+}
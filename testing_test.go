@@ -4,11 +4,16 @@ package sqlz
 
 import (
 	"cmp"
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/rfberaldo/sqlz/internal/parser"
@@ -130,3 +135,109 @@ func questionToDollar(query string) string {
 	}
 	return sb.String()
 }
+
+// countingConnector is a [driver.Connector] of a fake driver which counts
+// prepared and closed statements, queries return rows of columns, if set,
+// otherwise no rows.
+// While badConns > 0, statements fail with [driver.ErrBadConn], decrementing it.
+// Execs are counted too, and the failExec-th one fails, if set.
+// Execs return execResult, if set, or the one of execResults in their order.
+// Transactions are only supported if transactional, counting their ends,
+// rollbacks fail with rollbackErr, if set.
+type countingConnector struct {
+	prepares atomic.Int32
+	closes   atomic.Int32
+	badConns atomic.Int32
+	execs    atomic.Int32
+	failExec int32
+
+	execResult  driver.Result
+	execResults []driver.Result
+
+	columns []string
+	rows    [][]driver.Value
+
+	transactional bool
+	commits       atomic.Int32
+	rollbacks     atomic.Int32
+	rollbackErr   error
+}
+
+func (c *countingConnector) Connect(context.Context) (driver.Conn, error) {
+	return &countingConn{c}, nil
+}
+func (c *countingConnector) Driver() driver.Driver { return nil }
+
+type countingConn struct{ c *countingConnector }
+
+func (cn *countingConn) Prepare(string) (driver.Stmt, error) {
+	cn.c.prepares.Add(1)
+	return &countingStmt{cn.c}, nil
+}
+func (cn *countingConn) Close() error { return nil }
+func (cn *countingConn) Begin() (driver.Tx, error) {
+	if !cn.c.transactional {
+		return nil, errors.ErrUnsupported
+	}
+	return &countingTx{cn.c}, nil
+}
+
+type countingTx struct{ c *countingConnector }
+
+func (tx *countingTx) Commit() error {
+	tx.c.commits.Add(1)
+	return nil
+}
+func (tx *countingTx) Rollback() error {
+	tx.c.rollbacks.Add(1)
+	return tx.c.rollbackErr
+}
+
+type countingStmt struct{ c *countingConnector }
+
+func (s *countingStmt) Close() error {
+	s.c.closes.Add(1)
+	return nil
+}
+func (s *countingStmt) NumInput() int { return -1 }
+func (s *countingStmt) Exec([]driver.Value) (driver.Result, error) {
+	if s.c.badConns.Add(-1) >= 0 {
+		return nil, driver.ErrBadConn
+	}
+	n := s.c.execs.Add(1)
+	if n == s.c.failExec {
+		return nil, errors.New("exec failed")
+	}
+	if s.c.execResult != nil {
+		return s.c.execResult, nil
+	}
+	if int(n) <= len(s.c.execResults) {
+		return s.c.execResults[n-1], nil
+	}
+	return driver.RowsAffected(1), nil
+}
+func (s *countingStmt) Query([]driver.Value) (driver.Rows, error) {
+	if s.c.badConns.Add(-1) >= 0 {
+		return nil, driver.ErrBadConn
+	}
+	if s.c.columns != nil {
+		return &countingRows{columns: s.c.columns, rows: s.c.rows}, nil
+	}
+	return &countingRows{columns: []string{"id"}}, nil
+}
+
+type countingRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *countingRows) Columns() []string { return r.columns }
+func (r *countingRows) Close() error      { return nil }
+func (r *countingRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,208 @@
+package sqlz
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/rfberaldo/sqlz/internal/reflectutil"
+)
+
+// ErrStaleVersion is returned by [DB.Update] when the version of the row in the
+// database doesn't match the version of the struct, meaning it was modified
+// or deleted concurrently.
+var ErrStaleVersion = errors.New("sqlz: stale version, row was modified or deleted")
+
+// Update updates the row of table whose keyCol matches the row, a pointer to a struct,
+// setting every other column, which are mapped the same way as [DB.ReplaceAll].
+//
+// For optimistic concurrency, tag an integer field with the "version" option,
+// as in `db:"version,version"`: the update only matches the version of row and
+// increments it, returning [ErrStaleVersion] if no row matches, otherwise
+// the field is incremented as well:
+//
+//	// UPDATE user SET name = :name, version = version + 1 WHERE id = :id AND version = :version
+//	err := db.Update(ctx, "user", "id", &user)
+//
+// The table name is used as is, it must not come from user input.
+func (db *DB) Update(ctx context.Context, table, keyCol string, row any) error {
+	rowValue := reflect.ValueOf(row)
+	if rowValue.Kind() != reflect.Pointer || reflectutil.TypeOfAny(row) != reflectutil.Struct || rowValue.IsNil() {
+		return fmt.Errorf("sqlz: row must be a pointer to a struct, got %T", row)
+	}
+
+	query, versionIndex, err := db.base.updateQuery(table, keyCol, rowValue.Type())
+	if err != nil {
+		return err
+	}
+
+	result, err := db.Exec(ctx, query, row)
+	if err != nil {
+		return fmt.Errorf("sqlz: updating row of %s: %w", table, err)
+	}
+
+	if versionIndex == nil {
+		return nil
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("sqlz: updating row of %s: %w", table, err)
+	}
+	if affected == 0 {
+		return ErrStaleVersion
+	}
+
+	version := rowValue.Elem().FieldByIndex(versionIndex)
+	if version.CanInt() {
+		version.SetInt(version.Int() + 1)
+	} else {
+		version.SetUint(version.Uint() + 1)
+	}
+
+	return nil
+}
+
+// updateQuery returns the named UPDATE query of structType by keyCol, and the
+// index of the field tagged with [reflectutil.VersionOption], or nil if there's none.
+func (c *base) updateQuery(table, keyCol string, structType reflect.Type) (query string, versionIndex []int, err error) {
+	structType = reflectutil.Deref(structType)
+	indexByColumn := reflectutil.StructFieldMap(structType, c.structTag, "_", c.fieldNameTransformer)
+	identByIndex := make(map[string]string)
+	for ident, index := range reflectutil.StructFieldMap(structType, c.structTag, ".", c.fieldNameTransformer) {
+		identByIndex[fmt.Sprint(index)] = ident
+	}
+
+	keyIndex, ok := indexByColumn[keyCol]
+	if !ok {
+		return "", nil, fmt.Errorf("sqlz: key column not found: '%s'", keyCol)
+	}
+
+	var versionCol string
+	for column, index := range indexByColumn {
+		field := structType.FieldByIndex(index)
+		if !reflectutil.HasTagOption(field, c.structTag, reflectutil.VersionOption) {
+			continue
+		}
+		if versionIndex != nil {
+			return "", nil, fmt.Errorf("sqlz: multiple version fields in %s", structType)
+		}
+		if zero := reflect.Zero(field.Type); !zero.CanInt() && !zero.CanUint() {
+			return "", nil, fmt.Errorf("sqlz: version field must be an integer, got %s: '%s'", field.Type, column)
+		}
+		versionCol, versionIndex = column, index
+	}
+
+	columns, idents, err := c.insertColumns(structType)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var sets []string
+	for i, column := range columns {
+		if column == keyCol || column == versionCol {
+			continue
+		}
+		sets = append(sets, column+" = :"+idents[i])
+	}
+
+	where := keyCol + " = :" + identByIndex[fmt.Sprint(keyIndex)]
+	if versionIndex != nil {
+		sets = append(sets, versionCol+" = "+versionCol+" + 1")
+		where += " AND " + versionCol + " = :" + identByIndex[fmt.Sprint(versionIndex)]
+	}
+
+	if len(sets) == 0 {
+		return "", nil, fmt.Errorf("sqlz: no columns to update in %s", structType)
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, strings.Join(sets, ", "), where)
+	return query, versionIndex, nil
+}
+
+// UpdatePartial updates the row of table whose whereCol matches arg, a struct or a
+// pointer to a struct, setting only the columns of its non-zero fields, which are
+// mapped the same way as [DB.ReplaceAll], except that fields tagged with "-" are
+// skipped as well. If columns are given, exactly those are set instead, zero or not:
+//
+//	// UPDATE product SET price = :price WHERE id = :id
+//	result, err := db.UpdatePartial(ctx, "product", Product{Id: 1, Price: 9.9}, "id")
+//
+// Unlike [DB.Update], the "version" option has no special meaning.
+// The table name is used as is, it must not come from user input.
+func (db *DB) UpdatePartial(ctx context.Context, table string, arg any, whereCol string, columns ...string) (sql.Result, error) {
+	argValue := reflect.ValueOf(arg)
+	if reflectutil.TypeOfAny(arg) != reflectutil.Struct || (argValue.Kind() == reflect.Pointer && argValue.IsNil()) {
+		return nil, fmt.Errorf("sqlz: arg must be a struct or a pointer to a struct, got %T", arg)
+	}
+
+	query, err := db.base.updatePartialQuery(table, whereCol, reflect.Indirect(argValue), columns)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := db.Exec(ctx, query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("sqlz: updating row of %s: %w", table, err)
+	}
+
+	return result, nil
+}
+
+// updatePartialQuery returns the named UPDATE query of structValue by whereCol,
+// setting the given columns, or the ones of its non-zero fields if there's none.
+func (c *base) updatePartialQuery(table, whereCol string, structValue reflect.Value, columns []string) (string, error) {
+	indexByColumn := reflectutil.StructFieldMap(structValue.Type(), c.structTag, "_", c.fieldNameTransformer)
+	identByIndex := make(map[string]string)
+	for ident, index := range reflectutil.StructFieldMap(structValue.Type(), c.structTag, ".", c.fieldNameTransformer) {
+		identByIndex[fmt.Sprint(index)] = ident
+	}
+
+	whereIndex, ok := indexByColumn[whereCol]
+	if !ok {
+		return "", fmt.Errorf("sqlz: where column not found: '%s'", whereCol)
+	}
+
+	writable, idents, err := c.insertColumns(structValue.Type())
+	if err != nil {
+		return "", err
+	}
+	for _, column := range columns {
+		if !slices.Contains(writable, column) {
+			return "", fmt.Errorf("sqlz: column not found or read-only: '%s'", column)
+		}
+	}
+
+	var sets []string
+	for i, column := range writable {
+		if column == whereCol || isDashTagged(structValue.Type(), indexByColumn[column], c.structTag) {
+			continue
+		}
+		if len(columns) > 0 {
+			if !slices.Contains(columns, column) {
+				continue
+			}
+		} else if field, err := structValue.FieldByIndexErr(indexByColumn[column]); err != nil || field.IsZero() {
+			continue
+		}
+		sets = append(sets, column+" = :"+idents[i])
+	}
+
+	if len(sets) == 0 {
+		return "", fmt.Errorf("sqlz: no columns to update in %s", structValue.Type())
+	}
+
+	where := whereCol + " = :" + identByIndex[fmt.Sprint(whereIndex)]
+	return fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, strings.Join(sets, ", "), where), nil
+}
+
+// isDashTagged reports whether the field at index is tagged with "-" as name.
+// It's still mapped when scanning, but never written by [DB.UpdatePartial].
+func isDashTagged(t reflect.Type, index []int, tag string) bool {
+	name, _, _ := strings.Cut(t.FieldByIndex(index).Tag.Get(tag), ",")
+	return name == "-"
+}
@@ -0,0 +1,244 @@
+package sqlz
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDB_Update(t *testing.T) {
+	type Product struct {
+		Id      int `db:"id,readonly"`
+		Name    string
+		Version int `db:"version,version"`
+	}
+
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, nil)
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := db.Exec(ctx, th.fmt(`CREATE TABLE %s (id INT PRIMARY KEY, name VARCHAR(100), version INT NOT NULL)`))
+		require.NoError(t, err)
+
+		_, err = db.Exec(ctx, th.fmt(`INSERT INTO %s (id, name, version) VALUES (1, 'Pen', 1)`))
+		require.NoError(t, err)
+
+		selectOne := th.fmt(`SELECT id, name, version FROM %s WHERE id = 1`)
+
+		t.Run("increments version", func(t *testing.T) {
+			var product Product
+			require.NoError(t, db.QueryRow(ctx, selectOne).Scan(&product))
+
+			product.Name = "Pencil"
+			err := db.Update(ctx, th.tableName, "id", &product)
+			require.NoError(t, err)
+			assert.Equal(t, 2, product.Version)
+
+			var got Product
+			require.NoError(t, db.QueryRow(ctx, selectOne).Scan(&got))
+			assert.Equal(t, Product{1, "Pencil", 2}, got)
+		})
+
+		t.Run("stale version", func(t *testing.T) {
+			stale := Product{Id: 1, Name: "Marker", Version: 1}
+			err := db.Update(ctx, th.tableName, "id", &stale)
+			require.ErrorIs(t, err, ErrStaleVersion)
+			assert.Equal(t, 1, stale.Version)
+
+			var got Product
+			require.NoError(t, db.QueryRow(ctx, selectOne).Scan(&got))
+			assert.Equal(t, Product{1, "Pencil", 2}, got)
+		})
+	})
+}
+
+func TestDB_Update_mock(t *testing.T) {
+	pool := sql.OpenDB(&countingConnector{})
+	t.Cleanup(func() { pool.Close() })
+	db := New("mock", pool, &Options{Bind: BindQuestion})
+
+	t.Run("increments version", func(t *testing.T) {
+		product := struct {
+			Id      int
+			Name    string
+			Version uint16 `db:"version,version"`
+		}{1, "Pen", 7}
+		err := db.Update(ctx, "product", "id", &product)
+		require.NoError(t, err)
+		assert.Equal(t, uint16(8), product.Version)
+	})
+
+	t.Run("validate row", func(t *testing.T) {
+		product := struct{ Id, Name string }{"1", "Pen"}
+		err := db.Update(ctx, "product", "id", product)
+		assert.ErrorContains(t, err, "row must be a pointer to a struct")
+
+		err = db.Update(ctx, "product", "id", &[]int{1})
+		assert.ErrorContains(t, err, "row must be a pointer to a struct")
+
+		err = db.Update(ctx, "product", "code", &product)
+		assert.ErrorContains(t, err, "key column not found: 'code'")
+	})
+}
+
+func TestBase_updateQuery(t *testing.T) {
+	type Audit struct {
+		UpdatedBy string
+	}
+
+	t.Run("with version", func(t *testing.T) {
+		type Product struct {
+			Id      int    `db:"id,readonly"`
+			Name    string `db:"name"`
+			Version int64  `db:"row_version,version"`
+			Audit
+		}
+
+		base := newBase(nil)
+		query, versionIndex, err := base.updateQuery("product", "id", reflect.TypeFor[*Product]())
+		require.NoError(t, err)
+		assert.Equal(t,
+			"UPDATE product SET name = :name, updated_by = :updated_by, row_version = row_version + 1 "+
+				"WHERE id = :id AND row_version = :row_version",
+			query,
+		)
+		assert.Equal(t, []int{2}, versionIndex)
+	})
+
+	t.Run("without version", func(t *testing.T) {
+		type Product struct {
+			Code  string
+			Name  string
+			Price float64
+		}
+
+		base := newBase(nil)
+		query, versionIndex, err := base.updateQuery("product", "code", reflect.TypeFor[Product]())
+		require.NoError(t, err)
+		assert.Equal(t, "UPDATE product SET name = :name, price = :price WHERE code = :code", query)
+		assert.Nil(t, versionIndex)
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		type Product struct {
+			Id      int
+			Version string `db:"version,version"`
+		}
+
+		base := newBase(nil)
+		_, _, err := base.updateQuery("product", "id", reflect.TypeFor[Product]())
+		assert.ErrorContains(t, err, "version field must be an integer, got string: 'version'")
+	})
+
+	t.Run("no columns", func(t *testing.T) {
+		type Product struct {
+			Id int
+		}
+
+		base := newBase(nil)
+		_, _, err := base.updateQuery("product", "id", reflect.TypeFor[Product]())
+		assert.ErrorContains(t, err, "no columns to update")
+	})
+}
+
+func TestBase_updatePartialQuery(t *testing.T) {
+	type Audit struct {
+		UpdatedBy string
+	}
+
+	type Product struct {
+		Id        int    `db:"id"`
+		Name      string `db:"name"`
+		Price     float64
+		Internal  string    `db:"-"`
+		CreatedAt time.Time `db:"created_at,readonly"`
+		*Audit
+	}
+
+	base := newBase(nil)
+
+	t.Run("non-zero fields", func(t *testing.T) {
+		product := Product{Id: 1, Price: 9.9, Internal: "x", CreatedAt: time.Now()}
+		query, err := base.updatePartialQuery("product", "id", reflect.ValueOf(product), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "UPDATE product SET price = :price WHERE id = :id", query)
+
+		product = Product{Id: 1, Name: "Pen", Audit: &Audit{"alice"}}
+		query, err = base.updatePartialQuery("product", "id", reflect.ValueOf(product), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "UPDATE product SET name = :name, updated_by = :updated_by WHERE id = :id", query)
+	})
+
+	t.Run("given columns", func(t *testing.T) {
+		product := Product{Id: 1, Price: 9.9}
+		query, err := base.updatePartialQuery("product", "id", reflect.ValueOf(product), []string{"name", "price"})
+		require.NoError(t, err)
+		assert.Equal(t, "UPDATE product SET name = :name, price = :price WHERE id = :id", query)
+
+		_, err = base.updatePartialQuery("product", "id", reflect.ValueOf(product), []string{"created_at"})
+		assert.ErrorContains(t, err, "column not found or read-only: 'created_at'")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := base.updatePartialQuery("product", "code", reflect.ValueOf(Product{Id: 1}), nil)
+		assert.ErrorContains(t, err, "where column not found: 'code'")
+
+		_, err = base.updatePartialQuery("product", "id", reflect.ValueOf(Product{Id: 1}), nil)
+		assert.ErrorContains(t, err, "no columns to update")
+	})
+}
+
+func TestDB_UpdatePartial_mock(t *testing.T) {
+	connector := &countingConnector{}
+	pool := sql.OpenDB(connector)
+	t.Cleanup(func() { pool.Close() })
+	db := New("mock", pool, &Options{Bind: BindQuestion})
+
+	type Product struct {
+		Id   int
+		Name string
+	}
+
+	result, err := db.UpdatePartial(ctx, "product", &Product{1, "Pen"}, "id")
+	require.NoError(t, err)
+	affected, err := result.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+	assert.Equal(t, int32(1), connector.execs.Load())
+
+	_, err = db.UpdatePartial(ctx, "product", []Product{{1, "Pen"}}, "id")
+	assert.ErrorContains(t, err, "arg must be a struct or a pointer to a struct")
+
+	_, err = db.UpdatePartial(ctx, "product", (*Product)(nil), "id")
+	assert.ErrorContains(t, err, "arg must be a struct or a pointer to a struct")
+}
+
+func TestDB_UpdatePartial(t *testing.T) {
+	type Product struct {
+		Id    int
+		Name  string
+		Price float64
+	}
+
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, nil)
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := db.Exec(ctx, th.fmt(`CREATE TABLE %s (id INT PRIMARY KEY, name VARCHAR(100), price DECIMAL(10,2))`))
+		require.NoError(t, err)
+
+		_, err = db.Exec(ctx, th.fmt(`INSERT INTO %s (id, name, price) VALUES (1, 'Pen', 1.5)`))
+		require.NoError(t, err)
+
+		_, err = db.UpdatePartial(ctx, th.tableName, Product{Id: 1, Price: 2.5}, "id")
+		require.NoError(t, err)
+
+		var got Product
+		require.NoError(t, db.QueryRow(ctx, th.fmt(`SELECT id, name, price FROM %s WHERE id = 1`)).Scan(&got))
+		assert.Equal(t, Product{1, "Pen", 2.5}, got)
+	})
+}
@@ -3,14 +3,21 @@ package sqlz
 import (
 	"database/sql"
 	"database/sql/driver"
+	"encoding"
 	"errors"
 	"fmt"
+	"net"
+	"net/netip"
 	"reflect"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 	"unicode"
 	"unicode/utf8"
 
 	"github.com/rfberaldo/sqlz/internal/parser"
+	"github.com/rfberaldo/sqlz/internal/reflectutil"
 )
 
 var (
@@ -20,6 +27,26 @@ var (
 	// valuerType is [reflect.Type] of [driver.Valuer]
 	valuerType = reflect.TypeFor[driver.Valuer]()
 
+	// timeType is [reflect.Type] of [time.Time]
+	timeType = reflect.TypeFor[time.Time]()
+
+	// textMarshalerType is [reflect.Type] of [encoding.TextMarshaler]
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+
+	// ipTypes are the IP address types bound and scanned by their text form
+	ipTypes = []reflect.Type{
+		reflect.TypeFor[netip.Addr](),
+		reflect.TypeFor[netip.Prefix](),
+		reflect.TypeFor[net.IP](),
+	}
+
+	anyType      = reflect.TypeFor[any]()
+	rowMapType   = reflect.TypeFor[map[string]any]()
+	bytesType    = reflect.TypeFor[[]byte]()
+	rawBytesType = reflect.TypeFor[sql.RawBytes]()
+
+	// bindByDriverName is guarded by bindMu, see [RegisterBind]
+	bindMu           sync.RWMutex
 	bindByDriverName = map[string]parser.Bind{
 		"azuresql":         parser.BindAt,
 		"sqlserver":        parser.BindAt,
@@ -39,6 +66,13 @@ var (
 		"nrsqlite3":        parser.BindQuestion,
 		"sqlite3":          parser.BindQuestion,
 	}
+
+	// firstInsertIdDrivers are the drivers whose [sql.Result.LastInsertId] of
+	// a multi-row insert is the id of the first row, rather than the last one.
+	firstInsertIdDrivers = map[string]bool{
+		"mysql":   true,
+		"nrmysql": true,
+	}
 )
 
 // assertMap validates if arg is a map[string]any.
@@ -50,6 +84,39 @@ func assertMap(arg any) (map[string]any, error) {
 	return m, nil
 }
 
+// namedAtArgs wraps args in [sql.NamedArg] by the names of the [BindNamedAt]
+// placeholders of query, in order; repeated names are bound once, by their first arg.
+func namedAtArgs(query string, args []any) ([]any, error) {
+	names := parser.PlaceholderNames(query)
+	if len(names) != len(args) {
+		return nil, fmt.Errorf(
+			"sqlz: arguments mismatch binding named placeholders: placeholders %d arguments %d",
+			len(names), len(args),
+		)
+	}
+
+	seen := make(map[string]bool, len(names))
+	namedArgs := make([]any, 0, len(args))
+	for i, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		namedArgs = append(namedArgs, sql.Named(name, args[i]))
+	}
+
+	return namedArgs, nil
+}
+
+// assertSlicePtr validates if dest is a non-nil pointer to a slice.
+func assertSlicePtr(dest any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("sqlz: destination must be a non-nil pointer to a slice, got %T", dest)
+	}
+	return nil
+}
+
 // getMapValue recursively find the map value of a dot notation key string.
 func getMapValue(key string, m map[string]any) (any, bool) {
 	if !strings.Contains(key, ".") {
@@ -71,11 +138,235 @@ func getMapValue(key string, m map[string]any) (any, bool) {
 	return getMapValue(splits[1], nestedMap)
 }
 
+// timeToUTC returns arg converted to UTC if it's a [time.Time] or a non-nil
+// pointer to one, otherwise arg is returned unchanged.
+func timeToUTC(arg any) any {
+	switch t := arg.(type) {
+	case time.Time:
+		return t.UTC()
+	case *time.Time:
+		if t != nil {
+			return t.UTC()
+		}
+	}
+	return arg
+}
+
+// timesToUTC applies [timeToUTC] to every arg, the input slice is not modified.
+func timesToUTC(args []any) []any {
+	out := make([]any, len(args))
+	for i, arg := range args {
+		out[i] = timeToUTC(arg)
+	}
+	return out
+}
+
+// isIPType reports whether t, or the type it points to, is one of [ipTypes].
+func isIPType(t reflect.Type) bool {
+	return slices.Contains(ipTypes, reflectutil.Deref(t))
+}
+
+// ipToText returns arg in its text form if it's one of [ipTypes] or a pointer
+// to one, as not every driver supports them, e.g. "192.168.0.1" or "10.0.0.0/8",
+// reporting whether it is; the zero value and nil pointers are returned as nil, meaning NULL.
+func ipToText(arg any) (any, bool) {
+	var ip fmt.Stringer
+	switch v := arg.(type) {
+	case netip.Addr:
+		if v.IsValid() {
+			ip = v
+		}
+	case *netip.Addr:
+		if v != nil && v.IsValid() {
+			ip = v
+		}
+	case netip.Prefix:
+		if v.IsValid() {
+			ip = v
+		}
+	case *netip.Prefix:
+		if v != nil && v.IsValid() {
+			ip = v
+		}
+	case net.IP:
+		if v != nil {
+			ip = v
+		}
+	case *net.IP:
+		if v != nil && *v != nil {
+			ip = v
+		}
+	default:
+		return arg, false
+	}
+
+	if ip == nil {
+		return nil, true
+	}
+	return ip.String(), true
+}
+
+// ipsToText applies [ipToText] to every arg, the input slice is not modified.
+func ipsToText(args []any) []any {
+	var out []any
+	for i, arg := range args {
+		text, ok := ipToText(arg)
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = slices.Clone(args)
+		}
+		out[i] = text
+	}
+	if out == nil {
+		return args
+	}
+	return out
+}
+
+// stripColumnMap removes the [WithColumnMap] marker from args, returning its map.
+func stripColumnMap(args []any) ([]any, map[string]string) {
+	idx := slices.IndexFunc(args, func(arg any) bool {
+		_, ok := arg.(columnMap)
+		return ok
+	})
+	if idx == -1 {
+		return args, nil
+	}
+
+	return slices.Delete(slices.Clone(args), idx, idx+1), args[idx].(columnMap)
+}
+
+// stripStructTag removes the [WithStructTag] marker from args, returning its tag.
+func stripStructTag(args []any) ([]any, string) {
+	idx := slices.IndexFunc(args, func(arg any) bool {
+		_, ok := arg.(structTagOverride)
+		return ok
+	})
+	if idx == -1 {
+		return args, ""
+	}
+
+	return slices.Delete(slices.Clone(args), idx, idx+1), string(args[idx].(structTagOverride))
+}
+
+// stripAllowNoWhere removes any [AllowNoWhere] marker from args, reporting
+// whether it was found, the input slice is not modified.
+func stripAllowNoWhere(args []any) ([]any, bool) {
+	idx := slices.IndexFunc(args, func(arg any) bool {
+		_, ok := arg.(allowNoWhere)
+		return ok
+	})
+	if idx == -1 {
+		return args, false
+	}
+
+	out := make([]any, 0, len(args)-1)
+	for _, arg := range args {
+		if _, ok := arg.(allowNoWhere); !ok {
+			out = append(out, arg)
+		}
+	}
+	return out, true
+}
+
+// isValuerArg reports whether arg, or the elements of arg if it's a slice,
+// implements [driver.Valuer].
+func isValuerArg(arg any) bool {
+	t := reflect.TypeOf(arg)
+	if t == nil {
+		return false
+	}
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t.Implements(valuerType)
+}
+
+// marshalText returns the text form of v if it implements [encoding.TextMarshaler],
+// reporting whether it does; [time.Time] is not considered, drivers support it natively.
+// The caller must check [driver.Valuer] first, as it takes precedence.
+func marshalText(v reflect.Value) (string, bool, error) {
+	if !v.IsValid() || v.Type() == timeType {
+		return "", false, nil
+	}
+
+	var m encoding.TextMarshaler
+	switch {
+	case v.Type().Implements(textMarshalerType):
+		m = v.Interface().(encoding.TextMarshaler)
+	case v.CanAddr() && v.Addr().Type().Implements(textMarshalerType):
+		m = v.Addr().Interface().(encoding.TextMarshaler)
+	default:
+		return "", false, nil
+	}
+
+	text, err := m.MarshalText()
+	if err != nil {
+		return "", true, fmt.Errorf("marshaling text: %w", err)
+	}
+	return string(text), true, nil
+}
+
+// evalLazy returns the result of calling value if it's a func() (any, error),
+// which allows named args to be computed at bind time, otherwise value is returned as is.
+func evalLazy(value any) (any, error) {
+	fn, ok := value.(func() (any, error))
+	if !ok {
+		return value, nil
+	}
+
+	if fn == nil {
+		return nil, nil
+	}
+
+	result, err := fn()
+	if err != nil {
+		return nil, fmt.Errorf("evaluating func value: %w", err)
+	}
+	return result, nil
+}
+
 // IsNotFound is a helper to check if err contains [sql.ErrNoRows].
 func IsNotFound(err error) bool {
 	return errors.Is(err, sql.ErrNoRows)
 }
 
+// likeEscaper escapes the LIKE wildcards and the escape character itself.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// EscapeLike escapes the wildcards "%" and "_", and the escape character "\",
+// so that s is matched literally in a LIKE pattern, which prevents users from
+// injecting wildcards. Add your own wildcards after escaping:
+//
+//	db.Query(ctx, `SELECT * FROM user WHERE name LIKE :pattern ESCAPE '\'`,
+//		map[string]any{"pattern": sqlz.EscapeLike(input) + "%"})
+//
+// Backslash is the default escape character in MySQL and PostgreSQL, so the
+// ESCAPE clause is optional there, other databases require it. MySQL also
+// escapes backslashes inside literals, so the clause is written ESCAPE '\\'.
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// isPlainIdent reports whether s is a non-empty SQL identifier of ASCII letters,
+// digits and underscores, not starting with a digit, safe to be used unquoted.
+func isPlainIdent(s string) bool {
+	for i, ch := range []byte(s) {
+		isLetter := ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch == '_'
+		isDigit := ch >= '0' && ch <= '9'
+		if !isLetter && (!isDigit || i == 0) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// KeepFieldName returns s unchanged, set it as [Options.FieldNameTransformer]
+// to map struct fields without a tag by their name verbatim, e.g. "UserName".
+func KeepFieldName(s string) string { return s }
+
 // ToSnakeCase transforms a string to snake case.
 func ToSnakeCase(s string) string {
 	var sb strings.Builder
@@ -4,9 +4,13 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"net"
+	"net/netip"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetMapValue(t *testing.T) {
@@ -83,6 +87,82 @@ func TestNotFound(t *testing.T) {
 	assert.Equal(t, true, IsNotFound(err))
 }
 
+func TestIsPlainIdent(t *testing.T) {
+	for _, s := range []string{"a", "sp1", "_tmp", "Before_Update"} {
+		assert.True(t, isPlainIdent(s), s)
+	}
+	for _, s := range []string{"", "1sp", "sp-1", "sp 1", "sp;DROP TABLE user", `"sp"`, "ação"} {
+		assert.False(t, isPlainIdent(s), s)
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"alice", "alice"},
+		{"50%", `50\%`},
+		{"user_name", `user\_name`},
+		{`C:\temp`, `C:\\temp`},
+		{`%_\`, `\%\_\\`},
+		{`\%`, `\\\%`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, EscapeLike(tt.input))
+		})
+	}
+}
+
+func TestEscapeLike_query(t *testing.T) {
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, nil)
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		_, err := db.Exec(ctx, th.fmt(`CREATE TABLE IF NOT EXISTS %s (name VARCHAR(255))`))
+		require.NoError(t, err)
+
+		names := []map[string]any{
+			{"name": "50% off"}, {"name": "500 off"},
+			{"name": "a_b"}, {"name": "axb"},
+			{"name": `C:\x`}, {"name": "C:x"},
+		}
+		_, err = db.Exec(ctx, th.fmt(`INSERT INTO %s (name) VALUES (:name)`), names)
+		require.NoError(t, err)
+
+		// backslash is the default escape character of both MySQL and PostgreSQL
+		query := th.fmt(`SELECT name FROM %s WHERE name LIKE :pattern ORDER BY name`)
+		tests := []struct {
+			pattern  string
+			expected []string
+		}{
+			{EscapeLike("50%") + "%", []string{"50% off"}},
+			{EscapeLike("a_b"), []string{"a_b"}},
+			{EscapeLike(`C:\`) + "%", []string{`C:\x`}},
+		}
+
+		for _, tt := range tests {
+			var got []string
+			err := db.Query(ctx, query, map[string]any{"pattern": tt.pattern}).Scan(&got)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		}
+	})
+}
+
+func TestKeepFieldName(t *testing.T) {
+	assert.Equal(t, "UserName", KeepFieldName("UserName"))
+
+	cfg := applyDefaults(&config{fieldNameTransformer: KeepFieldName})
+	assert.Equal(t, "UserName", cfg.fieldNameTransformer("UserName"))
+
+	cfg = applyDefaults(&config{})
+	assert.Equal(t, "user_name", cfg.fieldNameTransformer("UserName"))
+}
+
 func TestToSnakeCase(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -116,3 +196,32 @@ func TestToSnakeCase(t *testing.T) {
 		})
 	}
 }
+
+func TestTimesToUTC(t *testing.T) {
+	loc := time.FixedZone("UTC-3", -3*60*60)
+	ts := time.Date(2025, 9, 29, 12, 0, 0, 0, loc)
+	var nilTime *time.Time
+
+	args := []any{ts, &ts, nilTime, "text", 42}
+	got := timesToUTC(args)
+
+	assert.Equal(t, []any{ts.UTC(), ts.UTC(), nilTime, "text", 42}, got)
+	assert.Equal(t, ts, args[0], "input must not be modified")
+}
+
+func TestIPsToText(t *testing.T) {
+	addr := netip.MustParseAddr("192.168.0.1")
+	prefix := netip.MustParsePrefix("10.0.0.0/8")
+	ip := net.ParseIP("::1")
+	var nilAddr *netip.Addr
+
+	args := []any{addr, &addr, prefix, &prefix, ip, &ip, netip.Addr{}, net.IP(nil), nilAddr, "text", 42}
+	got := ipsToText(args)
+
+	expect := []any{"192.168.0.1", "192.168.0.1", "10.0.0.0/8", "10.0.0.0/8", "::1", "::1", nil, nil, nil, "text", 42}
+	assert.Equal(t, expect, got)
+	assert.Equal(t, addr, args[0], "input must not be modified")
+
+	noIPs := []any{"text", 42}
+	assert.Equal(t, noIPs, ipsToText(noIPs))
+}
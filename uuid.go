@@ -0,0 +1,106 @@
+package sqlz
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+)
+
+// UUID is a universally unique identifier, it's scanned from both its text
+// form, e.g. PostgreSQL "uuid" or MySQL "CHAR(36)" columns, and its 16-byte
+// binary form, e.g. MySQL "BINARY(16)" columns. It's bound in text form,
+// use [BinaryUUID] to bind the binary form.
+type UUID [16]byte
+
+// BinaryUUID is a [UUID] bound in its 16-byte binary form, e.g. for MySQL
+// "BINARY(16)" columns. It's scanned from both forms, just like [UUID].
+type BinaryUUID UUID
+
+// ParseUUID parses s in the canonical form "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx",
+// or without hyphens, case insensitive.
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+
+	switch len(s) {
+	case 32:
+	case 36:
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return u, fmt.Errorf("sqlz: invalid UUID: '%s'", s)
+		}
+		s = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	default:
+		return u, fmt.Errorf("sqlz: invalid UUID length %d: '%s'", len(s), s)
+	}
+
+	if _, err := hex.Decode(u[:], []byte(s)); err != nil {
+		return u, fmt.Errorf("sqlz: invalid UUID: %w", err)
+	}
+
+	return u, nil
+}
+
+// String returns u in the canonical form, e.g. "9ef2c4f6-5a1b-4d3e-8c7f-0a1b2c3d4e5f".
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// IsZero reports whether u is the zero UUID.
+func (u UUID) IsZero() bool { return u == UUID{} }
+
+// Value implements [driver.Valuer], binding u in text form.
+func (u UUID) Value() (driver.Value, error) { return u.String(), nil }
+
+// Scan implements [sql.Scanner], from both the text and the 16-byte binary forms.
+func (u *UUID) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		if len(v) == len(u) {
+			copy(u[:], v)
+			return nil
+		}
+		return u.UnmarshalText(v)
+
+	case string:
+		return u.UnmarshalText([]byte(v))
+	}
+
+	return fmt.Errorf("sqlz: cannot scan %T into UUID", src)
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+func (u UUID) MarshalText() ([]byte, error) { return []byte(u.String()), nil }
+
+// UnmarshalText implements [encoding.TextUnmarshaler], see [ParseUUID].
+func (u *UUID) UnmarshalText(text []byte) error {
+	parsed, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// String returns u in the canonical form, see [UUID.String].
+func (u BinaryUUID) String() string { return UUID(u).String() }
+
+// Value implements [driver.Valuer], binding u in 16-byte binary form.
+func (u BinaryUUID) Value() (driver.Value, error) { return u[:], nil }
+
+// Scan implements [sql.Scanner], see [UUID.Scan].
+func (u *BinaryUUID) Scan(src any) error { return (*UUID)(u).Scan(src) }
+
+// MarshalText implements [encoding.TextMarshaler].
+func (u BinaryUUID) MarshalText() ([]byte, error) { return UUID(u).MarshalText() }
+
+// UnmarshalText implements [encoding.TextUnmarshaler], see [ParseUUID].
+func (u *BinaryUUID) UnmarshalText(text []byte) error { return (*UUID)(u).UnmarshalText(text) }
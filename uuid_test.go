@@ -0,0 +1,166 @@
+package sqlz
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rfberaldo/sqlz/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testUUID = "9ef2c4f6-5a1b-4d3e-8c7f-0a1b2c3d4e5f"
+
+var testUUIDBytes = []byte{
+	0x9e, 0xf2, 0xc4, 0xf6, 0x5a, 0x1b, 0x4d, 0x3e,
+	0x8c, 0x7f, 0x0a, 0x1b, 0x2c, 0x3d, 0x4e, 0x5f,
+}
+
+func TestParseUUID(t *testing.T) {
+	for _, input := range []string{
+		testUUID,
+		"9EF2C4F6-5A1B-4D3E-8C7F-0A1B2C3D4E5F",
+		"9ef2c4f65a1b4d3e8c7f0a1b2c3d4e5f",
+	} {
+		u, err := ParseUUID(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, testUUIDBytes, u[:])
+		assert.Equal(t, testUUID, u.String())
+	}
+
+	_, err := ParseUUID("9ef2c4f6")
+	assert.ErrorContains(t, err, "invalid UUID length 8")
+
+	_, err = ParseUUID("9ef2c4f6_5a1b_4d3e_8c7f_0a1b2c3d4e5f")
+	assert.ErrorContains(t, err, "invalid UUID")
+
+	_, err = ParseUUID("zef2c4f6-5a1b-4d3e-8c7f-0a1b2c3d4e5f")
+	assert.ErrorContains(t, err, "invalid UUID")
+}
+
+func TestUUID_Scan(t *testing.T) {
+	expected, err := ParseUUID(testUUID)
+	require.NoError(t, err)
+
+	for _, src := range []any{testUUID, []byte(testUUID), testUUIDBytes} {
+		var u UUID
+		require.NoError(t, u.Scan(src))
+		assert.Equal(t, expected, u)
+
+		var b BinaryUUID
+		require.NoError(t, b.Scan(src))
+		assert.Equal(t, BinaryUUID(expected), b)
+	}
+
+	var u UUID
+	assert.ErrorContains(t, u.Scan(nil), "cannot scan <nil> into UUID")
+	assert.ErrorContains(t, u.Scan(int64(1)), "cannot scan int64 into UUID")
+}
+
+func TestUUID_Value(t *testing.T) {
+	u, err := ParseUUID(testUUID)
+	require.NoError(t, err)
+
+	v, err := u.Value()
+	require.NoError(t, err)
+	assert.Equal(t, testUUID, v)
+
+	v, err = BinaryUUID(u).Value()
+	require.NoError(t, err)
+	assert.Equal(t, testUUIDBytes, v)
+
+	data, err := json.Marshal(map[string]any{"id": u, "bin": BinaryUUID(u)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id": "`+testUUID+`", "bin": "`+testUUID+`"}`, string(data))
+
+	assert.True(t, UUID{}.IsZero())
+	assert.False(t, u.IsZero())
+}
+
+func TestUUID_bindAndScan(t *testing.T) {
+	u, err := ParseUUID(testUUID)
+	require.NoError(t, err)
+
+	t.Run("named", func(t *testing.T) {
+		arg := struct {
+			Id  UUID
+			Bin BinaryUUID
+		}{u, BinaryUUID(u)}
+		_, args, err := processNamed("INSERT INTO t (id, bin) VALUES (:id, :bin)", arg, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []any{u, BinaryUUID(u)}, args)
+	})
+
+	t.Run("native", func(t *testing.T) {
+		base := newBase(&config{bind: parser.BindQuestion})
+		query, args, err := base.resolveQuery("SELECT * FROM t WHERE id = ?", []any{u})
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM t WHERE id = ?", query)
+		assert.Equal(t, []any{u}, args)
+
+		query, args, err = base.resolveQuery("SELECT * FROM t WHERE id IN (?)", []any{[]UUID{u, u}})
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM t WHERE id IN (?,?)", query)
+		assert.Equal(t, []any{u, u}, args)
+	})
+
+	t.Run("scan", func(t *testing.T) {
+		newRows := func(src any) *mockRows {
+			row := -1
+			return &mockRows{
+				ColumnsFunc: func() ([]string, error) { return []string{"id"}, nil },
+				NextFunc: func() bool {
+					row++
+					return row < 1
+				},
+				ScanFunc: func(dest ...any) error {
+					return dest[0].(interface{ Scan(any) error }).Scan(src)
+				},
+			}
+		}
+
+		var got UUID
+		require.NoError(t, newRowScanner(newRows(testUUID), nil).Scan(&got))
+		assert.Equal(t, u, got)
+
+		var all []BinaryUUID
+		require.NoError(t, newScanner(newRows(testUUIDBytes), nil).Scan(&all))
+		assert.Equal(t, []BinaryUUID{BinaryUUID(u)}, all)
+	})
+}
+
+func TestUUID_roundTrip(t *testing.T) {
+	type Session struct {
+		Id    UUID
+		Token BinaryUUID
+	}
+
+	runConn(t, func(t *testing.T, conn *Conn) {
+		db := New(conn.driverName, conn.db, nil)
+		th := newTableHelper(t, conn.db, conn.bind)
+
+		create := `CREATE TABLE %s (id CHAR(36) PRIMARY KEY, token BINARY(16))`
+		if conn.bind == parser.BindDollar {
+			create = `CREATE TABLE %s (id UUID PRIMARY KEY, token BYTEA)`
+		}
+		_, err := db.Exec(ctx, th.fmt(create))
+		require.NoError(t, err)
+
+		id, err := ParseUUID(testUUID)
+		require.NoError(t, err)
+		session := Session{id, BinaryUUID(id)}
+
+		_, err = db.Exec(ctx, th.fmt(`INSERT INTO %s (id, token) VALUES (:id, :token)`), session)
+		require.NoError(t, err)
+
+		var got Session
+		err = db.QueryRow(ctx, th.fmt(`SELECT id, token FROM %s WHERE id = ?`), id).Scan(&got)
+		require.NoError(t, err)
+		assert.Equal(t, session, got)
+
+		var ids []UUID
+		err = db.Query(ctx, th.fmt(`SELECT id FROM %s WHERE id IN (?)`), []UUID{id}).Scan(&ids)
+		require.NoError(t, err)
+		assert.Equal(t, []UUID{id}, ids)
+	})
+}